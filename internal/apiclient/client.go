@@ -0,0 +1,68 @@
+package apiclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/cassette"
+)
+
+// The API requests are sent to, e.g. a difftest.Target for an in-process router or a remote URL
+type Target interface {
+	Do(ctx context.Context, req cassette.Request) (cassette.Response, error)
+}
+
+type TargetFunc func(ctx context.Context, req cassette.Request) (cassette.Response, error)
+
+func (f TargetFunc) Do(ctx context.Context, req cassette.Request) (cassette.Response, error) {
+	return f(ctx, req)
+}
+
+var uniqueCounter int64
+
+// Names are unique across runs, entities left by earlier runs against a DB that is not recreated do not clash with new ones
+func Unique(prefix string) string {
+	return prefix + "-" + strconv.FormatInt(time.Now().UnixNano()+atomic.AddInt64(&uniqueCounter, 1), 36)
+}
+
+// Creates an entity and returns its id
+func Create(ctx context.Context, target Target, token string, path string, body map[string]any) (int, error) {
+	resp, err := Expect(ctx, target, http.MethodPost, path, token, body, http.StatusCreated)
+	if err != nil {
+		return 0, err
+	}
+	id, err := strconv.Atoi(resp)
+	if err != nil {
+		return 0, fmt.Errorf("POST %s returned '%s' instead of an id", path, resp)
+	}
+	return id, nil
+}
+
+// Sends the request and returns the response body if the status is the expected one
+func Expect(ctx context.Context, target Target, method string, path string, token string, body any, status int) (string, error) {
+	req := cassette.Request{Method: method, Url: path, Header: http.Header{}}
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return "", err
+		}
+		req.Body = string(data)
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := target.Do(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	if resp.Status != status {
+		return "", fmt.Errorf("%s %s returned %d instead of %d: %s", method, path, resp.Status, status, resp.Body)
+	}
+	return resp.Body, nil
+}
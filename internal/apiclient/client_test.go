@@ -0,0 +1,47 @@
+package apiclient
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/cassette"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreate(t *testing.T) {
+	var sent cassette.Request
+	target := TargetFunc(func(ctx context.Context, req cassette.Request) (cassette.Response, error) {
+		sent = req
+		switch req.Url {
+		case "/tags":
+			return cassette.Response{Status: http.StatusCreated, Body: "42"}, nil
+		case "/notes":
+			return cassette.Response{Status: http.StatusCreated, Body: `{"Id": 1}`}, nil
+		}
+		return cassette.Response{Status: http.StatusBadRequest, Body: "unknown state"}, nil
+	})
+
+	id, err := Create(context.Background(), target, "token", "/tags", map[string]any{"Name": "books"})
+	assert.Nil(t, err)
+	assert.Equal(t, 42, id)
+	assert.Equal(t, http.MethodPost, sent.Method)
+	assert.Equal(t, `{"Name":"books"}`, sent.Body)
+	assert.Equal(t, "Bearer token", sent.Header.Get("Authorization"))
+	assert.Equal(t, "application/json", sent.Header.Get("Content-Type"))
+
+	_, err = Create(context.Background(), target, "", "/notes", map[string]any{})
+	assert.EqualError(t, err, `POST /notes returned '{"Id": 1}' instead of an id`)
+	assert.Empty(t, sent.Header.Get("Authorization"))
+
+	_, err = Create(context.Background(), target, "", "/users", map[string]any{})
+	assert.EqualError(t, err, "POST /users returned 400 instead of 201: unknown state")
+}
+
+func TestUnique(t *testing.T) {
+	a, b := Unique("monitor-tag"), Unique("monitor-tag")
+
+	assert.NotEqual(t, a, b)
+	assert.True(t, strings.HasPrefix(a, "monitor-tag-"))
+}
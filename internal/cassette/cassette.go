@@ -0,0 +1,124 @@
+package cassette
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/pathname"
+)
+
+// Version of the file format, files written by a newer format are rejected
+const FORMAT_VERSION int = 1
+
+const DEFAULT_API_VERSION string = "dev"
+
+const (
+	MODE_OFF    string = "off"
+	MODE_RECORD string = "record"
+	MODE_REPLAY string = "replay"
+)
+
+func GetPossibleModes() []string {
+	return []string{MODE_OFF, MODE_RECORD, MODE_REPLAY}
+}
+
+type Request struct {
+	Method string
+	Url    string
+	Header http.Header `json:",omitempty"`
+	Body   string
+}
+
+// Matching ignores headers, they carry tokens that differ between runs
+func (p Request) Key() string {
+	return p.Method + " " + p.Url + " " + p.Body
+}
+
+func (p Request) String() string {
+	return p.Method + " " + p.Url
+}
+
+type Response struct {
+	Status int
+	Body   string
+}
+
+type Interaction struct {
+	// Full name of the (sub)test that made the request
+	Test       string
+	Request    Request
+	Response   Response
+	Duration   time.Duration
+	RecordedAt time.Time
+}
+
+// Exchanges of one top level test against one version of the API
+type Cassette struct {
+	FormatVersion int
+	ApiVersion    string
+	Name          string
+	RecordedAt    time.Time
+	Interactions  []Interaction
+}
+
+func Path(dir string, apiVersion string, name string) string {
+	return filepath.Join(dir, pathname.Slug(apiVersion), pathname.Slug(name)+".json")
+}
+
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read cassette '%s': %v", path, err)
+	}
+	var result Cassette
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("unable to parse cassette '%s': %v", path, err)
+	}
+	if result.FormatVersion > FORMAT_VERSION {
+		return nil, fmt.Errorf("cassette '%s' has format version %d, supported up to %d", path, result.FormatVersion, FORMAT_VERSION)
+	}
+	return &result, nil
+}
+
+func (p *Cassette) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("unable to create cassette dir for '%s': %v", path, err)
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("unable to write cassette '%s': %v", path, err)
+	}
+	return nil
+}
+
+// Cassettes recorded against the API version, sorted by name
+func LoadAll(dir string, apiVersion string) ([]*Cassette, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, pathname.Slug(apiVersion), "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	var result []*Cassette
+	for _, path := range paths {
+		c, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+// Name of the cassette a test writes to, subtests share the cassette of their top level test
+func NameOf(test string) string {
+	return strings.SplitN(test, "/", 2)[0]
+}
@@ -0,0 +1,107 @@
+package cassette
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+const (
+	DIFF_ADDED          string = "added"
+	DIFF_REMOVED        string = "removed"
+	DIFF_STATUS_CHANGED string = "status changed"
+	DIFF_BODY_CHANGED   string = "body changed"
+)
+
+type Difference struct {
+	Test    string
+	Request Request
+	Kind    string
+	Old     string
+	New     string
+}
+
+func (p Difference) String() string {
+	switch p.Kind {
+	case DIFF_ADDED, DIFF_REMOVED:
+		return fmt.Sprintf("%s: %s %s", p.Test, p.Request.String(), p.Kind)
+	}
+	return fmt.Sprintf("%s: %s %s\n  - %s\n  + %s", p.Test, p.Request.String(), p.Kind, p.Old, p.New)
+}
+
+// Pairs the interactions of two recordings by test, request and occurrence and reports what changed
+func Compare(old []*Cassette, new []*Cassette) []Difference {
+	before, after := index(old), index(new)
+	var result []Difference
+	for _, key := range unionKeys(before, after) {
+		b, inOld := before[key]
+		a, inNew := after[key]
+		switch {
+		case !inNew:
+			result = append(result, Difference{Test: b.Test, Request: b.Request, Kind: DIFF_REMOVED})
+		case !inOld:
+			result = append(result, Difference{Test: a.Test, Request: a.Request, Kind: DIFF_ADDED})
+		case b.Response.Status != a.Response.Status:
+			result = append(result, Difference{Test: a.Test, Request: a.Request, Kind: DIFF_STATUS_CHANGED, Old: fmt.Sprint(b.Response.Status), New: fmt.Sprint(a.Response.Status)})
+		case CanonicalBody(b.Response.Body) != CanonicalBody(a.Response.Body):
+			result = append(result, Difference{Test: a.Test, Request: a.Request, Kind: DIFF_BODY_CHANGED, Old: b.Response.Body, New: a.Response.Body})
+		}
+	}
+	return result
+}
+
+func index(cassettes []*Cassette) map[string]Interaction {
+	result := make(map[string]Interaction)
+	occurrences := make(map[string]int)
+	for _, c := range cassettes {
+		for _, interaction := range c.Interactions {
+			key := interaction.Test + " " + interaction.Request.Key()
+			result[fmt.Sprintf("%s #%d", key, occurrences[key])] = interaction
+			occurrences[key]++
+		}
+	}
+	return result
+}
+
+func unionKeys(a map[string]Interaction, b map[string]Interaction) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, m := range []map[string]Interaction{a, b} {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				result = append(result, key)
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// JSON bodies are compared regardless of key order and formatting, other bodies as is
+func CanonicalBody(body string) string {
+	var value any
+	if err := json.Unmarshal([]byte(body), &value); err != nil {
+		return body
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return body
+	}
+	return string(data)
+}
+
+func WriteDifferences(w io.Writer, differences []Difference) error {
+	if len(differences) == 0 {
+		_, err := fmt.Fprintln(w, "no differences")
+		return err
+	}
+	for _, d := range differences {
+		if _, err := fmt.Fprintln(w, d.String()); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "\n%d differences\n", len(differences))
+	return err
+}
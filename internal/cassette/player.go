@@ -0,0 +1,62 @@
+package cassette
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Serves recorded responses. Requests of a test are matched by method, URL and body,
+// repeated requests get the recorded responses in the order they were recorded.
+type Player struct {
+	mutex        sync.Mutex
+	interactions map[string][]Interaction
+	played       map[string]int
+}
+
+func NewPlayer(cassettes ...*Cassette) *Player {
+	result := &Player{interactions: make(map[string][]Interaction), played: make(map[string]int)}
+	for _, c := range cassettes {
+		for _, interaction := range c.Interactions {
+			key := interaction.Test + " " + interaction.Request.Key()
+			result.interactions[key] = append(result.interactions[key], interaction)
+		}
+	}
+	return result
+}
+
+func LoadPlayer(dir string, apiVersion string) (*Player, error) {
+	cassettes, err := LoadAll(dir, apiVersion)
+	if err != nil {
+		return nil, err
+	}
+	if len(cassettes) == 0 {
+		return nil, fmt.Errorf("no cassettes for version '%s' in '%s'", apiVersion, dir)
+	}
+	return NewPlayer(cassettes...), nil
+}
+
+func (p *Player) Play(test string, req Request) (Interaction, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	key := test + " " + req.Key()
+	recorded := p.interactions[key]
+	i := p.played[key]
+	if i >= len(recorded) {
+		return Interaction{}, fmt.Errorf("no recorded response for %s in test '%s' (recorded %d, requested %d)", req.String(), test, len(recorded), i+1)
+	}
+	p.played[key] = i + 1
+	return recorded[i], nil
+}
+
+// True if the test made any requests while recording
+func (p *Player) Has(test string) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for _, recorded := range p.interactions {
+		if recorded[0].Test == test || strings.HasPrefix(recorded[0].Test, test+"/") {
+			return true
+		}
+	}
+	return false
+}
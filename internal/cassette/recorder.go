@@ -0,0 +1,46 @@
+package cassette
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+type Recorder struct {
+	mutex      sync.Mutex
+	apiVersion string
+	cassettes  map[string]*Cassette
+}
+
+func NewRecorder(apiVersion string) *Recorder {
+	return &Recorder{apiVersion: apiVersion, cassettes: make(map[string]*Cassette)}
+}
+
+func (p *Recorder) Record(test string, req Request, resp Response, duration time.Duration) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	name := NameOf(test)
+	c, ok := p.cassettes[name]
+	if !ok {
+		c = &Cassette{FormatVersion: FORMAT_VERSION, ApiVersion: p.apiVersion, Name: name, RecordedAt: time.Now().UTC()}
+		p.cassettes[name] = c
+	}
+	c.Interactions = append(c.Interactions, Interaction{Test: test, Request: req, Response: resp, Duration: duration, RecordedAt: time.Now().UTC()})
+}
+
+// Writes a file per top level test with the credentials redacted, existing cassettes of these tests are replaced
+func (p *Recorder) Save(dir string) ([]string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	var result []string
+	for name, c := range p.cassettes {
+		path := Path(dir, p.apiVersion, name)
+		c.Redact()
+		if err := c.Save(path); err != nil {
+			return result, err
+		}
+		result = append(result, path)
+	}
+	sort.Strings(result)
+	return result, nil
+}
@@ -0,0 +1,74 @@
+package cassette
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+const (
+	PLACEHOLDER_REDACTED string = "<redacted>"
+	// The same token gets the same placeholder in the whole cassette
+	PLACEHOLDER_TOKEN_FORMAT string = "<token-%d>"
+)
+
+// Headers whose values are secrets. Bearer tokens are replaced with token placeholders, other values are dropped.
+var SENSITIVE_HEADERS = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+var (
+	tokenPattern       = regexp.MustCompile(`[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`)
+	placeholderPattern = regexp.MustCompile(`^<token-\d+>$`)
+)
+
+func IsTokenPlaceholder(value string) bool {
+	return placeholderPattern.MatchString(value)
+}
+
+// Replaces the auth headers and the JWTs of requests and responses with placeholders, so cassettes carry no credentials.
+// A request still sends the placeholder of the token the response of an earlier request issued,
+// replaying and difftest pair them by the placeholder.
+func (p *Cassette) Redact() {
+	tokens := make(map[string]string)
+	placeholder := func(token string) string {
+		result, ok := tokens[token]
+		if !ok {
+			result = fmt.Sprintf(PLACEHOLDER_TOKEN_FORMAT, len(tokens)+1)
+			tokens[token] = result
+		}
+		return result
+	}
+	redact := func(text string) string {
+		return tokenPattern.ReplaceAllStringFunc(text, placeholder)
+	}
+
+	for i := range p.Interactions {
+		interaction := &p.Interactions[i]
+		interaction.Request.Header = redactHeader(interaction.Request.Header, placeholder)
+		interaction.Request.Url = redact(interaction.Request.Url)
+		interaction.Request.Body = redact(interaction.Request.Body)
+		interaction.Response.Body = redact(interaction.Response.Body)
+	}
+}
+
+func redactHeader(header http.Header, placeholder func(token string) string) http.Header {
+	if header == nil {
+		return nil
+	}
+	result := header.Clone()
+	for _, name := range SENSITIVE_HEADERS {
+		values := result.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		result.Del(name)
+		for _, value := range values {
+			if token := strings.TrimPrefix(value, "Bearer "); token != value && token != "" {
+				result.Add(name, "Bearer "+placeholder(token))
+			} else {
+				result.Add(name, PLACEHOLDER_REDACTED)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/cassette"
+)
+
+func init() {
+	register(Command{
+		Name:        "cassette-diff",
+		Description: "compare responses recorded against two API versions",
+		Run:         runCassetteDiff,
+	})
+}
+
+func runCassetteDiff(args []string) int {
+	flags := flag.NewFlagSet("cassette-diff", flag.ContinueOnError)
+	dir := flags.String("dir", "test/cassettes", "directory with cassettes (QA_CASSETTE_DIR)")
+	oldVersion := flags.String("old", "", "API version of the reference recording, e.g. the last release")
+	newVersion := flags.String("new", cassette.DEFAULT_API_VERSION, "API version of the recording to check")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *oldVersion == "" {
+		fmt.Fprintln(os.Stderr, "-old is required")
+		flags.Usage()
+		return 2
+	}
+
+	old, err := cassette.LoadAll(*dir, *oldVersion)
+	if err != nil {
+		return fail(err)
+	}
+	new, err := cassette.LoadAll(*dir, *newVersion)
+	if err != nil {
+		return fail(err)
+	}
+	if len(old) == 0 || len(new) == 0 {
+		return fail(fmt.Errorf("no cassettes for '%s' or '%s' in '%s'", *oldVersion, *newVersion, *dir))
+	}
+	differences := cassette.Compare(old, new)
+	if err := cassette.WriteDifferences(os.Stdout, differences); err != nil {
+		return fail(err)
+	}
+	if len(differences) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+type Command struct {
+	Name        string
+	Description string
+	Run         func(args []string) int
+}
+
+var registry = make(map[string]Command)
+
+func register(command Command) {
+	registry[command.Name] = command
+}
+
+func Run(args []string) int {
+	if len(args) == 0 {
+		Usage(os.Stderr)
+		return 2
+	}
+	command, ok := registry[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command '%s'\n\n", args[0])
+		Usage(os.Stderr)
+		return 2
+	}
+	return command.Run(args[1:])
+}
+
+func Usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: qa <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-12s %s\n", name, registry[name].Description)
+	}
+}
+
+func fail(err error) int {
+	fmt.Fprintf(os.Stderr, "error: %v\n", err)
+	return 1
+}
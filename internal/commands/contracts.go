@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/contract"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/difftest"
+)
+
+func init() {
+	register(Command{
+		Name:        "contracts",
+		Description: "verify consumer contracts against a running API",
+		Run:         runContracts,
+	})
+}
+
+func runContracts(args []string) int {
+	defaults := contract.DefaultFixtures()
+	flags := flag.NewFlagSet("contracts", flag.ContinueOnError)
+	url := flags.String("url", "", "base URL of the provider, e.g. http://localhost:3005")
+	dir := flags.String("dir", "test/contracts", "directory with contracts (QA_CONTRACT_DIR)")
+	consumer := flags.String("consumer", "", "verify only the contracts of this consumer")
+	userRole := flags.String("user-role", defaults.UserRole, "role of the users created by provider states")
+	userState := flags.String("user-state", defaults.UserState, "state of the users created by provider states")
+	tagState := flags.String("tag-state", defaults.TagState, "state of the tags created by provider states")
+	noteState := flags.String("note-state", defaults.NoteState, "state of the notes created by provider states")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *url == "" {
+		fmt.Fprintln(os.Stderr, "-url is required")
+		flags.Usage()
+		return 2
+	}
+
+	contracts, err := contract.LoadAll(*dir)
+	if err != nil {
+		return fail(err)
+	}
+	var selected []*contract.Contract
+	for _, c := range contracts {
+		if *consumer == "" || c.Consumer == *consumer {
+			selected = append(selected, c)
+		}
+	}
+	if len(selected) == 0 {
+		return fail(fmt.Errorf("no contracts in '%s'", *dir))
+	}
+
+	fixtures := contract.Fixtures{UserRole: *userRole, UserState: *userState, TagState: *tagState, NoteState: *noteState}
+	report := contract.NewVerifier(difftest.NewUrlTarget(*url, *url), fixtures).Verify(context.Background(), selected)
+	if err := report.WriteText(os.Stdout); err != nil {
+		return fail(err)
+	}
+	if report.Failed() {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/cassette"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/dbconn"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/difftest"
+)
+
+func init() {
+	register(Command{
+		Name:        "diff",
+		Description: "replay recorded requests against two API builds and report divergences",
+		Run:         runDiff,
+	})
+}
+
+type diffTargetFlags struct {
+	url   *string
+	dsn   *string
+	reset *string
+}
+
+func addDiffTargetFlags(flags *flag.FlagSet, name string) diffTargetFlags {
+	return diffTargetFlags{
+		url:   flags.String(name, "", fmt.Sprintf("base URL of target %s", strings.ToUpper(name))),
+		dsn:   flags.String(name+"-dsn", "", fmt.Sprintf("DB of target %s, DB side effects are compared only if both are set", strings.ToUpper(name))),
+		reset: flags.String(name+"-reset", "", fmt.Sprintf("shell command that resets target %s before every test, e.g. recreates its DB", strings.ToUpper(name))),
+	}
+}
+
+func (p diffTargetFlags) target(name string) (*difftest.Target, func(), error) {
+	result := difftest.NewUrlTarget(name+" "+*p.url, *p.url)
+	if *p.reset != "" {
+		command := *p.reset
+		result.Reset = func() error {
+			output, err := exec.Command("sh", "-c", command).CombinedOutput()
+			if err != nil {
+				return fmt.Errorf("'%s' failed: %v\n%s", command, err, output)
+			}
+			return nil
+		}
+	}
+	if *p.dsn == "" {
+		return result, func() {}, nil
+	}
+	db, err := dbconn.Open(*p.dsn)
+	if err != nil {
+		return nil, nil, err
+	}
+	result.DB = db
+	return result, func() { db.Close() }, nil
+}
+
+func runDiff(args []string) int {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	a := addDiffTargetFlags(flags, "a")
+	b := addDiffTargetFlags(flags, "b")
+	dir := flags.String("cassettes", "test/cassettes", "directory with cassettes the scenario stream is taken from")
+	version := flags.String("version", cassette.DEFAULT_API_VERSION, "API version of the cassettes")
+	test := flags.String("test", "", "replay only tests with this name prefix")
+	volatile := flags.String("volatile", strings.Join(difftest.DEFAULT_VOLATILE_FIELDS, ","), "comma separated fields whose values are ignored")
+	asJson := flags.Bool("json", false, "print the report as JSON")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *a.url == "" || *b.url == "" {
+		fmt.Fprintln(os.Stderr, "-a and -b are required")
+		flags.Usage()
+		return 2
+	}
+
+	cassettes, err := cassette.LoadAll(*dir, *version)
+	if err != nil {
+		return fail(err)
+	}
+	var steps []difftest.Step
+	for _, step := range difftest.FromCassettes(cassettes...) {
+		if strings.HasPrefix(step.Test, *test) {
+			steps = append(steps, step)
+		}
+	}
+	if len(steps) == 0 {
+		return fail(fmt.Errorf("no recorded requests for version '%s' in '%s'", *version, *dir))
+	}
+
+	targetA, closeA, err := a.target("A")
+	if err != nil {
+		return fail(err)
+	}
+	defer closeA()
+	targetB, closeB, err := b.target("B")
+	if err != nil {
+		return fail(err)
+	}
+	defer closeB()
+	if targetA.DB == nil || targetB.DB == nil {
+		targetA.DB, targetB.DB = nil, nil
+	}
+
+	runner := difftest.NewRunner(targetA, targetB)
+	runner.Normalizer = difftest.NewNormalizer(strings.Split(*volatile, ",")...)
+	report, err := runner.Run(context.Background(), steps)
+	if err != nil {
+		return fail(err)
+	}
+	if *asJson {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return fail(err)
+		}
+	} else if err := report.WriteText(os.Stdout); err != nil {
+		return fail(err)
+	}
+	if report.Failed() {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/history"
+)
+
+const DEFAULT_HISTORY_PATH string = "test/history.db"
+
+func init() {
+	register(Command{
+		Name:        "history",
+		Description: "query the history of test runs: runs, started-failing, durations, first-failing, export",
+		Run:         runHistory,
+	})
+}
+
+func runHistory(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: qa history <runs|started-failing|durations|first-failing|export> [flags]")
+		return 2
+	}
+	query := args[0]
+	flags := flag.NewFlagSet("history "+query, flag.ContinueOnError)
+	path := flags.String("db", DEFAULT_HISTORY_PATH, "history file")
+	version := flags.String("version", os.Getenv("QA_API_VERSION"), "API version the runs were made against")
+	test := flags.String("test", "", "full name of the test, durations also take a name prefix")
+	limit := flags.Int("limit", 0, "latest runs taken into account, all if 0")
+	window := flags.Int("window", 5, "runs averaged on each side of the duration comparison")
+	format := flags.String("format", history.EXPORT_JSON, "export format, json or csv")
+	out := flags.String("out", "", "export file, stdout if empty")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	store, err := history.Open(*path)
+	if err != nil {
+		return fail(err)
+	}
+	defer store.Close()
+	runs, err := store.Runs(*version)
+	if err != nil {
+		return fail(err)
+	}
+	runs = history.Latest(runs, *limit)
+
+	switch query {
+	case "runs":
+		err = history.WriteRuns(os.Stdout, runs)
+	case "started-failing":
+		err = history.WriteStreaks(os.Stdout, history.StartedFailing(runs))
+	case "durations":
+		// A full test name shows the durations of every run, a prefix compares the averages
+		if points := history.Series(runs, *test); len(points) > 0 {
+			err = history.WriteSeries(os.Stdout, points)
+		} else {
+			err = history.WriteTrends(os.Stdout, history.DurationTrends(runs, *window, *test))
+		}
+	case "first-failing":
+		if *test == "" {
+			fmt.Fprintln(os.Stderr, "usage: qa history first-failing -test <name>")
+			return 2
+		}
+		streak, ok := history.FirstFailing(runs, *test)
+		if !ok {
+			fmt.Printf("%s does not fail in the latest run it ran in\n", *test)
+			return 0
+		}
+		err = history.WriteStreak(os.Stdout, streak)
+	case "export":
+		err = export(runs, *format, *out)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown history query '%s'\n", query)
+		return 2
+	}
+	if err != nil {
+		return fail(err)
+	}
+	return 0
+}
+
+func export(runs []history.Run, format string, path string) error {
+	var w io.Writer = os.Stdout
+	if path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("unable to create export '%s': %v", path, err)
+		}
+		defer file.Close()
+		w = file
+	}
+	return history.Export(w, runs, format)
+}
@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/importer"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/pathname"
+	"github.com/joho/godotenv"
+)
+
+func init() {
+	register(Command{
+		Name:        "import",
+		Description: "convert Postman or Insomnia collections to scenario files",
+		Run:         runImport,
+	})
+}
+
+func runImport(args []string) int {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	out := flags.String("out", "test/scenarios", "directory for the scenario files")
+	environment := flags.String("env", "", "Postman environment export with the variable values")
+	environmentName := flags.String("insomnia-env", "", "sub environment of the Insomnia export")
+	dotEnv := flags.String("env-file", ".env.test", "env file the -map keys refer to")
+	mapping := flags.String("map", "", "collection variables read from the environment when the scenarios run, the keys must be in the env file, e.g. adminEmail=ADMIN_EMAIL,adminPassword=ADMIN_PASSWORD")
+	force := flags.Bool("force", false, "overwrite existing scenario files")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: qa import [flags] <collection.json>...")
+		flags.Usage()
+		return 2
+	}
+
+	options := importer.Options{EnvironmentName: *environmentName}
+	if *environment != "" {
+		data, err := os.ReadFile(*environment)
+		if err != nil {
+			return fail(err)
+		}
+		if options.Environment, err = importer.ParsePostmanEnvironment(data); err != nil {
+			return fail(err)
+		}
+	}
+	if *mapping != "" {
+		options.DotEnvMapping = make(map[string]string)
+		for _, pair := range strings.Split(*mapping, ",") {
+			name, key, ok := strings.Cut(pair, "=")
+			if !ok || name == "" || key == "" {
+				return fail(fmt.Errorf("wrong mapping '%s', expected variable=KEY", pair))
+			}
+			options.DotEnvMapping[name] = key
+		}
+		values, err := godotenv.Read(*dotEnv)
+		if err != nil {
+			return fail(fmt.Errorf("unable to read env file '%s': %v", *dotEnv, err))
+		}
+		options.DotEnv = values
+	}
+
+	failed := false
+	for _, path := range flags.Args() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fail(err)
+		}
+		result, err := importer.Import(data, options)
+		if err != nil {
+			return fail(fmt.Errorf("%s: %v", path, err))
+		}
+		for _, warning := range result.Warnings {
+			fmt.Fprintf(os.Stderr, "warning: %s: %s\n", path, warning)
+		}
+		for _, s := range result.Scenarios {
+			target := filepath.Join(*out, strings.ToLower(pathname.Slug(s.Name))+".yaml")
+			if _, err := os.Stat(target); err == nil && !*force {
+				fmt.Fprintf(os.Stderr, "%s exists, use -force to overwrite it\n", target)
+				failed = true
+				continue
+			}
+			if err := s.Save(target); err != nil {
+				return fail(err)
+			}
+			fmt.Printf("%s: %d steps\n", target, len(s.Steps))
+		}
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
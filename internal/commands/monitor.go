@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/difftest"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/monitor"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/stub"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	register(Command{
+		Name:        "monitor",
+		Description: "probe a deployed API in a loop and expose the results as Prometheus metrics",
+		Run:         runMonitor,
+	})
+}
+
+func runMonitor(args []string) int {
+	enums := stub.DefaultEnums()
+	flags := flag.NewFlagSet("monitor", flag.ContinueOnError)
+	url := flags.String("url", "", "base URL of the API, e.g. http://localhost:3005")
+	addr := flags.String("addr", ":9090", "listen address of /metrics")
+	interval := flags.Duration("interval", 30*time.Second, "time between rounds of the probes")
+	timeout := flags.Duration("timeout", 10*time.Second, "time a probe may take")
+	probes := flags.String("probes", strings.Join([]string{monitor.PROBE_PING, monitor.PROBE_SAFE_PING, monitor.PROBE_NOTE}, ","), "probes to run")
+	email := flags.String("email", os.Getenv("QA_MONITOR_EMAIL"), "email of the user the probes log in as")
+	password := flags.String("password", os.Getenv("QA_MONITOR_PASSWORD"), "password of the user the probes log in as")
+	userId := flags.Int("user-id", 0, "existing author of the probe notes")
+	tagId := flags.Int("tag-id", 0, "existing tag of the probe notes")
+	temporary := flags.Bool("temporary-fixtures", false, "create a user and a tag for every probe note if -user-id or -tag-id is 0. The API only soft deletes them, so every round leaves rows behind")
+	userRole := flags.String("user-role", enums.UserRoles[0], "role of the temporary users")
+	userState := flags.String("user-state", enums.UserStates[0], "state of the temporary users")
+	tagState := flags.String("tag-state", enums.TagStates[0], "state of the temporary tags")
+	noteState := flags.String("note-state", enums.NoteStates[0], "state of the probe notes")
+	webhook := flags.String("webhook", "", "URL the alerts are posted to, no alerts if empty")
+	failures := flags.Int("failures", 3, "failed runs of a probe in a row that fire an alert")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *url == "" {
+		fmt.Fprintln(os.Stderr, "-url is required")
+		flags.Usage()
+		return 2
+	}
+
+	credentials := monitor.Credentials{Email: *email, Password: *password}
+	fixtures := monitor.NoteFixtures{UserId: *userId, TagId: *tagId, Temporary: *temporary, UserRole: *userRole, UserState: *userState, TagState: *tagState, NoteState: *noteState}
+	available := make(map[string]monitor.Probe)
+	for _, probe := range monitor.Probes(credentials, fixtures) {
+		available[probe.Name] = probe
+	}
+	config := monitor.Config{TargetUrl: *url, Interval: *interval, Timeout: *timeout, Log: os.Stderr}
+	for _, name := range strings.Split(*probes, ",") {
+		probe, ok := available[strings.TrimSpace(name)]
+		if !ok {
+			return fail(fmt.Errorf("unknown probe '%s'", name))
+		}
+		if probe.Name != monitor.PROBE_PING && *email == "" {
+			return fail(fmt.Errorf("probe '%s' logs in, -email and -password are required", probe.Name))
+		}
+		if probe.Name == monitor.PROBE_NOTE {
+			if err := fixtures.Validate(); err != nil {
+				return fail(fmt.Errorf("%v: set -user-id and -tag-id or -temporary-fixtures", err))
+			}
+		}
+		config.Probes = append(config.Probes, probe)
+	}
+	if *webhook != "" {
+		config.Webhook = monitor.NewWebhook(*webhook, *failures)
+	}
+	gin.SetMode(gin.ReleaseMode)
+
+	target := difftest.NewUrlTarget(*url, *url)
+	m := monitor.New(target, config)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	server := &http.Server{Addr: *addr, Handler: m.Router()}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+	// The probe running at shutdown finishes its cleanup before the command exits
+	done := make(chan struct{})
+	go func() {
+		m.Run(ctx)
+		close(done)
+	}()
+
+	fmt.Printf("monitoring %s, metrics are served on %s/metrics\n", *url, *addr)
+	err := server.ListenAndServe()
+	stop()
+	<-done
+	if err != nil && err != http.ErrServerClosed {
+		return fail(err)
+	}
+	return 0
+}
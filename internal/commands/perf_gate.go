@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/perf"
+)
+
+func init() {
+	register(Command{
+		Name:        "perf-gate",
+		Description: "compare load report with stored performance baselines",
+		Run:         runPerfGate,
+	})
+}
+
+func runPerfGate(args []string) int {
+	flags := flag.NewFlagSet("perf-gate", flag.ContinueOnError)
+	reportPath := flags.String("report", "", "path to JSON load report (QA_LOAD_REPORT)")
+	scenario := flags.String("scenario", "", "scenario name the report belongs to")
+	baselines := flags.String("baselines", "test/baselines", "directory with baseline files")
+	tolerancesSpec := flags.String("tolerances", perf.DEFAULT_TOLERANCES, "allowed regressions, e.g. 'p95=20%,GET /notes?limit=100@p95=10%'")
+	update := flags.Bool("update", false, "store the report as new baselines instead of comparing")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *reportPath == "" || *scenario == "" {
+		fmt.Fprintln(os.Stderr, "-report and -scenario are required")
+		flags.Usage()
+		return 2
+	}
+
+	report, err := perf.LoadReport(*reportPath)
+	if err != nil {
+		return fail(err)
+	}
+	current := perf.FromReport(*scenario, report)
+
+	if *update {
+		if err := perf.WriteBaselines(*baselines, current); err != nil {
+			return fail(err)
+		}
+		fmt.Printf("stored %d baselines for scenario '%s' in %s\n", len(current), *scenario, *baselines)
+		return 0
+	}
+
+	tolerances, err := perf.ParseTolerances(*tolerancesSpec)
+	if err != nil {
+		return fail(err)
+	}
+	result, err := perf.Compare(*baselines, current, tolerances)
+	if err != nil {
+		return fail(err)
+	}
+	result.WriteTable(os.Stdout)
+	if result.Failed() {
+		fmt.Fprintln(os.Stderr, "\nperformance regression detected")
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/history"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/runner"
+)
+
+func init() {
+	register(Command{
+		Name:        "run",
+		Description: "run the tests with retries of failed ones and flakiness stats",
+		Run:         runTests,
+	})
+}
+
+func runTests(args []string) int {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	retries := flags.Int("retries", 2, "times a failed test is run again, a test that passes on a retry is flaky")
+	quarantinePath := flags.String("quarantine", "test/quarantine.txt", "tests whose failures do not fail the run, one per line")
+	statsPath := flags.String("stats", "test/flaky-stats.json", "file with flakiness stats, updated after the run")
+	run := flags.String("run", "", "-run pattern of go test")
+	tags := flags.String("tags", runner.DEFAULT_TAGS, "build tags of go test")
+	goArgs := flags.String("go-args", "", "other arguments of go test, e.g. '-timeout 30m'")
+	verbose := flags.Bool("v", false, "list all tests in the report, not only failed and flaky ones")
+	quiet := flags.Bool("q", false, "do not print the output of the tests while they run")
+	flakiest := flags.Int("flakiest", 0, "print the N flakiest tests from the stats and exit")
+	historyPath := flags.String("history", DEFAULT_HISTORY_PATH, "history file the run is stored in, not stored if empty")
+	apiVersion := flags.String("api-version", os.Getenv("QA_API_VERSION"), "API version the run is made against")
+	commit := flags.String("commit", os.Getenv("QA_API_COMMIT"), "commit of the API the run is made against")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	stats, err := runner.LoadStats(*statsPath)
+	if err != nil {
+		return fail(err)
+	}
+	if *flakiest > 0 {
+		if err := runner.WriteFlakiest(os.Stdout, stats, *flakiest); err != nil {
+			return fail(err)
+		}
+		return 0
+	}
+	quarantine, err := runner.LoadQuarantine(*quarantinePath)
+	if err != nil {
+		return fail(err)
+	}
+
+	options := runner.Options{
+		Packages:   flags.Args(),
+		Tags:       *tags,
+		Run:        *run,
+		Args:       strings.Fields(*goArgs),
+		Retries:    *retries,
+		Quarantine: quarantine,
+		Output:     os.Stdout,
+	}
+	if *quiet {
+		options.Output = io.Discard
+	}
+	result, err := runner.Run(context.Background(), options)
+	if err != nil {
+		return fail(err)
+	}
+	stats.Add(result)
+	if err := stats.Save(*statsPath); err != nil {
+		return fail(err)
+	}
+	if *historyPath != "" {
+		if err := storeRun(*historyPath, history.FromResult(result, *apiVersion, *commit)); err != nil {
+			return fail(err)
+		}
+	}
+	if err := runner.WriteReport(os.Stdout, result, stats, *verbose); err != nil {
+		return fail(err)
+	}
+	if result.Failed() {
+		return 1
+	}
+	return 0
+}
+
+func storeRun(path string, run history.Run) error {
+	store, err := history.Open(path)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	return store.Add(&run)
+}
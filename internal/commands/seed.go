@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/dbconn"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/seed"
+)
+
+func init() {
+	register(Command{
+		Name:        "seed",
+		Description: "bulk insert users, tags, tasks and notes into the DB",
+		Run:         runSeed,
+	})
+}
+
+func runSeed(args []string) int {
+	config := seed.DefaultConfig()
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	dsn := flags.String("dsn", "", "DB connection string, by default built from DATABASE_* variables")
+	envFile := flags.String("env-file", dbconn.DEFAULT_ENV_FILE, "file with DATABASE_* variables")
+	flags.IntVar(&config.Users, "users", 1000, "count of users")
+	flags.IntVar(&config.Tags, "tags", 100, "count of tags")
+	flags.IntVar(&config.Tasks, "tasks", 0, "count of tasks")
+	notesPerUser := flags.String("notes-per-user", config.NotesPerUser.String(), "distribution of notes per user: fixed:N, uniform:MIN-MAX or zipf:S:MAX")
+	tagPopularity := flags.String("tag-popularity", config.TagPopularity.String(), "distribution of tag ranks, 0 is the most popular tag")
+	flags.Float64Var(&config.DeletedRatio, "deleted-ratio", 0, "share of soft deleted rows")
+	flags.StringVar(&config.State, "state", config.State, "state of rows")
+	flags.StringVar(&config.DeletedState, "deleted-state", config.DeletedState, "state of soft deleted rows")
+	flags.StringVar(&config.UserRole, "user-role", config.UserRole, "role of users")
+	flags.StringVar(&config.Method, "method", config.Method, fmt.Sprintf("insert method %v", seed.GetPossibleMethods()))
+	flags.IntVar(&config.BatchSize, "batch-size", 0, "rows per statement for the insert method, 0 means as many as possible")
+	flags.Int64Var(&config.Seed, "seed", config.Seed, "random seed")
+	flags.StringVar(&config.Prefix, "prefix", config.Prefix, "prefix of generated names, must be unique per run")
+	flags.BoolVar(&config.Analyze, "analyze", config.Analyze, "run ANALYZE after seeding")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	var err error
+	if config.NotesPerUser, err = seed.ParseDistribution(*notesPerUser); err != nil {
+		return fail(err)
+	}
+	if config.TagPopularity, err = seed.ParseDistribution(*tagPopularity); err != nil {
+		return fail(err)
+	}
+	if err := config.Validate(); err != nil {
+		return fail(err)
+	}
+	if err := dbconn.LoadEnv(*envFile); err != nil {
+		return fail(err)
+	}
+	db, err := dbconn.Open(*dsn)
+	if err != nil {
+		return fail(err)
+	}
+	defer db.Close()
+
+	result, err := seed.Run(context.Background(), db, config)
+	if err != nil {
+		return fail(err)
+	}
+	fmt.Println(result.String())
+	return 0
+}
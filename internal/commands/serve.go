@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/controlplane"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/history"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/runner"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	register(Command{
+		Name:        "serve",
+		Description: "start the HTTP control plane that runs the tests on demand",
+		Run:         runServe,
+	})
+}
+
+func runServe(args []string) int {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := flags.String("addr", "127.0.0.1:8090", "listen address, local by default since the control plane has no authentication")
+	dir := flags.String("dir", ".", "root of the module the tests are run in")
+	workers := flags.Int("workers", 1, "runs executed at the same time, only 1 is supported: every run recreates the same test DB")
+	queueSize := flags.Int("queue", 10, "runs waiting for a worker, more are rejected with 503")
+	keep := flags.Int("keep", 100, "finished runs kept in memory")
+	retries := flags.Int("retries", 2, "times a failed test is run again, a test that passes on a retry is flaky")
+	quarantinePath := flags.String("quarantine", "test/quarantine.txt", "tests whose failures do not fail the run, one per line")
+	tags := flags.String("tags", runner.DEFAULT_TAGS, "build tags of go test")
+	goArgs := flags.String("go-args", "", "other arguments of go test, e.g. '-timeout 30m'")
+	historyPath := flags.String("history", DEFAULT_HISTORY_PATH, "history file the finished runs are stored in, not stored if empty")
+	apiVersion := flags.String("api-version", os.Getenv("QA_API_VERSION"), "API version the runs are made against")
+	commit := flags.String("commit", os.Getenv("QA_API_COMMIT"), "commit of the API the runs are made against")
+	allowedTargets := flags.String("allow-target", "", "base URLs of deployed APIs the runs may target, comma separated, e.g. https://api.staging.example")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *workers != 1 {
+		// Parallel runs would roll back and recreate the shared DB under each other
+		fmt.Fprintln(os.Stderr, "-workers must be 1, the runs share the test DB")
+		return 2
+	}
+
+	quarantine, err := runner.LoadQuarantine(*quarantinePath)
+	if err != nil {
+		return fail(err)
+	}
+	options := controlplane.Options{
+		Runner: runner.Options{
+			Dir:        *dir,
+			Tags:       *tags,
+			Args:       strings.Fields(*goArgs),
+			Retries:    *retries,
+			Quarantine: quarantine,
+		},
+		Workers:      *workers,
+		QueueSize:    *queueSize,
+		KeepFinished: *keep,
+	}
+	for _, target := range strings.Split(*allowedTargets, ",") {
+		if target = strings.TrimSpace(target); target != "" {
+			options.AllowedTargets = append(options.AllowedTargets, target)
+		}
+	}
+	if *historyPath != "" {
+		options.OnFinish = func(run controlplane.Run) {
+			if err := storeRun(*historyPath, history.FromResult(*run.Result, *apiVersion, *commit)); err != nil {
+				fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			}
+		}
+	}
+	gin.SetMode(gin.ReleaseMode)
+
+	queue := controlplane.NewQueue(options)
+	server := &http.Server{Addr: *addr, Handler: controlplane.NewServer(queue).Router()}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("control plane is listening on %s\n", *addr)
+	err = server.ListenAndServe()
+	stop()
+	// Kills the test binaries of the running run, they would go on recreating the DB otherwise
+	queue.Close()
+	if err != nil && err != http.ErrServerClosed {
+		return fail(err)
+	}
+	return 0
+}
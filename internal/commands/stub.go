@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/stub"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	register(Command{
+		Name:        "stub",
+		Description: "start an in-memory fake of the API for downstream consumers",
+		Run:         runStub,
+	})
+}
+
+func runStub(args []string) int {
+	config := stub.DefaultConfig()
+	flags := flag.NewFlagSet("stub", flag.ContinueOnError)
+	addr := flags.String("addr", ":8080", "listen address")
+	configPath := flags.String("config", "", "JSON file that overrides the default config, e.g. {\"Messages\":{\"Done\":\"OK\"}}")
+	flags.DurationVar(&config.AccessTokenTTL, "access-token-ttl", config.AccessTokenTTL, "lifetime of access tokens")
+	flags.DurationVar(&config.RefreshTokenTTL, "refresh-token-ttl", config.RefreshTokenTTL, "lifetime of refresh tokens")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *configPath != "" {
+		data, err := os.ReadFile(*configPath)
+		if err != nil {
+			return fail(fmt.Errorf("unable to read stub config '%s': %v", *configPath, err))
+		}
+		if err := json.Unmarshal(data, &config); err != nil {
+			return fail(fmt.Errorf("unable to parse stub config '%s': %v", *configPath, err))
+		}
+	}
+	gin.SetMode(gin.ReleaseMode)
+
+	server := stub.New(config)
+	fmt.Printf("stub of the API is listening on %s\n", *addr)
+	if err := server.Run(*addr); err != nil {
+		return fail(err)
+	}
+	return 0
+}
@@ -0,0 +1,107 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const FORMAT_VERSION int = 1
+
+// Interactions a consumer relies on, e.g.
+//
+//	{
+//	  "Consumer": "notes-feed",
+//	  "Interactions": [{
+//	    "Description": "a note by id",
+//	    "Given": [{"Name": "a note exists"}],
+//	    "Request": {"Method": "GET", "Path": "/notes/${noteId}"},
+//	    "Response": {
+//	      "Status": 200,
+//	      "Body": {"Id": 1, "Text": "text", "State": "NEW"},
+//	      "Matchers": {"$.Id": {"Match": "type"}, "$.Text": {"Match": "type"}}
+//	    }
+//	  }]
+//	}
+type Contract struct {
+	FormatVersion int
+	Consumer      string
+	Provider      string
+	Interactions  []Interaction
+	// File the contract was loaded from
+	Path string `json:"-"`
+}
+
+type Interaction struct {
+	Description string
+	// Provider states to set up before the request, their variables are available as ${name}
+	Given    []State `json:",omitempty"`
+	Request  Request
+	Response Response
+}
+
+type State struct {
+	Name   string
+	Params map[string]any `json:",omitempty"`
+}
+
+type Request struct {
+	Method  string
+	Path    string
+	Headers map[string]string `json:",omitempty"`
+	Body    any               `json:",omitempty"`
+}
+
+// Expected response. Fields of the body that are not in the example are ignored, the example values
+// must be equal unless a matcher relaxes them.
+type Response struct {
+	Status   int
+	Body     any                `json:",omitempty"`
+	Matchers map[string]Matcher `json:",omitempty"`
+}
+
+func Load(path string) (*Contract, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read contract '%s': %v", path, err)
+	}
+	var result Contract
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("unable to parse contract '%s': %v", path, err)
+	}
+	if result.FormatVersion > FORMAT_VERSION {
+		return nil, fmt.Errorf("contract '%s' has format version %d, supported up to %d", path, result.FormatVersion, FORMAT_VERSION)
+	}
+	if result.Consumer == "" {
+		return nil, fmt.Errorf("contract '%s' has no consumer", path)
+	}
+	for _, interaction := range result.Interactions {
+		for jsonPath, matcher := range interaction.Response.Matchers {
+			if err := matcher.validate(); err != nil {
+				return nil, fmt.Errorf("contract '%s', '%s' %s: %v", path, interaction.Description, jsonPath, err)
+			}
+		}
+	}
+	result.Path = path
+	return &result, nil
+}
+
+// Contracts of the dir sorted by file name
+func LoadAll(dir string) ([]*Contract, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	var result []*Contract
+	for _, path := range paths {
+		c, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, c)
+	}
+	return result, nil
+}
@@ -0,0 +1,168 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Same JSON type as the example, applies to everything below the path too
+	MATCH_TYPE string = "type"
+	// String that matches Regex
+	MATCH_REGEX string = "regex"
+	// String that contains the example
+	MATCH_INCLUDE string = "include"
+	// Value equal to the example, the default without a matcher
+	MATCH_EQUALITY string = "equality"
+)
+
+var arrayIndex = regexp.MustCompile(`\[\d+\]`)
+
+// Relaxes the comparison at a JSONPath of the body, e.g. "$.Id" or "$.Data[*].Id"
+type Matcher struct {
+	Match string
+	Regex string `json:",omitempty"`
+	// Least number of items of an array matched by type, every item is matched against the first one of the example
+	Min int `json:",omitempty"`
+}
+
+func (p Matcher) validate() error {
+	switch p.Match {
+	case MATCH_TYPE, MATCH_INCLUDE, MATCH_EQUALITY:
+		return nil
+	case MATCH_REGEX:
+		_, err := regexp.Compile(p.Regex)
+		return err
+	}
+	return fmt.Errorf("unknown matcher '%s'", p.Match)
+}
+
+// Mismatches between the actual body and the example, as "$.path: message"
+func Match(example any, actual any, matchers map[string]Matcher) []string {
+	var result []string
+	compare("$", normalize(example), actual, matchers, false, &result)
+	return result
+}
+
+func compare(path string, expected any, actual any, matchers map[string]Matcher, byType bool, result *[]string) {
+	fail := func(format string, args ...any) {
+		*result = append(*result, path+": "+fmt.Sprintf(format, args...))
+	}
+	matcher, ok := matchers[path]
+	if !ok {
+		matcher, ok = matchers[arrayIndex.ReplaceAllString(path, "[*]")]
+	}
+	if ok {
+		switch matcher.Match {
+		case MATCH_REGEX:
+			text, isString := actual.(string)
+			if !isString || !regexp.MustCompile(matcher.Regex).MatchString(text) {
+				fail("%s does not match /%s/", toJson(actual), matcher.Regex)
+			}
+			return
+		case MATCH_INCLUDE:
+			text, isString := actual.(string)
+			if !isString || !strings.Contains(text, fmt.Sprint(expected)) {
+				fail("%s does not include %s", toJson(actual), toJson(expected))
+			}
+			return
+		case MATCH_TYPE:
+			byType = true
+		case MATCH_EQUALITY:
+			byType = false
+		}
+	}
+
+	switch e := expected.(type) {
+	case map[string]any:
+		a, isObject := actual.(map[string]any)
+		if !isObject {
+			fail("expected an object, got %s", toJson(actual))
+			return
+		}
+		for _, key := range sortedKeys(e) {
+			value, ok := a[key]
+			if !ok {
+				fail("missing field '%s'", key)
+				continue
+			}
+			compare(path+"."+key, e[key], value, matchers, byType, result)
+		}
+	case []any:
+		a, isArray := actual.([]any)
+		if !isArray {
+			fail("expected an array, got %s", toJson(actual))
+			return
+		}
+		if byType {
+			if len(a) < matcher.Min {
+				fail("%d items, expected at least %d", len(a), matcher.Min)
+			}
+			if len(e) == 0 {
+				return
+			}
+			for i, item := range a {
+				compare(path+"["+strconv.Itoa(i)+"]", e[0], item, matchers, true, result)
+			}
+			return
+		}
+		if len(a) != len(e) {
+			fail("%d items, expected %d", len(a), len(e))
+			return
+		}
+		for i := range e {
+			compare(path+"["+strconv.Itoa(i)+"]", e[i], a[i], matchers, false, result)
+		}
+	default:
+		if byType {
+			if jsonType(expected) != jsonType(actual) {
+				fail("expected a %s, got %s", jsonType(expected), toJson(actual))
+			}
+			return
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			fail("expected %s, got %s", toJson(expected), toJson(actual))
+		}
+	}
+}
+
+func jsonType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	}
+	return "object"
+}
+
+// Same types as json.Unmarshal produces
+func normalize(value any) any {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var result any
+	if json.Unmarshal(data, &result) != nil {
+		return value
+	}
+	return result
+}
+
+func toJson(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,89 @@
+package contract
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	example := map[string]any{"Id": 1, "Name": "books", "State": "NEW", "Owner": map[string]any{"Id": 7, "Email": "owner@example.com"}, "Tags": []any{map[string]any{"Id": 1, "Name": "a"}}}
+	tests := []struct {
+		Name     string
+		Matchers map[string]Matcher
+		Actual   string
+		Expected []string
+	}{
+		{
+			Name:     "Equality",
+			Actual:   `{"Id": 2, "Name": "books", "State": "NEW", "Owner": {"Id": 7, "Email": "owner@example.com"}, "Tags": [{"Id": 1, "Name": "a"}, {"Id": 2, "Name": "b"}]}`,
+			Expected: []string{"$.Id: expected 1, got 2", "$.Tags: 2 items, expected 1"},
+		},
+		{
+			Name:     "MissingFieldsAndTypes",
+			Actual:   `{"Id": 1, "Name": "books", "Owner": [], "Tags": {}, "Extra": true}`,
+			Expected: []string{"$.Owner: expected an object, got []", "$: missing field 'State'", "$.Tags: expected an array, got {}"},
+		},
+		{
+			// The type matcher of the root covers every field below it
+			Name:     "TypeBelowPath",
+			Matchers: map[string]Matcher{"$": {Match: MATCH_TYPE}},
+			Actual:   `{"Id": 2, "Name": "music", "State": "DELETED", "Owner": {"Id": 8, "Email": null}, "Tags": [{"Id": 2, "Name": "b"}, {"Id": "3", "Name": "c"}]}`,
+			Expected: []string{"$.Owner.Email: expected a string, got null", "$.Tags[1].Id: expected a number, got \"3\""},
+		},
+		{
+			// An equality matcher below a type matcher compares values again
+			Name:     "EqualityBelowType",
+			Matchers: map[string]Matcher{"$": {Match: MATCH_TYPE}, "$.State": {Match: MATCH_EQUALITY}},
+			Actual:   `{"Id": 2, "Name": "music", "State": "DELETED", "Owner": {"Id": 8, "Email": "other@example.com"}, "Tags": []}`,
+			Expected: []string{"$.State: expected \"NEW\", got \"DELETED\""},
+		},
+		{
+			Name:     "ArrayItemsFallback",
+			Matchers: map[string]Matcher{"$.Tags": {Match: MATCH_TYPE}, "$.Tags[*].Name": {Match: MATCH_REGEX, Regex: "^[a-z]+$"}, "$.Tags[1].Name": {Match: MATCH_INCLUDE}},
+			Actual:   `{"Id": 1, "Name": "books", "State": "NEW", "Owner": {"Id": 7, "Email": "owner@example.com"}, "Tags": [{"Id": 1, "Name": "a"}, {"Id": 2, "Name": "B"}, {"Id": 3, "Name": "C"}]}`,
+			// The exact path wins over [*]
+			Expected: []string{"$.Tags[1].Name: \"B\" does not include \"a\"", "$.Tags[2].Name: \"C\" does not match /^[a-z]+$/"},
+		},
+		{
+			Name:     "MinItems",
+			Matchers: map[string]Matcher{"$.Tags": {Match: MATCH_TYPE, Min: 2}},
+			Actual:   `{"Id": 1, "Name": "books", "State": "NEW", "Owner": {"Id": 7, "Email": "owner@example.com"}, "Tags": [{"Id": 5, "Name": "z"}]}`,
+			Expected: []string{"$.Tags: 1 items, expected at least 2"},
+		},
+		{
+			Name:     "EmptyArrayByType",
+			Matchers: map[string]Matcher{"$.Tags": {Match: MATCH_TYPE}},
+			Actual:   `{"Id": 1, "Name": "books", "State": "NEW", "Owner": {"Id": 7, "Email": "owner@example.com"}, "Tags": []}`,
+		},
+		{
+			Name:     "RegexAndInclude",
+			Matchers: map[string]Matcher{"$.Name": {Match: MATCH_REGEX, Regex: "^b"}, "$.Owner.Email": {Match: MATCH_INCLUDE}, "$.Id": {Match: MATCH_REGEX, Regex: ".*"}},
+			Actual:   `{"Id": 1, "Name": "boots", "State": "NEW", "Owner": {"Id": 7, "Email": "Owner owner@example.com"}, "Tags": [{"Id": 1, "Name": "a"}]}`,
+			// Regex matches only strings
+			Expected: []string{"$.Id: 1 does not match /.*/"},
+		},
+		{
+			Name:     "RegexAndIncludeMismatch",
+			Matchers: map[string]Matcher{"$.Name": {Match: MATCH_REGEX, Regex: "^b"}, "$.Owner.Email": {Match: MATCH_INCLUDE}},
+			Actual:   `{"Id": 1, "Name": "music", "State": "NEW", "Owner": {"Id": 7, "Email": 7}, "Tags": [{"Id": 1, "Name": "a"}]}`,
+			Expected: []string{"$.Name: \"music\" does not match /^b/", "$.Owner.Email: 7 does not include \"owner@example.com\""},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var actual any
+			assert.Nil(t, json.Unmarshal([]byte(test.Actual), &actual))
+
+			assert.Equal(t, test.Expected, Match(example, actual, test.Matchers))
+		})
+	}
+}
+
+func TestMatcherValidate(t *testing.T) {
+	assert.Nil(t, Matcher{Match: MATCH_TYPE}.validate())
+	assert.Nil(t, Matcher{Match: MATCH_REGEX, Regex: "^[0-9]+$"}.validate())
+	assert.NotNil(t, Matcher{Match: MATCH_REGEX, Regex: "("}.validate())
+	assert.EqualError(t, Matcher{Match: "like"}.validate(), "unknown matcher 'like'")
+}
@@ -0,0 +1,74 @@
+package contract
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+type Result struct {
+	Consumer    string
+	Interaction string
+	Mismatches  []string
+}
+
+func (p Result) Passed() bool {
+	return len(p.Mismatches) == 0
+}
+
+type Report struct {
+	Results []Result
+}
+
+func (p Report) Failed() bool {
+	return len(p.Broken()) > 0
+}
+
+// Consumers with at least one interaction the provider does not satisfy, sorted by name
+func (p Report) Broken() []string {
+	broken := make(map[string]bool)
+	for _, result := range p.Results {
+		if !result.Passed() {
+			broken[result.Consumer] = true
+		}
+	}
+	result := make([]string, 0, len(broken))
+	for consumer := range broken {
+		result = append(result, consumer)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (p Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CONSUMER\tINTERACTION\tRESULT\t")
+	for _, result := range p.Results {
+		status := "OK"
+		if !result.Passed() {
+			status = "BROKEN"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t\n", result.Consumer, result.Interaction, status)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	for _, result := range p.Results {
+		if result.Passed() {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s: %s\n", result.Consumer, result.Interaction)
+		for _, mismatch := range result.Mismatches {
+			fmt.Fprintf(w, "  %s\n", mismatch)
+		}
+	}
+	broken := p.Broken()
+	if len(broken) == 0 {
+		_, err := fmt.Fprintf(w, "\nall %d interactions are satisfied\n", len(p.Results))
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\nconsumers that would break: %s\n", strings.Join(broken, ", "))
+	return err
+}
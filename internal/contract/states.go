@@ -0,0 +1,126 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/apiclient"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/stub"
+)
+
+const (
+	STATE_USER_EXISTS    string = "a user exists"
+	STATE_USER_LOGGED_IN string = "a user is logged in"
+	STATE_TAG_EXISTS     string = "a tag exists"
+	STATE_NOTE_EXISTS    string = "a note exists"
+)
+
+// Sets up the data an interaction relies on through the API of the provider and returns the variables it created,
+// e.g. noteId. Params of the contract override the defaults of the handler.
+type StateHandler func(ctx context.Context, provider Provider, params map[string]any) (map[string]any, error)
+
+// Enum values the provider states create entities with
+type Fixtures struct {
+	UserRole  string
+	UserState string
+	TagState  string
+	NoteState string
+}
+
+// First values of the enums of the stub, the suite passes the constants of the API instead
+func DefaultFixtures() Fixtures {
+	enums := stub.DefaultEnums()
+	return Fixtures{
+		UserRole:  enums.UserRoles[0],
+		UserState: enums.UserStates[0],
+		TagState:  enums.TagStates[0],
+		NoteState: enums.NoteStates[0],
+	}
+}
+
+func States(fixtures Fixtures) map[string]StateHandler {
+	createUser := func(ctx context.Context, provider Provider, params map[string]any) (map[string]any, error) {
+		login := apiclient.Unique("contract-user")
+		vars := merge(map[string]any{
+			"login":    login,
+			"email":    login + "@contracts.qa",
+			"password": "Contract password 1",
+			"role":     fixtures.UserRole,
+			"state":    fixtures.UserState,
+		}, params)
+		id, err := apiclient.Create(ctx, provider, "", "/users", map[string]any{
+			"Login":    vars["login"],
+			"Email":    vars["email"],
+			"Password": vars["password"],
+			"Role":     vars["role"],
+			"State":    vars["state"],
+		})
+		vars["userId"] = id
+		return vars, err
+	}
+	createTag := func(ctx context.Context, provider Provider, params map[string]any) (map[string]any, error) {
+		vars := merge(map[string]any{"tagName": apiclient.Unique("contract-tag"), "tagState": fixtures.TagState}, params)
+		id, err := apiclient.Create(ctx, provider, "", "/tags", map[string]any{"Name": vars["tagName"], "State": vars["tagState"]})
+		vars["tagId"] = id
+		return vars, err
+	}
+
+	return map[string]StateHandler{
+		STATE_USER_EXISTS: createUser,
+		STATE_TAG_EXISTS:  createTag,
+		STATE_USER_LOGGED_IN: func(ctx context.Context, provider Provider, params map[string]any) (map[string]any, error) {
+			vars, err := createUser(ctx, provider, params)
+			if err != nil {
+				return nil, err
+			}
+			body, err := apiclient.Expect(ctx, provider, http.MethodPost, "/auth/login", "", map[string]any{"Email": vars["email"], "Password": vars["password"]}, http.StatusOK)
+			if err != nil {
+				return nil, err
+			}
+			var tokens struct {
+				AccessToken  string
+				RefreshToken string
+			}
+			if err := json.Unmarshal([]byte(body), &tokens); err != nil {
+				return nil, fmt.Errorf("unable to parse login response: %v", err)
+			}
+			vars["accessToken"], vars["refreshToken"] = tokens.AccessToken, tokens.RefreshToken
+			return vars, nil
+		},
+		STATE_NOTE_EXISTS: func(ctx context.Context, provider Provider, params map[string]any) (map[string]any, error) {
+			user, err := createUser(ctx, provider, nil)
+			if err != nil {
+				return nil, err
+			}
+			tag, err := createTag(ctx, provider, nil)
+			if err != nil {
+				return nil, err
+			}
+			vars := merge(map[string]any{
+				"text":      "Contract note text",
+				"topic":     "Contract note topic",
+				"noteState": fixtures.NoteState,
+				"userId":    user["userId"],
+				"tagId":     tag["tagId"],
+			}, params)
+			id, err := apiclient.Create(ctx, provider, "", "/notes", map[string]any{
+				"Text":   vars["text"],
+				"Topic":  vars["topic"],
+				"TagId":  vars["tagId"],
+				"UserId": vars["userId"],
+				"State":  vars["noteState"],
+			})
+			vars["noteId"] = id
+			return vars, err
+		},
+	}
+}
+
+func merge(defaults map[string]any, params map[string]any) map[string]any {
+	for name, value := range params {
+		defaults[name] = value
+	}
+	return defaults
+}
@@ -0,0 +1,129 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/apiclient"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/cassette"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/scenario"
+)
+
+// The API under verification, e.g. a difftest.Target for an in-process router or a remote URL
+type Provider = apiclient.Target
+
+type ProviderFunc = apiclient.TargetFunc
+
+type Verifier struct {
+	Provider Provider
+	States   map[string]StateHandler
+	// Called before every interaction, e.g. to recreate the DB of an in-process provider
+	Reset func() error
+}
+
+func NewVerifier(provider Provider, fixtures Fixtures) *Verifier {
+	return &Verifier{Provider: provider, States: States(fixtures)}
+}
+
+func (p *Verifier) Verify(ctx context.Context, contracts []*Contract) Report {
+	var report Report
+	for _, c := range contracts {
+		for _, interaction := range c.Interactions {
+			report.Results = append(report.Results, p.VerifyInteraction(ctx, c, interaction))
+		}
+	}
+	return report
+}
+
+func (p *Verifier) VerifyInteraction(ctx context.Context, c *Contract, interaction Interaction) Result {
+	result := Result{Consumer: c.Consumer, Interaction: interaction.Description}
+	fail := func(format string, args ...any) Result {
+		result.Mismatches = append(result.Mismatches, fmt.Sprintf(format, args...))
+		return result
+	}
+	if p.Reset != nil {
+		if err := p.Reset(); err != nil {
+			return fail("unable to reset the provider: %v", err)
+		}
+	}
+
+	vars := make(scenario.Vars)
+	for _, state := range interaction.Given {
+		handler, ok := p.States[state.Name]
+		if !ok {
+			return fail("unknown provider state '%s'", state.Name)
+		}
+		created, err := handler(ctx, p.Provider, state.Params)
+		if err != nil {
+			return fail("unable to set up '%s': %v", state.Name, err)
+		}
+		for name, value := range created {
+			vars[name] = value
+		}
+	}
+
+	req, err := request(vars, interaction.Request)
+	if err != nil {
+		return fail("%v", err)
+	}
+	resp, err := p.Provider.Do(ctx, req)
+	if err != nil {
+		return fail("%v", err)
+	}
+	if resp.Status != interaction.Response.Status {
+		fail("status is %d, expected %d, body: %s", resp.Status, interaction.Response.Status, resp.Body)
+	}
+	if interaction.Response.Body == nil {
+		return result
+	}
+	example, err := vars.Resolve(interaction.Response.Body)
+	if err != nil {
+		return fail("%v", err)
+	}
+	var actual any
+	if err := json.Unmarshal([]byte(resp.Body), &actual); err != nil {
+		return fail("body is not JSON: %s", resp.Body)
+	}
+	result.Mismatches = append(result.Mismatches, Match(example, actual, interaction.Response.Matchers)...)
+	return result
+}
+
+func request(vars scenario.Vars, r Request) (cassette.Request, error) {
+	path, err := vars.Interpolate(r.Path)
+	if err != nil {
+		return cassette.Request{}, err
+	}
+	result := cassette.Request{Method: r.Method, Url: path, Header: http.Header{}}
+	for name, value := range r.Headers {
+		value, err := vars.Interpolate(value)
+		if err != nil {
+			return cassette.Request{}, err
+		}
+		result.Header.Set(name, value)
+	}
+	if r.Body != nil {
+		body, err := vars.Resolve(r.Body)
+		if err != nil {
+			return cassette.Request{}, err
+		}
+		data, err := json.Marshal(body)
+		if err != nil {
+			return cassette.Request{}, err
+		}
+		result.Body = string(data)
+		result.Header.Set("Content-Type", "application/json")
+	}
+	return result, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	result := make([]string, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,62 @@
+package controlplane
+
+import (
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/runner"
+)
+
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"duration": func(value time.Duration) string { return value.Round(time.Millisecond).String() },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>QA run {{.Run.Id}}: {{.Run.Status}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+td, th { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+.pass { color: #1a7f37; } .fail { color: #cf222e; } .flaky { color: #9a6700; } .skip { color: #6e7781; }
+pre { background: #f6f8fa; padding: 8px; overflow-x: auto; }
+</style>
+</head>
+<body>
+<h1>QA run {{.Run.Id}}: {{.Run.Status}}</h1>
+<p>
+Suite: {{if .Run.Request.Suite}}{{.Run.Request.Suite}}{{else}}all{{end}},
+target: {{if .Run.Request.Target}}{{.Run.Request.Target}}{{else}}api{{end}},
+created {{.Run.Created.Format "2006-01-02 15:04:05"}} UTC{{if .Result}}, took {{duration .Result.Elapsed}}{{end}}
+</p>
+{{if .Run.Error}}<pre>{{.Run.Error}}</pre>{{end}}
+{{with .Result}}
+<p>{{$.Passed}} passed, {{$.Failed}} failed, {{$.Flaky}} flaky, {{$.Skipped}} skipped</p>
+{{range $pkg, $output := .PackageFailures}}<h3 class="fail">package {{$pkg}} failed</h3><pre>{{$output}}</pre>{{end}}
+<table>
+<tr><th>Test</th><th>Result</th><th>Attempts</th><th>Duration</th></tr>
+{{range .Tests}}<tr><td>{{.Name}}</td><td class="{{.Outcome}}">{{.Outcome}}{{if .Quarantined}} (quarantined){{end}}</td><td>{{.Attempts}}</td><td>{{duration .Elapsed}}</td></tr>
+{{end}}</table>
+{{range .Tests}}{{if .Output}}<h3 class="{{.Outcome}}">{{.Name}}</h3><pre>{{.Output}}</pre>
+{{end}}{{end}}
+{{end}}
+</body>
+</html>
+`))
+
+// Human readable report of the run, the tests are listed once the run finished
+func WriteHTML(w io.Writer, run Run) error {
+	data := struct {
+		Run                            Run
+		Result                         *runner.Result
+		Passed, Failed, Flaky, Skipped int
+	}{Run: run, Result: run.Result}
+	if run.Result != nil {
+		data.Passed = run.Result.Count(runner.OUTCOME_PASS)
+		data.Failed = run.Result.Count(runner.OUTCOME_FAIL)
+		data.Flaky = run.Result.Count(runner.OUTCOME_FLAKY)
+		data.Skipped = run.Result.Count(runner.OUTCOME_SKIP)
+	}
+	return htmlReport.Execute(w, data)
+}
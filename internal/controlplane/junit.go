@@ -0,0 +1,124 @@
+package controlplane
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/history"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/runner"
+)
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Errors    int         `xml:"errors,attr"`
+	Skipped   int         `xml:"skipped,attr"`
+	Time      string      `xml:"time,attr"`
+	Timestamp string      `xml:"timestamp,attr"`
+	Cases     []junitCase `xml:"testcase"`
+	// Output of a package that failed outside of tests
+	SystemErr string `xml:"system-err,omitempty"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *struct{}     `xml:"skipped,omitempty"`
+	// Flaky and quarantined tests are reported as passed with a note, CI should not fail on them
+	SystemOut string `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Output  string `xml:",chardata"`
+}
+
+// JUnit XML of the run, a test suite per package
+func WriteJUnit(w io.Writer, run Run) error {
+	result := run.Result
+	if result == nil {
+		result = &runner.Result{}
+	}
+	suites := junitSuites{Name: fmt.Sprintf("qa run %d", run.Id), Time: seconds(result.Elapsed.Seconds())}
+	byPackage := make(map[string]*junitSuite)
+	suite := func(pkg string) *junitSuite {
+		if _, ok := byPackage[pkg]; !ok {
+			byPackage[pkg] = &junitSuite{Name: pkg, Timestamp: result.Started.Format("2006-01-02T15:04:05")}
+		}
+		return byPackage[pkg]
+	}
+
+	for _, test := range result.Tests {
+		s := suite(test.Package)
+		testCase := junitCase{Name: test.Name, Classname: test.Package, Time: seconds(test.Elapsed.Seconds())}
+		switch {
+		case test.Outcome == runner.OUTCOME_SKIP:
+			testCase.Skipped = &struct{}{}
+			s.Skipped++
+		case test.Outcome == runner.OUTCOME_FAIL && !test.Quarantined:
+			testCase.Failure = &junitFailure{Message: history.FailureMessage(test.Output), Output: test.Output}
+			s.Failures++
+		case test.Outcome == runner.OUTCOME_FAIL:
+			testCase.SystemOut = "quarantined, failed:\n" + test.Output
+		case test.Outcome == runner.OUTCOME_FLAKY:
+			testCase.SystemOut = fmt.Sprintf("flaky, passed on attempt %d", test.Attempts)
+		}
+		s.Tests++
+		s.Cases = append(s.Cases, testCase)
+	}
+	for pkg, output := range result.PackageFailures {
+		s := suite(pkg)
+		s.Errors++
+		s.SystemErr = output
+	}
+
+	var names []string
+	for name := range byPackage {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s := byPackage[name]
+		var elapsed float64
+		for _, test := range result.Tests {
+			if test.Package == name {
+				elapsed += test.Elapsed.Seconds()
+			}
+		}
+		s.Time = seconds(elapsed)
+		suites.Tests += s.Tests
+		suites.Failures += s.Failures
+		suites.Skipped += s.Skipped
+		suites.Suites = append(suites.Suites, *s)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func seconds(value float64) string {
+	return fmt.Sprintf("%.3f", value)
+}
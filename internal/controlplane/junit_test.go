@@ -0,0 +1,43 @@
+package controlplane
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/runner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	run := Run{Id: 7, Status: STATUS_FAILED, Result: &runner.Result{
+		Started: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+		Elapsed: 12500 * time.Millisecond,
+		Tests: []runner.TestResult{
+			{Package: "qa/test/integration", Name: "TestApiPing", Outcome: runner.OUTCOME_PASS, Attempts: 1, Elapsed: 250 * time.Millisecond},
+			{Package: "qa/test/integration", Name: "TestApiNotes/Create", Outcome: runner.OUTCOME_FAIL, Attempts: 3, Elapsed: time.Second,
+				Output: "=== RUN   TestApiNotes/Create\n    api_notes_test.go:42: expected 201, got <500>\n--- FAIL: TestApiNotes/Create (1.00s)\n"},
+			{Package: "qa/test/integration", Name: "TestApiAuthRefresh", Outcome: runner.OUTCOME_FAIL, Attempts: 3, Elapsed: 2 * time.Second, Quarantined: true,
+				Output: "    api_auth_test.go:10: token expired\n"},
+			{Package: "qa/test/contracts", Name: "TestContracts", Outcome: runner.OUTCOME_FLAKY, Attempts: 2, Elapsed: 500 * time.Millisecond},
+			{Package: "qa/test/contracts", Name: "TestSkipped", Outcome: runner.OUTCOME_SKIP, Attempts: 1},
+		},
+		PackageFailures: map[string]string{"qa/test/broken": "broken_test.go:5:28: undefined: undefined\n"},
+	}}
+	var body bytes.Buffer
+
+	assert.Nil(t, WriteJUnit(&body, run))
+
+	expected, err := os.ReadFile("testdata/junit.xml")
+	assert.Nil(t, err)
+	assert.Equal(t, string(expected), body.String())
+}
+
+func TestWriteJUnitWithoutResult(t *testing.T) {
+	var body bytes.Buffer
+
+	assert.Nil(t, WriteJUnit(&body, Run{Id: 1, Status: STATUS_ERROR}))
+
+	assert.Contains(t, body.String(), `<testsuites name="qa run 1" tests="0" failures="0" skipped="0" time="0.000"></testsuites>`)
+}
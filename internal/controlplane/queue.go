@@ -0,0 +1,253 @@
+package controlplane
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/runner"
+)
+
+var (
+	ErrQueueFull   = errors.New("the queue of runs is full")
+	ErrRunNotFound = errors.New("run not found")
+	ErrRunFinished = errors.New("run has already finished")
+)
+
+type Options struct {
+	// Options every run starts from, e.g. the module dir, retries and quarantine
+	Runner runner.Options
+	// Runs executed at the same time
+	Workers int
+	// Runs waiting for a worker, more are rejected
+	QueueSize int
+	// Finished runs kept for GET /runs/:id, the oldest are dropped
+	KeepFinished int
+	// Called after the tests of a run finished, e.g. to store it in the history
+	OnFinish func(Run)
+	// Base URLs of deployed APIs a run may target besides the in-process API and the stub
+	AllowedTargets []string
+}
+
+// Runs the suites on a fixed number of workers, waiting runs are kept in a bounded queue
+type Queue struct {
+	options Options
+	mutex   sync.Mutex
+	wakeup  *sync.Cond
+	runs    map[int]*Run
+	// Ids in the order the runs were submitted
+	order   []int
+	pending []*Run
+	lastId  int
+	closed  bool
+	ctx     context.Context
+	stop    context.CancelFunc
+	workers sync.WaitGroup
+	// runner.Run, tests replace it
+	runTests func(context.Context, runner.Options) (runner.Result, error)
+}
+
+func NewQueue(options Options) *Queue {
+	return newQueue(options, runner.Run)
+}
+
+func newQueue(options Options, runTests func(context.Context, runner.Options) (runner.Result, error)) *Queue {
+	if options.Workers <= 0 {
+		options.Workers = 1
+	}
+	ctx, stop := context.WithCancel(context.Background())
+	result := &Queue{options: options, runs: make(map[int]*Run), ctx: ctx, stop: stop, runTests: runTests}
+	result.wakeup = sync.NewCond(&result.mutex)
+	for i := 0; i < options.Workers; i++ {
+		result.workers.Add(1)
+		go result.work()
+	}
+	return result
+}
+
+func (p *Queue) Submit(request RunRequest) (Run, error) {
+	if err := request.Validate(p.options.AllowedTargets); err != nil {
+		return Run{}, err
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.closed || len(p.pending) >= p.options.QueueSize {
+		return Run{}, ErrQueueFull
+	}
+	p.lastId++
+	run := &Run{Id: p.lastId, Status: STATUS_QUEUED, Request: request, Created: time.Now().UTC()}
+	p.runs[run.Id] = run
+	p.order = append(p.order, run.Id)
+	p.pending = append(p.pending, run)
+	p.wakeup.Signal()
+	return *run, nil
+}
+
+func (p *Queue) Get(id int) (Run, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	run, ok := p.runs[id]
+	if !ok {
+		return Run{}, ErrRunNotFound
+	}
+	return *run, nil
+}
+
+// All kept runs, the latest first
+func (p *Queue) List() []Run {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	result := make([]Run, 0, len(p.order))
+	for i := len(p.order) - 1; i >= 0; i-- {
+		result = append(result, *p.runs[p.order[i]])
+	}
+	return result
+}
+
+// A queued run is dropped from the queue, a running one has its `go test` killed
+func (p *Queue) Cancel(id int) (Run, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	run, ok := p.runs[id]
+	if !ok {
+		return Run{}, ErrRunNotFound
+	}
+	switch run.Status {
+	case STATUS_QUEUED:
+		for i, queued := range p.pending {
+			if queued == run {
+				p.pending = append(p.pending[:i], p.pending[i+1:]...)
+				break
+			}
+		}
+		now := time.Now().UTC()
+		run.Status = STATUS_CANCELLED
+		run.Finished = &now
+		result := *run
+		p.prune()
+		return result, nil
+	case STATUS_RUNNING:
+		// The worker sets the status once the tests stopped
+		run.cancel()
+	default:
+		return *run, ErrRunFinished
+	}
+	return *run, nil
+}
+
+// Cancels all the runs and waits for the workers to stop
+func (p *Queue) Close() {
+	p.mutex.Lock()
+	p.closed = true
+	now := time.Now().UTC()
+	for _, run := range p.pending {
+		run.Status = STATUS_CANCELLED
+		run.Finished = &now
+	}
+	p.pending = nil
+	p.stop()
+	p.wakeup.Broadcast()
+	p.mutex.Unlock()
+	p.workers.Wait()
+}
+
+func (p *Queue) work() {
+	defer p.workers.Done()
+	for {
+		run, ctx, ok := p.next()
+		if !ok {
+			return
+		}
+		options := p.options.Runner
+		options.Run = run.Request.Suite
+		options.Packages = run.Request.Packages
+		options.Env = append(append([]string{}, options.Env...), run.Request.env()...)
+		if options.Output == nil {
+			options.Output = io.Discard
+		}
+		result, err := p.runTests(ctx, options)
+		p.finish(run, result, err, ctx.Err())
+	}
+}
+
+// Waits for a queued run and marks it as running
+func (p *Queue) next() (*Run, context.Context, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for len(p.pending) == 0 && !p.closed {
+		p.wakeup.Wait()
+	}
+	if p.closed {
+		return nil, nil, false
+	}
+	run := p.pending[0]
+	p.pending = p.pending[1:]
+	ctx, cancel := context.WithCancel(p.ctx)
+	now := time.Now().UTC()
+	run.Status = STATUS_RUNNING
+	run.Started = &now
+	run.cancel = cancel
+	return run, ctx, true
+}
+
+func (p *Queue) finish(run *Run, result runner.Result, err error, cancelled error) {
+	p.mutex.Lock()
+	run.cancel()
+	now := time.Now().UTC()
+	run.Finished = &now
+	switch {
+	case cancelled != nil:
+		run.Status = STATUS_CANCELLED
+	case err != nil:
+		run.Status = STATUS_ERROR
+		run.Error = err.Error()
+	case result.Failed():
+		run.Status = STATUS_FAILED
+	default:
+		run.Status = STATUS_PASSED
+	}
+	if err == nil {
+		run.Result = &result
+	}
+	finished := *run
+	p.prune()
+	p.mutex.Unlock()
+
+	// A cancelled run has only part of the results
+	if p.options.OnFinish != nil && (finished.Status == STATUS_PASSED || finished.Status == STATUS_FAILED) {
+		p.options.OnFinish(finished)
+	}
+}
+
+// Drops the runs that finished first beyond KeepFinished, a long run that just finished is kept
+// even if it was submitted before them
+func (p *Queue) prune() {
+	if p.options.KeepFinished <= 0 {
+		return
+	}
+	var finished []*Run
+	for _, id := range p.order {
+		if run := p.runs[id]; run.Done() {
+			finished = append(finished, run)
+		}
+	}
+	if len(finished) <= p.options.KeepFinished {
+		return
+	}
+	sort.SliceStable(finished, func(i, j int) bool {
+		return finished[i].Finished.Before(*finished[j].Finished)
+	})
+	for _, run := range finished[:len(finished)-p.options.KeepFinished] {
+		delete(p.runs, run.Id)
+	}
+	kept := p.order[:0]
+	for _, id := range p.order {
+		if _, ok := p.runs[id]; ok {
+			kept = append(kept, id)
+		}
+	}
+	p.order = kept
+}
@@ -0,0 +1,179 @@
+package controlplane
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/runner"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	SUITE_PASS  string = "pass"
+	SUITE_FAIL  string = "fail"
+	SUITE_ERROR string = "error"
+	// Runs until it is cancelled
+	SUITE_BLOCK string = "block"
+)
+
+// Outcome of the run is picked by its suite, see SUITE_*
+func fakeTests(ctx context.Context, options runner.Options) (runner.Result, error) {
+	result := runner.Result{Started: time.Now().UTC()}
+	switch options.Run {
+	case SUITE_BLOCK:
+		<-ctx.Done()
+		return result, ctx.Err()
+	case SUITE_ERROR:
+		return result, fmt.Errorf("unable to run 'go test': executable file not found in $PATH")
+	case SUITE_FAIL:
+		result.Tests = []runner.TestResult{{Package: "./test/integration", Name: "TestApiPing", Outcome: runner.OUTCOME_FAIL, Attempts: 3}}
+	default:
+		result.Tests = []runner.TestResult{{Package: "./test/integration", Name: "TestApiPing", Outcome: runner.OUTCOME_PASS, Attempts: 1}}
+	}
+	return result, nil
+}
+
+func newTestQueue(t *testing.T, options Options) *Queue {
+	result := newQueue(options, fakeTests)
+	t.Cleanup(result.Close)
+	return result
+}
+
+func waitStatus(t *testing.T, queue *Queue, id int, status string) Run {
+	var run Run
+	assert.Eventually(t, func() bool {
+		run, _ = queue.Get(id)
+		return run.Status == status
+	}, 5*time.Second, time.Millisecond, "run %d is not %s", id, status)
+	return run
+}
+
+func TestQueueFinishedStatuses(t *testing.T) {
+	var mutex sync.Mutex
+	var stored []int
+	queue := newTestQueue(t, Options{QueueSize: 10, OnFinish: func(run Run) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		stored = append(stored, run.Id)
+	}})
+	tests := []struct {
+		Suite          string
+		ExpectedStatus string
+		ExpectedResult bool
+	}{
+		{Suite: SUITE_PASS, ExpectedStatus: STATUS_PASSED, ExpectedResult: true},
+		{Suite: SUITE_FAIL, ExpectedStatus: STATUS_FAILED, ExpectedResult: true},
+		{Suite: SUITE_ERROR, ExpectedStatus: STATUS_ERROR, ExpectedResult: false},
+	}
+	for _, test := range tests {
+		run, err := queue.Submit(RunRequest{Suite: test.Suite})
+		assert.Nil(t, err)
+		assert.Equal(t, STATUS_QUEUED, run.Status)
+
+		run = waitStatus(t, queue, run.Id, test.ExpectedStatus)
+
+		assert.Equal(t, test.ExpectedResult, run.Result != nil, test.Suite)
+		assert.NotNil(t, run.Started, test.Suite)
+		assert.NotNil(t, run.Finished, test.Suite)
+	}
+	run, _ := queue.Get(3)
+	assert.Equal(t, "unable to run 'go test': executable file not found in $PATH", run.Error)
+	// Runs that did not finish their tests are not stored
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.Equal(t, []int{1, 2}, stored)
+}
+
+func TestQueueCancel(t *testing.T) {
+	queue := newTestQueue(t, Options{QueueSize: 10})
+	running, _ := queue.Submit(RunRequest{Suite: SUITE_BLOCK})
+	queued, _ := queue.Submit(RunRequest{Suite: SUITE_PASS})
+	waitStatus(t, queue, running.Id, STATUS_RUNNING)
+
+	// A queued run never starts
+	run, err := queue.Cancel(queued.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, STATUS_CANCELLED, run.Status)
+	assert.Nil(t, run.Started)
+	assert.NotNil(t, run.Finished)
+
+	// A running run is cancelled once its tests stopped
+	run, err = queue.Cancel(running.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, STATUS_RUNNING, run.Status)
+	run = waitStatus(t, queue, running.Id, STATUS_CANCELLED)
+	assert.Nil(t, run.Result)
+
+	_, err = queue.Cancel(running.Id)
+	assert.ErrorIs(t, err, ErrRunFinished)
+	_, err = queue.Cancel(42)
+	assert.ErrorIs(t, err, ErrRunNotFound)
+	run, _ = queue.Get(queued.Id)
+	assert.Equal(t, STATUS_CANCELLED, run.Status)
+}
+
+func TestQueueFull(t *testing.T) {
+	queue := newTestQueue(t, Options{QueueSize: 1})
+	running, _ := queue.Submit(RunRequest{Suite: SUITE_BLOCK})
+	waitStatus(t, queue, running.Id, STATUS_RUNNING)
+	_, err := queue.Submit(RunRequest{Suite: SUITE_PASS})
+	assert.Nil(t, err)
+
+	_, err = queue.Submit(RunRequest{Suite: SUITE_PASS})
+
+	assert.ErrorIs(t, err, ErrQueueFull)
+}
+
+func TestQueueClose(t *testing.T) {
+	queue := newQueue(Options{QueueSize: 10}, fakeTests)
+	running, _ := queue.Submit(RunRequest{Suite: SUITE_BLOCK})
+	waitStatus(t, queue, running.Id, STATUS_RUNNING)
+	queued, _ := queue.Submit(RunRequest{Suite: SUITE_PASS})
+
+	queue.Close()
+
+	for _, id := range []int{running.Id, queued.Id} {
+		run, _ := queue.Get(id)
+		assert.Equal(t, STATUS_CANCELLED, run.Status)
+	}
+	_, err := queue.Submit(RunRequest{Suite: SUITE_PASS})
+	assert.ErrorIs(t, err, ErrQueueFull)
+}
+
+func TestQueuePrune(t *testing.T) {
+	queue := newTestQueue(t, Options{QueueSize: 10, KeepFinished: 2})
+	running, _ := queue.Submit(RunRequest{Suite: SUITE_BLOCK})
+	waitStatus(t, queue, running.Id, STATUS_RUNNING)
+	var queued []int
+	for i := 0; i < 3; i++ {
+		run, _ := queue.Submit(RunRequest{Suite: SUITE_PASS})
+		queued = append(queued, run.Id)
+	}
+
+	// Cancelled runs count as finished
+	for _, id := range queued {
+		_, err := queue.Cancel(id)
+		assert.Nil(t, err)
+	}
+
+	var ids []int
+	for _, run := range queue.List() {
+		ids = append(ids, run.Id)
+	}
+	// Runs that did not finish are kept
+	assert.Equal(t, []int{queued[2], queued[1], running.Id}, ids)
+	_, err := queue.Get(queued[0])
+	assert.ErrorIs(t, err, ErrRunNotFound)
+
+	// The run submitted first finished last, it is kept for the clients that poll it
+	queue.Cancel(running.Id)
+	waitStatus(t, queue, running.Id, STATUS_CANCELLED)
+	ids = nil
+	for _, run := range queue.List() {
+		ids = append(ids, run.Id)
+	}
+	assert.Equal(t, []int{queued[2], running.Id}, ids)
+}
@@ -0,0 +1,86 @@
+package controlplane
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/runner"
+)
+
+const (
+	STATUS_QUEUED    string = "queued"
+	STATUS_RUNNING   string = "running"
+	STATUS_PASSED    string = "passed"
+	STATUS_FAILED    string = "failed"
+	STATUS_CANCELLED string = "cancelled"
+	// The tests could not be run, e.g. `go` is missing
+	STATUS_ERROR string = "error"
+
+	TARGET_API  string = "api"
+	TARGET_STUB string = "stub"
+)
+
+type RunRequest struct {
+	// -run pattern of the tests, all if empty
+	Suite    string
+	Packages []string
+	// api runs the API in-process, stub runs the in-memory fake, an http(s) URL runs against a deployed API
+	Target string
+}
+
+// Deployed APIs the runs may target are listed in allowedTargets, the suite logs in with the credentials
+// of .env.test and must not send them anywhere else
+func (p RunRequest) Validate(allowedTargets []string) error {
+	for _, pkg := range p.Packages {
+		// Only packages of the module, `go test` is not given paths outside of it
+		if !strings.HasPrefix(pkg, "./") || strings.Contains(pkg, "/../") || strings.HasSuffix(pkg, "/..") {
+			return fmt.Errorf("wrong package '%s', expected a path inside the module like '%s'", pkg, runner.DEFAULT_PACKAGE)
+		}
+	}
+	switch p.Target {
+	case "", TARGET_API, TARGET_STUB:
+		return nil
+	}
+	for _, allowed := range allowedTargets {
+		if strings.TrimSuffix(p.Target, "/") == strings.TrimSuffix(allowed, "/") {
+			return nil
+		}
+	}
+	return fmt.Errorf("wrong target '%s', expected '%s', '%s' or one of the allowed URLs %v", p.Target, TARGET_API, TARGET_STUB, allowedTargets)
+}
+
+// Environment the tests pick the target from, see test/integration/start_test.go
+func (p RunRequest) env() []string {
+	switch p.Target {
+	case "", TARGET_API:
+		return nil
+	case TARGET_STUB:
+		return []string{"QA_TARGET=stub"}
+	default:
+		return []string{"QA_TARGET_URL=" + p.Target}
+	}
+}
+
+type Run struct {
+	Id       int
+	Status   string
+	Request  RunRequest
+	Created  time.Time
+	Started  *time.Time `json:",omitempty"`
+	Finished *time.Time `json:",omitempty"`
+	Error    string     `json:",omitempty"`
+	// Set once the tests finished
+	Result *runner.Result `json:",omitempty"`
+
+	cancel context.CancelFunc
+}
+
+func (p *Run) Done() bool {
+	switch p.Status {
+	case STATUS_PASSED, STATUS_FAILED, STATUS_CANCELLED, STATUS_ERROR:
+		return true
+	}
+	return false
+}
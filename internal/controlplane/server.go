@@ -0,0 +1,145 @@
+package controlplane
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	REPORT_JUNIT string = "junit"
+	REPORT_HTML  string = "html"
+)
+
+// HTTP API over the queue of runs, so other tools can start and inspect QA runs
+type Server struct {
+	queue  *Queue
+	router *gin.Engine
+}
+
+func NewServer(queue *Queue) *Server {
+	result := &Server{queue: queue}
+	result.router = result.setupRouter()
+	return result
+}
+
+func (p *Server) setupRouter() *gin.Engine {
+	r := gin.New()
+	r.Use(gin.Recovery())
+	r.GET("/ping", func(c *gin.Context) {
+		c.JSON(http.StatusOK, "Pong")
+	})
+	r.GET("/runs", p.listRuns)
+	r.POST("/runs", p.startRun)
+	r.GET("/runs/:id", p.getRun)
+	r.GET("/runs/:id/report", p.getReport)
+	r.DELETE("/runs/:id", p.cancelRun)
+	return r
+}
+
+func (p *Server) Router() *gin.Engine {
+	return p.router
+}
+
+func (p *Server) listRuns(c *gin.Context) {
+	c.JSON(http.StatusOK, p.queue.List())
+}
+
+func (p *Server) startRun(c *gin.Context) {
+	var request RunRequest
+	// An empty body runs the whole suite against the API
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, "Error during parsing of HTTP request body. Please check it format correctness: missed brackets, double quotes, commas, matching of names and data types and etc")
+			return
+		}
+	}
+	run, err := p.queue.Submit(request)
+	switch {
+	case errors.Is(err, ErrQueueFull):
+		c.JSON(http.StatusServiceUnavailable, err.Error())
+	case err != nil:
+		c.JSON(http.StatusBadRequest, err.Error())
+	default:
+		c.Header("Location", "/runs/"+strconv.Itoa(run.Id))
+		c.JSON(http.StatusAccepted, run)
+	}
+}
+
+func (p *Server) getRun(c *gin.Context) {
+	run, ok := p.run(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
+
+// ?format=junit (default) or ?format=html
+func (p *Server) getReport(c *gin.Context) {
+	run, ok := p.run(c)
+	if !ok {
+		return
+	}
+	if !run.Done() {
+		c.JSON(http.StatusConflict, "The run has not finished yet")
+		return
+	}
+	var body bytes.Buffer
+	switch format := c.DefaultQuery("format", REPORT_JUNIT); format {
+	case REPORT_JUNIT:
+		if err := WriteJUnit(&body, run); err != nil {
+			c.JSON(http.StatusInternalServerError, err.Error())
+			return
+		}
+		c.Data(http.StatusOK, "application/xml; charset=utf-8", body.Bytes())
+	case REPORT_HTML:
+		if err := WriteHTML(&body, run); err != nil {
+			c.JSON(http.StatusInternalServerError, err.Error())
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", body.Bytes())
+	default:
+		c.JSON(http.StatusBadRequest, "Wrong 'format' value. Possible values: [junit html]")
+	}
+}
+
+func (p *Server) cancelRun(c *gin.Context) {
+	id, ok := p.id(c)
+	if !ok {
+		return
+	}
+	run, err := p.queue.Cancel(id)
+	switch {
+	case errors.Is(err, ErrRunNotFound):
+		c.JSON(http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrRunFinished):
+		c.JSON(http.StatusConflict, err.Error())
+	default:
+		c.JSON(http.StatusAccepted, run)
+	}
+}
+
+func (p *Server) run(c *gin.Context) (Run, bool) {
+	id, ok := p.id(c)
+	if !ok {
+		return Run{}, false
+	}
+	run, err := p.queue.Get(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, err.Error())
+		return Run{}, false
+	}
+	return run, true
+}
+
+func (p *Server) id(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, "Wrong format of the run id")
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,132 @@
+package controlplane
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer(t *testing.T, options Options) (*Server, *Queue) {
+	gin.SetMode(gin.TestMode)
+	queue := newTestQueue(t, options)
+	return NewServer(queue), queue
+}
+
+func serve(server *Server, method string, url string, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, url, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	server.Router().ServeHTTP(w, req)
+	return w
+}
+
+func TestServerStartRun(t *testing.T) {
+	server, queue := newTestServer(t, Options{QueueSize: 10, AllowedTargets: []string{"https://staging.example.com"}})
+	tests := []struct {
+		Name             string
+		Body             string
+		ExpectedCode     int
+		ExpectedLocation string
+		ExpectedError    string
+	}{
+		{Name: "EmptyBody", Body: "", ExpectedCode: http.StatusAccepted, ExpectedLocation: "/runs/1"},
+		{Name: "Suite", Body: `{"Suite":"pass","Target":"stub"}`, ExpectedCode: http.StatusAccepted, ExpectedLocation: "/runs/2"},
+		{Name: "AllowedTarget", Body: `{"Suite":"pass","Target":"https://staging.example.com/"}`, ExpectedCode: http.StatusAccepted, ExpectedLocation: "/runs/3"},
+		{Name: "WrongBody", Body: `{"Suite":`, ExpectedCode: http.StatusBadRequest},
+		{
+			Name: "WrongTarget", Body: `{"Target":"https://example.org"}`, ExpectedCode: http.StatusBadRequest,
+			ExpectedError: "wrong target 'https://example.org', expected 'api', 'stub' or one of the allowed URLs [https://staging.example.com]",
+		},
+		{
+			Name: "PackageOutsideOfModule", Body: `{"Packages":["../other/..."]}`, ExpectedCode: http.StatusBadRequest,
+			ExpectedError: "wrong package '../other/...', expected a path inside the module like './test/integration/...'",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			w := serve(server, http.MethodPost, "/runs", test.Body)
+
+			assert.Equal(t, test.ExpectedCode, w.Code)
+			assert.Equal(t, test.ExpectedLocation, w.Header().Get("Location"))
+			if test.ExpectedError != "" {
+				var message string
+				assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &message))
+				assert.Equal(t, test.ExpectedError, message)
+			}
+		})
+	}
+	// The empty body runs the whole suite against the API
+	waitStatus(t, queue, 1, STATUS_PASSED)
+	run, _ := queue.Get(1)
+	assert.Equal(t, RunRequest{}, run.Request)
+}
+
+func TestServerQueueFull(t *testing.T) {
+	server, queue := newTestServer(t, Options{QueueSize: 0})
+	assert.Equal(t, http.StatusServiceUnavailable, serve(server, http.MethodPost, "/runs", "").Code)
+	assert.Empty(t, queue.List())
+}
+
+func TestServerRuns(t *testing.T) {
+	server, queue := newTestServer(t, Options{QueueSize: 10})
+	serve(server, http.MethodPost, "/runs", `{"Suite":"fail"}`)
+	serve(server, http.MethodPost, "/runs", `{"Suite":"block"}`)
+	waitStatus(t, queue, 1, STATUS_FAILED)
+	waitStatus(t, queue, 2, STATUS_RUNNING)
+
+	w := serve(server, http.MethodGet, "/runs", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var runs []Run
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &runs))
+	assert.Equal(t, 2, len(runs))
+	assert.Equal(t, []string{STATUS_RUNNING, STATUS_FAILED}, []string{runs[0].Status, runs[1].Status})
+
+	w = serve(server, http.MethodGet, "/runs/1", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var run Run
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &run))
+	assert.Equal(t, "TestApiPing", run.Result.Tests[0].Name)
+
+	assert.Equal(t, http.StatusNotFound, serve(server, http.MethodGet, "/runs/42", "").Code)
+	assert.Equal(t, http.StatusBadRequest, serve(server, http.MethodGet, "/runs/first", "").Code)
+}
+
+func TestServerReport(t *testing.T) {
+	server, queue := newTestServer(t, Options{QueueSize: 10})
+	serve(server, http.MethodPost, "/runs", `{"Suite":"fail"}`)
+	serve(server, http.MethodPost, "/runs", `{"Suite":"block"}`)
+	waitStatus(t, queue, 1, STATUS_FAILED)
+	waitStatus(t, queue, 2, STATUS_RUNNING)
+
+	w := serve(server, http.MethodGet, "/runs/1/report", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/xml; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `<testsuites name="qa run 1" tests="1" failures="1"`)
+
+	w = serve(server, http.MethodGet, "/runs/1/report?format=html", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "TestApiPing")
+
+	assert.Equal(t, http.StatusBadRequest, serve(server, http.MethodGet, "/runs/1/report?format=pdf", "").Code)
+	assert.Equal(t, http.StatusConflict, serve(server, http.MethodGet, "/runs/2/report", "").Code)
+}
+
+func TestServerCancelRun(t *testing.T) {
+	server, queue := newTestServer(t, Options{QueueSize: 10})
+	serve(server, http.MethodPost, "/runs", `{"Suite":"block"}`)
+	waitStatus(t, queue, 1, STATUS_RUNNING)
+
+	assert.Equal(t, http.StatusAccepted, serve(server, http.MethodDelete, "/runs/1", "").Code)
+	waitStatus(t, queue, 1, STATUS_CANCELLED)
+
+	assert.Equal(t, http.StatusConflict, serve(server, http.MethodDelete, "/runs/1", "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(server, http.MethodDelete, "/runs/42", "").Code)
+}
@@ -0,0 +1,62 @@
+package dbconn
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+)
+
+const DEFAULT_ENV_FILE string = ".env.test"
+
+// Connection string from the same DATABASE_* variables the API and the tests use
+func DSNFromEnv() string {
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(os.Getenv("DATABASE_USER"), os.Getenv("DATABASE_PASSWORD")),
+		Host:     getEnv("DATABASE_HOST", "localhost") + ":" + getEnv("DATABASE_PORT", "5432"),
+		Path:     "/" + os.Getenv("DATABASE_NAME"),
+		RawQuery: "sslmode=" + getEnv("DATABASE_SSL_MODE", "disable"),
+	}
+	return dsn.String()
+}
+
+// Loads the env file if it exists, the variables that are already set win
+func LoadEnv(path string) error {
+	if path == "" {
+		return nil
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	if err := godotenv.Load(path); err != nil {
+		return fmt.Errorf("unable to load env file '%s': %v", path, err)
+	}
+	return nil
+}
+
+// Opens and pings the DB, an empty dsn means DSNFromEnv
+func Open(dsn string) (*sql.DB, error) {
+	if dsn == "" {
+		dsn = DSNFromEnv()
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open DB: %v", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("unable to connect to DB: %v", err)
+	}
+	return db, nil
+}
+
+func getEnv(name string, def string) string {
+	if value, ok := os.LookupEnv(name); ok && value != "" {
+		return value
+	}
+	return def
+}
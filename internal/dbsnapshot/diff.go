@@ -0,0 +1,136 @@
+package dbsnapshot
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	INSERTED string = "inserted"
+	UPDATED  string = "updated"
+	DELETED  string = "deleted"
+)
+
+type Change struct {
+	Kind    string
+	Table   string
+	Key     string
+	Columns []string
+	Before  Row
+	After   Row
+}
+
+// Short form used in assertions, e.g. "inserted tags#1"
+func (p Change) Id() string {
+	return p.Kind + " " + p.Table + "#" + p.Key
+}
+
+// Names of the columns whose values differ, only for updated rows
+func (p Change) Changed() []string {
+	var result []string
+	for _, column := range p.Columns {
+		if p.Before[column] != p.After[column] {
+			result = append(result, column)
+		}
+	}
+	return result
+}
+
+func (p Change) String() string {
+	switch p.Kind {
+	case INSERTED:
+		return "+ " + p.Table + "#" + p.Key + " " + p.After.String(p.Columns)
+	case DELETED:
+		return "- " + p.Table + "#" + p.Key + " " + p.Before.String(p.Columns)
+	}
+	var parts []string
+	for _, column := range p.Changed() {
+		parts = append(parts, column+": "+quote(p.Before[column])+" -> "+quote(p.After[column]))
+	}
+	return "~ " + p.Table + "#" + p.Key + " " + strings.Join(parts, ", ")
+}
+
+type Diff struct {
+	Changes []Change
+}
+
+func Compare(before Snapshot, after Snapshot) Diff {
+	var result Diff
+	for _, name := range tableNames(before, after) {
+		b, a := before[name], after[name]
+		columns := a.Columns
+		if columns == nil {
+			columns = b.Columns
+		}
+		for _, key := range sortedKeys(b.Rows) {
+			row, ok := a.Rows[key]
+			if !ok {
+				result.Changes = append(result.Changes, Change{Kind: DELETED, Table: name, Key: key, Columns: columns, Before: b.Rows[key]})
+				continue
+			}
+			change := Change{Kind: UPDATED, Table: name, Key: key, Columns: columns, Before: b.Rows[key], After: row}
+			if len(change.Changed()) > 0 {
+				result.Changes = append(result.Changes, change)
+			}
+		}
+		for _, key := range sortedKeys(a.Rows) {
+			if _, ok := b.Rows[key]; !ok {
+				result.Changes = append(result.Changes, Change{Kind: INSERTED, Table: name, Key: key, Columns: columns, After: a.Rows[key]})
+			}
+		}
+	}
+	return result
+}
+
+func tableNames(snapshots ...Snapshot) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, snapshot := range snapshots {
+		for name := range snapshot {
+			if !seen[name] {
+				seen[name] = true
+				result = append(result, name)
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (p Diff) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+func (p Diff) Count(kind string, table string) int {
+	result := 0
+	for _, change := range p.Changes {
+		if change.Kind == kind && change.Table == table {
+			result++
+		}
+	}
+	return result
+}
+
+// Sorted short forms of all changes, see Change.Id
+func (p Diff) Ids() []string {
+	result := make([]string, 0, len(p.Changes))
+	for _, change := range p.Changes {
+		result = append(result, change.Id())
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (p Diff) String() string {
+	if p.Empty() {
+		return "no changes"
+	}
+	var sb strings.Builder
+	for i, change := range p.Changes {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(change.String())
+	}
+	return sb.String()
+}
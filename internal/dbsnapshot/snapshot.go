@@ -0,0 +1,154 @@
+package dbsnapshot
+
+import (
+	"context"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	KEY_COLUMN string = "id"
+	NULL       string = "NULL"
+)
+
+var DEFAULT_TABLES = []string{"tasks", "tags", "users", "notes", "refresh_tokens"}
+
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// Column values of a row, formatted as text so that rows can be compared and printed
+type Row map[string]string
+
+type Table struct {
+	Name    string
+	Columns []string
+	// Rows by the value of the id column, tables without it are keyed by a hash of the whole row
+	Rows map[string]Row
+}
+
+type Snapshot map[string]Table
+
+func Take(ctx context.Context, queryer Queryer, tables ...string) (Snapshot, error) {
+	if len(tables) == 0 {
+		tables = DEFAULT_TABLES
+	}
+	result := make(Snapshot, len(tables))
+	for _, name := range tables {
+		table, err := takeTable(ctx, queryer, name)
+		if err != nil {
+			return nil, err
+		}
+		result[name] = table
+	}
+	return result, nil
+}
+
+func takeTable(ctx context.Context, queryer Queryer, name string) (Table, error) {
+	rows, err := queryer.QueryContext(ctx, "SELECT * FROM "+name)
+	if err != nil {
+		return Table{}, fmt.Errorf("error at reading table '%s': %v", name, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return Table{}, fmt.Errorf("error at reading columns of '%s': %v", name, err)
+	}
+	table := Table{Name: name, Columns: columns, Rows: make(map[string]Row)}
+	values := make([]any, len(columns))
+	pointers := make([]any, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return Table{}, fmt.Errorf("error at reading row of '%s': %v", name, err)
+		}
+		row := make(Row, len(columns))
+		for i, column := range columns {
+			row[column] = Format(values[i])
+		}
+		table.Rows[key(row, columns)] = row
+	}
+	if err := rows.Err(); err != nil {
+		return Table{}, fmt.Errorf("error at reading table '%s': %v", name, err)
+	}
+	return table, nil
+}
+
+// Text of a scanned column value, NULL for nil and UTC RFC 3339 for timestamps
+func Format(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return NULL
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(value)
+}
+
+func key(row Row, columns []string) string {
+	if id, ok := row[KEY_COLUMN]; ok {
+		return id
+	}
+	hash := sha1.New()
+	for _, column := range columns {
+		hash.Write([]byte(column + "=" + row[column] + "\x00"))
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:12]
+}
+
+// Text like {name: "Test Tag 1", state: "NEW"}, the key column is omitted
+func (p Row) String(columns []string) string {
+	var parts []string
+	for _, column := range columns {
+		if column == KEY_COLUMN {
+			continue
+		}
+		parts = append(parts, column+": "+quote(p[column]))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+func quote(value string) string {
+	if value == NULL {
+		return value
+	}
+	return fmt.Sprintf("%q", value)
+}
+
+func sortedKeys(rows map[string]Row) []string {
+	result := make([]string, 0, len(rows))
+	for k := range rows {
+		result = append(result, k)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return lessKey(result[i], result[j])
+	})
+	return result
+}
+
+// Numeric ids are ordered as numbers
+func lessKey(a string, b string) bool {
+	if len(a) != len(b) && isDigits(a) && isDigits(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return value != ""
+}
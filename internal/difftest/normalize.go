@@ -0,0 +1,101 @@
+package difftest
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/dbsnapshot"
+)
+
+const (
+	PLACEHOLDER_TOKEN     string = "<token>"
+	PLACEHOLDER_TIMESTAMP string = "<timestamp>"
+	PLACEHOLDER_VOLATILE  string = "<volatile>"
+)
+
+// Fields that differ on every run, matched case insensitively and without underscores,
+// so "RefreshTokenExpiredAt" also covers the refresh_token_expired_at column
+var DEFAULT_VOLATILE_FIELDS = []string{"AccessToken", "RefreshToken", "AccessTokenExpiredAt", "RefreshTokenExpiredAt", "Token", "ExpireAt"}
+
+var (
+	jwtPattern       = regexp.MustCompile(`^[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}$`)
+	timestampPattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}`)
+)
+
+type Normalizer struct {
+	fields map[string]bool
+}
+
+func NewNormalizer(volatileFields ...string) Normalizer {
+	result := Normalizer{fields: make(map[string]bool)}
+	for _, field := range volatileFields {
+		result.fields[fieldKey(field)] = true
+	}
+	return result
+}
+
+func fieldKey(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+func (p Normalizer) IsVolatile(field string) bool {
+	return p.fields[fieldKey(field)]
+}
+
+func (p Normalizer) Value(field string, value string) string {
+	switch {
+	case jwtPattern.MatchString(value):
+		return PLACEHOLDER_TOKEN
+	case timestampPattern.MatchString(value):
+		return PLACEHOLDER_TIMESTAMP
+	case p.IsVolatile(field):
+		return PLACEHOLDER_VOLATILE
+	}
+	return value
+}
+
+// Parsed JSON with volatile values replaced, nil if the body is not JSON
+func (p Normalizer) JSON(body string) any {
+	var value any
+	if err := json.Unmarshal([]byte(body), &value); err != nil {
+		return nil
+	}
+	return p.walk("", value)
+}
+
+func (p Normalizer) walk(field string, value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			v[key] = p.walk(key, item)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = p.walk(field, item)
+		}
+		return v
+	case string:
+		return p.Value(field, v)
+	}
+	return value
+}
+
+// Textual form of a DB change with volatile column values replaced
+func (p Normalizer) Change(change dbsnapshot.Change) string {
+	normalized := change
+	normalized.Before, normalized.After = p.row(change.Before), p.row(change.After)
+	return normalized.String()
+}
+
+func (p Normalizer) row(row dbsnapshot.Row) dbsnapshot.Row {
+	if row == nil {
+		return nil
+	}
+	result := make(dbsnapshot.Row, len(row))
+	for column, value := range row {
+		result[column] = p.Value(column, value)
+	}
+	return result
+}
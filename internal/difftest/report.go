@@ -0,0 +1,58 @@
+package difftest
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	DIVERGENCE_STATUS string = "status"
+	DIVERGENCE_BODY   string = "body"
+	DIVERGENCE_DB     string = "db"
+	DIVERGENCE_ERROR  string = "error"
+)
+
+type Divergence struct {
+	// Index of the step in the scenario stream
+	Step    int
+	Test    string
+	Request string
+	Kind    string
+	// JSON path of a body divergence, e.g. $.Data[0].Name
+	Path string `json:",omitempty"`
+	A    string
+	B    string
+}
+
+type Report struct {
+	A           string
+	B           string
+	Tests       int
+	Steps       int
+	Divergences []Divergence
+	Elapsed     time.Duration
+}
+
+func (p Report) Failed() bool {
+	return len(p.Divergences) > 0
+}
+
+func (p Report) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "%d steps of %d tests against %s (A) and %s (B) in %v\n\n", p.Steps, p.Tests, p.A, p.B, p.Elapsed.Round(time.Millisecond))
+	if !p.Failed() {
+		_, err := fmt.Fprintln(w, "no divergences")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STEP\tTEST\tREQUEST\tKIND\tPATH\tA\tB\t")
+	for _, d := range p.Divergences {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t\n", d.Step, d.Test, d.Request, d.Kind, d.Path, d.A, d.B)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%d divergences\n", len(p.Divergences))
+	return err
+}
@@ -0,0 +1,326 @@
+package difftest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/cassette"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/dbsnapshot"
+)
+
+type Step struct {
+	Test    string
+	Request cassette.Request
+	// Response from the cassette, used to learn which values of a target replace the recorded tokens
+	Recorded cassette.Response
+}
+
+// The recorded requests in the order they were made
+func FromCassettes(cassettes ...*cassette.Cassette) []Step {
+	var result []Step
+	for _, c := range cassettes {
+		for _, interaction := range c.Interactions {
+			result = append(result, Step{Test: interaction.Test, Request: interaction.Request, Recorded: interaction.Response})
+		}
+	}
+	return result
+}
+
+// Sends every step to both targets and compares status, normalized body and DB side effects
+type Runner struct {
+	A          *Target
+	B          *Target
+	Normalizer Normalizer
+}
+
+func NewRunner(a *Target, b *Target) *Runner {
+	return &Runner{A: a, B: b, Normalizer: NewNormalizer(DEFAULT_VOLATILE_FIELDS...)}
+}
+
+type outcome struct {
+	response cassette.Response
+	err      error
+	changes  []string
+}
+
+// State of one target while a test is replayed
+type session struct {
+	target *Target
+	// Recorded token -> token issued by the target
+	substitutions map[string]string
+}
+
+func (p *Runner) Run(ctx context.Context, steps []Step) (Report, error) {
+	start := time.Now()
+	report := Report{A: p.A.Name, B: p.B.Name, Steps: len(steps)}
+	sessions := []*session{{target: p.A}, {target: p.B}}
+	test := ""
+	for i, step := range steps {
+		if i == 0 || step.Test != test {
+			test = step.Test
+			report.Tests++
+			for _, s := range sessions {
+				if err := s.reset(); err != nil {
+					return report, err
+				}
+			}
+		}
+		var outcomes [2]outcome
+		for j, s := range sessions {
+			o, err := p.execute(ctx, s, step)
+			if err != nil {
+				return report, err
+			}
+			outcomes[j] = o
+		}
+		report.Divergences = append(report.Divergences, p.compare(i, step, outcomes[0], outcomes[1])...)
+	}
+	report.Elapsed = time.Since(start)
+	return report, nil
+}
+
+func (p *session) reset() error {
+	p.substitutions = make(map[string]string)
+	if p.target.Reset == nil {
+		return nil
+	}
+	if err := p.target.Reset(); err != nil {
+		return fmt.Errorf("error at resetting %s: %v", p.target.Name, err)
+	}
+	return nil
+}
+
+// Request errors are a part of the outcome, only failed DB snapshots stop the run
+func (p *Runner) execute(ctx context.Context, s *session, step Step) (outcome, error) {
+	var before dbsnapshot.Snapshot
+	var err error
+	if s.target.DB != nil {
+		if before, err = dbsnapshot.Take(ctx, s.target.DB); err != nil {
+			return outcome{}, fmt.Errorf("error at taking snapshot of %s: %v", s.target.Name, err)
+		}
+	}
+
+	var result outcome
+	result.response, result.err = s.target.Do(ctx, s.rewrite(step.Request))
+	if result.err == nil {
+		s.learn(p.Normalizer, step.Recorded, result.response)
+	}
+
+	if s.target.DB != nil {
+		after, err := dbsnapshot.Take(ctx, s.target.DB)
+		if err != nil {
+			return outcome{}, fmt.Errorf("error at taking snapshot of %s: %v", s.target.Name, err)
+		}
+		for _, change := range dbsnapshot.Compare(before, after).Changes {
+			result.changes = append(result.changes, p.Normalizer.Change(change))
+		}
+	}
+	return result, nil
+}
+
+func (p *session) rewrite(req cassette.Request) cassette.Request {
+	if len(p.substitutions) == 0 {
+		return req
+	}
+	replacements := make([]string, 0, 2*len(p.substitutions))
+	for recorded, actual := range p.substitutions {
+		replacements = append(replacements, recorded, actual)
+	}
+	replacer := strings.NewReplacer(replacements...)
+	result := req
+	result.Url = replacer.Replace(req.Url)
+	result.Body = replacer.Replace(req.Body)
+	result.Header = make(http.Header, len(req.Header))
+	for name, values := range req.Header {
+		for _, value := range values {
+			result.Header.Add(name, replacer.Replace(value))
+		}
+	}
+	return result
+}
+
+// Pairs the volatile values of the recorded and the actual response by path
+func (p *session) learn(normalizer Normalizer, recorded cassette.Response, actual cassette.Response) {
+	var r, a any
+	if json.Unmarshal([]byte(recorded.Body), &r) != nil || json.Unmarshal([]byte(actual.Body), &a) != nil {
+		return
+	}
+	actualLeaves := leaves("$", "", a)
+	for path, value := range leaves("$", "", r) {
+		actualValue, ok := actualLeaves[path]
+		if !ok || value.value == actualValue.value || value.value == "" {
+			continue
+		}
+		// Recorded cassettes have the tokens redacted to placeholders
+		if normalizer.IsVolatile(value.field) || jwtPattern.MatchString(value.value) || cassette.IsTokenPlaceholder(value.value) {
+			p.substitutions[value.value] = actualValue.value
+		}
+	}
+}
+
+type leaf struct {
+	field string
+	value string
+}
+
+func leaves(path string, field string, value any) map[string]leaf {
+	result := make(map[string]leaf)
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			for p, l := range leaves(path+"."+key, key, item) {
+				result[p] = l
+			}
+		}
+	case []any:
+		for i, item := range v {
+			for p, l := range leaves(fmt.Sprintf("%s[%d]", path, i), field, item) {
+				result[p] = l
+			}
+		}
+	case string:
+		result[path] = leaf{field: field, value: v}
+	}
+	return result
+}
+
+func (p *Runner) compare(i int, step Step, a outcome, b outcome) []Divergence {
+	divergence := Divergence{Step: i, Test: step.Test, Request: step.Request.String()}
+	with := func(kind string, path string, valueA string, valueB string) Divergence {
+		result := divergence
+		result.Kind, result.Path, result.A, result.B = kind, path, valueA, valueB
+		return result
+	}
+
+	if a.err != nil || b.err != nil {
+		if fmt.Sprint(a.err) != fmt.Sprint(b.err) {
+			return []Divergence{with(DIVERGENCE_ERROR, "", errorString(a.err), errorString(b.err))}
+		}
+		return nil
+	}
+
+	var result []Divergence
+	if a.response.Status != b.response.Status {
+		result = append(result, with(DIVERGENCE_STATUS, "", fmt.Sprint(a.response.Status), fmt.Sprint(b.response.Status)))
+	}
+	jsonA, jsonB := p.Normalizer.JSON(a.response.Body), p.Normalizer.JSON(b.response.Body)
+	if jsonA == nil || jsonB == nil {
+		if a.response.Body != b.response.Body {
+			result = append(result, with(DIVERGENCE_BODY, "", a.response.Body, b.response.Body))
+		}
+	} else {
+		for _, d := range diffJSON("$", jsonA, jsonB) {
+			result = append(result, with(DIVERGENCE_BODY, d.path, d.a, d.b))
+		}
+	}
+	onlyA, onlyB := difference(a.changes, b.changes), difference(b.changes, a.changes)
+	for _, change := range onlyA {
+		result = append(result, with(DIVERGENCE_DB, "", change, ""))
+	}
+	for _, change := range onlyB {
+		result = append(result, with(DIVERGENCE_DB, "", "", change))
+	}
+	return result
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+type jsonDifference struct {
+	path string
+	a    string
+	b    string
+}
+
+const MISSING string = "<missing>"
+
+func diffJSON(path string, a any, b any) []jsonDifference {
+	mapA, isMapA := a.(map[string]any)
+	mapB, isMapB := b.(map[string]any)
+	if isMapA && isMapB {
+		var result []jsonDifference
+		for _, key := range unionKeys(mapA, mapB) {
+			itemA, inA := mapA[key]
+			itemB, inB := mapB[key]
+			switch {
+			case !inA:
+				result = append(result, jsonDifference{path + "." + key, MISSING, encode(itemB)})
+			case !inB:
+				result = append(result, jsonDifference{path + "." + key, encode(itemA), MISSING})
+			default:
+				result = append(result, diffJSON(path+"."+key, itemA, itemB)...)
+			}
+		}
+		return result
+	}
+	sliceA, isSliceA := a.([]any)
+	sliceB, isSliceB := b.([]any)
+	if isSliceA && isSliceB {
+		var result []jsonDifference
+		for i := 0; i < len(sliceA) || i < len(sliceB); i++ {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(sliceA):
+				result = append(result, jsonDifference{itemPath, MISSING, encode(sliceB[i])})
+			case i >= len(sliceB):
+				result = append(result, jsonDifference{itemPath, encode(sliceA[i]), MISSING})
+			default:
+				result = append(result, diffJSON(itemPath, sliceA[i], sliceB[i])...)
+			}
+		}
+		return result
+	}
+	if encodedA, encodedB := encode(a), encode(b); encodedA != encodedB {
+		return []jsonDifference{{path, encodedA, encodedB}}
+	}
+	return nil
+}
+
+func encode(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func unionKeys(a map[string]any, b map[string]any) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, m := range []map[string]any{a, b} {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				result = append(result, key)
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Values of a missing from b, duplicates are counted
+func difference(a []string, b []string) []string {
+	counts := make(map[string]int)
+	for _, value := range b {
+		counts[value]++
+	}
+	var result []string
+	for _, value := range a {
+		if counts[value] > 0 {
+			counts[value]--
+			continue
+		}
+		result = append(result, value)
+	}
+	return result
+}
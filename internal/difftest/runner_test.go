@@ -0,0 +1,141 @@
+package difftest
+
+import (
+	"testing"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/cassette"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffJSON(t *testing.T) {
+	tests := []struct {
+		Name     string
+		A        string
+		B        string
+		Expected []jsonDifference
+	}{
+		{Name: "Equal", A: `{"Id": 1, "Tags": ["a", "b"]}`, B: `{"Tags": ["a", "b"], "Id": 1}`},
+		{
+			Name: "Fields", A: `{"Id": 1, "Name": "books", "Old": true}`, B: `{"Id": 2, "Name": "books", "New": null}`,
+			Expected: []jsonDifference{{"$.Id", "1", "2"}, {"$.New", MISSING, "null"}, {"$.Old", "true", MISSING}},
+		},
+		{
+			Name: "NestedFields", A: `{"Data": [{"Name": "a"}, {"Name": "b"}]}`, B: `{"Data": [{"Name": "a"}, {"Name": "c"}]}`,
+			Expected: []jsonDifference{{"$.Data[1].Name", `"b"`, `"c"`}},
+		},
+		{
+			Name: "LongerArray", A: `{"Data": [1]}`, B: `{"Data": [1, {"Id": 2}, 3]}`,
+			Expected: []jsonDifference{{"$.Data[1]", MISSING, `{"Id":2}`}, {"$.Data[2]", MISSING, "3"}},
+		},
+		{
+			Name: "ShorterArray", A: `[1, 2]`, B: `[]`,
+			Expected: []jsonDifference{{"$[0]", "1", MISSING}, {"$[1]", "2", MISSING}},
+		},
+		{
+			Name: "Types", A: `{"Id": "1", "Data": []}`, B: `{"Id": 1, "Data": {}}`,
+			Expected: []jsonDifference{{"$.Data", "[]", "{}"}, {"$.Id", `"1"`, "1"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			normalizer := NewNormalizer()
+
+			assert.Equal(t, test.Expected, diffJSON("$", normalizer.JSON(test.A), normalizer.JSON(test.B)))
+		})
+	}
+}
+
+func TestLearn(t *testing.T) {
+	normalizer := NewNormalizer(DEFAULT_VOLATILE_FIELDS...)
+	tests := []struct {
+		Name     string
+		Recorded string
+		Actual   string
+		Expected map[string]string
+	}{
+		{
+			Name:     "VolatileFields",
+			Recorded: `{"AccessToken": "recorded", "RefreshToken": "recorded refresh", "Name": "a"}`,
+			Actual:   `{"AccessToken": "actual", "RefreshToken": "actual refresh", "Name": "b"}`,
+			Expected: map[string]string{"recorded": "actual", "recorded refresh": "actual refresh"},
+		},
+		{
+			Name:     "JwtOfAnyField",
+			Recorded: `{"Data": [{"Session": "` + TEST_JWT + `"}]}`,
+			Actual:   `{"Data": [{"Session": "issued"}]}`,
+			Expected: map[string]string{TEST_JWT: "issued"},
+		},
+		{
+			Name:     "RedactedPlaceholders",
+			Recorded: `{"Session": "<token-1>"}`,
+			Actual:   `{"Session": "issued"}`,
+			Expected: map[string]string{"<token-1>": "issued"},
+		},
+		{
+			Name:     "SameOrMissing",
+			Recorded: `{"AccessToken": "same", "Token": "", "Data": [{"ExpireAt": "recorded"}]}`,
+			Actual:   `{"AccessToken": "same", "Token": "actual", "Data": []}`,
+			Expected: map[string]string{},
+		},
+		{Name: "NotJson", Recorded: "pong", Actual: `{"AccessToken": "actual"}`, Expected: map[string]string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			s := &session{substitutions: make(map[string]string)}
+
+			s.learn(normalizer, cassette.Response{Body: test.Recorded}, cassette.Response{Body: test.Actual})
+
+			assert.Equal(t, test.Expected, s.substitutions)
+		})
+	}
+}
+
+func TestLearnedTokensRewrite(t *testing.T) {
+	s := &session{substitutions: make(map[string]string)}
+	s.learn(NewNormalizer(DEFAULT_VOLATILE_FIELDS...), cassette.Response{Body: `{"AccessToken": "<token-1>"}`}, cassette.Response{Body: `{"AccessToken": "issued"}`})
+
+	req := s.rewrite(cassette.Request{Method: "GET", Url: "/tags?token=<token-1>", Header: map[string][]string{"Authorization": {"Bearer <token-1>"}}})
+
+	assert.Equal(t, "/tags?token=issued", req.Url)
+	assert.Equal(t, "Bearer issued", req.Header.Get("Authorization"))
+}
+
+func TestCompareMasksVolatileValues(t *testing.T) {
+	runner := NewRunner(&Target{Name: "a"}, &Target{Name: "b"})
+	step := Step{Test: "login", Request: cassette.Request{Method: "POST", Url: "/auth/login"}}
+	tests := []struct {
+		Name     string
+		A        cassette.Response
+		B        cassette.Response
+		Expected []Divergence
+	}{
+		{
+			Name:     "Tokens",
+			A:        cassette.Response{Status: 200, Body: `{"AccessToken": "a", "RefreshToken": "` + TEST_JWT + `", "CreateDate": "2022-03-01T10:00:00Z"}`},
+			B:        cassette.Response{Status: 200, Body: `{"AccessToken": "b", "RefreshToken": "` + TEST_JWT + `x", "CreateDate": "2022-03-02T11:00:00Z"}`},
+			Expected: nil,
+		},
+		{
+			Name: "StatusAndBody",
+			A:    cassette.Response{Status: 200, Body: `{"AccessToken": "a", "Role": "ADMIN"}`},
+			B:    cassette.Response{Status: 201, Body: `{"AccessToken": "b", "Role": "USER"}`},
+			Expected: []Divergence{
+				{Step: 3, Test: "login", Request: step.Request.String(), Kind: DIVERGENCE_STATUS, A: "200", B: "201"},
+				{Step: 3, Test: "login", Request: step.Request.String(), Kind: DIVERGENCE_BODY, Path: "$.Role", A: `"ADMIN"`, B: `"USER"`},
+			},
+		},
+		{
+			Name: "Text",
+			A:    cassette.Response{Status: 200, Body: "pong"},
+			B:    cassette.Response{Status: 200, Body: "ping"},
+			Expected: []Divergence{
+				{Step: 3, Test: "login", Request: step.Request.String(), Kind: DIVERGENCE_BODY, A: "pong", B: "ping"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Expected, runner.compare(3, step, outcome{response: test.A}, outcome{response: test.B}))
+		})
+	}
+}
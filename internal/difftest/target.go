@@ -0,0 +1,112 @@
+package difftest
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/cassette"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/dbsnapshot"
+)
+
+// One build of the API, either an in-process router or a base URL
+type Target struct {
+	Name    string
+	Handler http.Handler
+	BaseUrl string
+	Client  *http.Client
+	// DB of the target, side effects are not compared if it is nil
+	DB dbsnapshot.Queryer
+	// Called before the requests of every test, e.g. to recreate the DB
+	Reset func() error
+}
+
+func NewHandlerTarget(name string, handler http.Handler) *Target {
+	return &Target{Name: name, Handler: handler}
+}
+
+func NewUrlTarget(name string, baseUrl string) *Target {
+	return &Target{Name: name, BaseUrl: strings.TrimSuffix(baseUrl, "/"), Client: http.DefaultClient}
+}
+
+func (p *Target) Do(ctx context.Context, req cassette.Request) (cassette.Response, error) {
+	var body io.Reader
+	if req.Body != "" {
+		body = bytes.NewBufferString(req.Body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, p.BaseUrl+req.Url, body)
+	if err != nil {
+		return cassette.Response{}, err
+	}
+	httpReq.Header = req.Header.Clone()
+	if httpReq.Header == nil {
+		httpReq.Header = http.Header{}
+	}
+
+	if p.Handler != nil {
+		w := httptest.NewRecorder()
+		p.Handler.ServeHTTP(w, httpReq)
+		return cassette.Response{Status: w.Code, Body: w.Body.String()}, nil
+	}
+
+	resp, err := p.Client.Do(httpReq)
+	if err != nil {
+		return cassette.Response{}, fmt.Errorf("error at calling %s: %v", p.Name, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return cassette.Response{}, fmt.Errorf("error at reading response of %s: %v", p.Name, err)
+	}
+	return cassette.Response{Status: resp.StatusCode, Body: string(data)}, nil
+}
+
+// Reset that empties every table of the public schema and restarts the ids, except the liquibase changelog
+func TruncateReset(db *sql.DB) func() error {
+	return func() error {
+		rows, err := db.Query("SELECT tablename FROM pg_tables WHERE schemaname = 'public' AND tablename NOT LIKE 'databasechangelog%' ORDER BY tablename")
+		if err != nil {
+			return fmt.Errorf("unable to list tables: %v", err)
+		}
+		defer rows.Close()
+		var tables []string
+		for rows.Next() {
+			var table string
+			if err := rows.Scan(&table); err != nil {
+				return fmt.Errorf("unable to list tables: %v", err)
+			}
+			tables = append(tables, `"`+table+`"`)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("unable to list tables: %v", err)
+		}
+		if len(tables) == 0 {
+			return nil
+		}
+		if _, err := db.Exec("TRUNCATE " + strings.Join(tables, ", ") + " RESTART IDENTITY CASCADE"); err != nil {
+			return fmt.Errorf("unable to truncate tables: %v", err)
+		}
+		return nil
+	}
+}
+
+// Reset that POSTs to an endpoint of the deployment, e.g. a test-only hook that recreates its DB
+func UrlReset(client *http.Client, url string) func() error {
+	return func() error {
+		resp, err := client.Post(url, "application/json", nil)
+		if err != nil {
+			return fmt.Errorf("unable to call reset '%s': %v", url, err)
+		}
+		defer resp.Body.Close()
+		io.Copy(io.Discard, resp.Body)
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("unable to call reset '%s': status %d", url, resp.StatusCode)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,173 @@
+package explain
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+const NODE_TYPE_SEQ_SCAN string = "Seq Scan"
+
+// Node of the plan produced by EXPLAIN (FORMAT JSON)
+type Node struct {
+	NodeType     string  `json:"Node Type"`
+	RelationName string  `json:"Relation Name"`
+	IndexName    string  `json:"Index Name"`
+	StartupCost  float64 `json:"Startup Cost"`
+	TotalCost    float64 `json:"Total Cost"`
+	PlanRows     float64 `json:"Plan Rows"`
+	Filter       string  `json:"Filter"`
+	Plans        []Node  `json:"Plans"`
+}
+
+type Plan struct {
+	Query string
+	Args  []any
+	Root  Node
+}
+
+type explainOutput []struct {
+	Plan Node `json:"Plan"`
+}
+
+func Parse(data []byte) (Node, error) {
+	var output explainOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		return Node{}, fmt.Errorf("unable to parse EXPLAIN output: %v", err)
+	}
+	if len(output) == 0 {
+		return Node{}, fmt.Errorf("empty EXPLAIN output")
+	}
+	return output[0].Plan, nil
+}
+
+type Queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func Explain(ctx context.Context, queryer Queryer, query string, args ...any) (Plan, error) {
+	var data []byte
+	err := queryer.QueryRowContext(ctx, "EXPLAIN (FORMAT JSON) "+query, args...).Scan(&data)
+	if err != nil {
+		return Plan{}, fmt.Errorf("error at explaining query '%s': %v", query, err)
+	}
+	root, err := Parse(data)
+	if err != nil {
+		return Plan{}, err
+	}
+	return Plan{Query: query, Args: args, Root: root}, nil
+}
+
+func (p Node) Walk(f func(node Node)) {
+	f(p)
+	for _, child := range p.Plans {
+		child.Walk(f)
+	}
+}
+
+func (p Plan) SeqScans() []Node {
+	var result []Node
+	p.Root.Walk(func(node Node) {
+		if node.NodeType == NODE_TYPE_SEQ_SCAN {
+			result = append(result, node)
+		}
+	})
+	return result
+}
+
+type Thresholds struct {
+	MaxTotalCost float64
+	// Relations that are allowed to be scanned sequentially, e.g. small dictionaries
+	AllowSeqScan []string
+}
+
+type Violation struct {
+	Query   string
+	Message string
+}
+
+func (p Plan) Check(thresholds Thresholds) []Violation {
+	var result []Violation
+	for _, node := range p.SeqScans() {
+		if contains(thresholds.AllowSeqScan, node.RelationName) {
+			continue
+		}
+		result = append(result, Violation{
+			Query:   p.Query,
+			Message: fmt.Sprintf("sequential scan on '%s' (cost %.2f, rows %.0f, filter: %s)", node.RelationName, node.TotalCost, node.PlanRows, node.Filter),
+		})
+	}
+	if thresholds.MaxTotalCost > 0 && p.Root.TotalCost > thresholds.MaxTotalCost {
+		result = append(result, Violation{
+			Query:   p.Query,
+			Message: fmt.Sprintf("estimated total cost %.2f exceeds threshold %.2f", p.Root.TotalCost, thresholds.MaxTotalCost),
+		})
+	}
+	return result
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Text representation of the plan tree like the one printed by psql
+func (p Plan) String() string {
+	var sb strings.Builder
+	var write func(node Node, depth int)
+	write = func(node Node, depth int) {
+		sb.WriteString(strings.Repeat("  ", depth))
+		sb.WriteString("-> " + node.NodeType)
+		if node.RelationName != "" {
+			sb.WriteString(" on " + node.RelationName)
+		}
+		if node.IndexName != "" {
+			sb.WriteString(" using " + node.IndexName)
+		}
+		fmt.Fprintf(&sb, " (cost=%.2f..%.2f rows=%.0f)\n", node.StartupCost, node.TotalCost, node.PlanRows)
+		for _, child := range node.Plans {
+			write(child, depth+1)
+		}
+	}
+	write(p.Root, 0)
+	return sb.String()
+}
+
+type Result struct {
+	Name       string
+	Plan       Plan
+	Violations []Violation
+}
+
+func WriteReport(w io.Writer, results []Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "QUERY\tROOT NODE\tTOTAL COST\tSEQ SCANS\tSTATUS\t")
+	for _, r := range results {
+		status := "OK"
+		if len(r.Violations) > 0 {
+			status = "FAILED"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%.2f\t%d\t%s\t\n", r.Name, r.Plan.Root.NodeType, r.Plan.Root.TotalCost, len(r.Plan.SeqScans()), status)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	for _, r := range results {
+		if len(r.Violations) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s: %s\n%s", r.Name, r.Plan.Query, r.Plan.String())
+		for _, v := range r.Violations {
+			fmt.Fprintf(w, "  violation: %s\n", v.Message)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package faultproxy
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+)
+
+const BUFFER_SIZE int = 32 * 1024
+
+// Message types of the Postgres frontend protocol that start the execution of a statement
+var queryMessageTypes = map[byte]bool{'Q': true, 'P': true, 'B': true, 'E': true}
+
+// Faults applied to the traffic, the zero value forwards everything untouched
+type Fault struct {
+	// Delay of every chunk in both directions
+	Latency time.Duration
+	// New connections are closed right after they are accepted
+	Refuse bool
+	// A connection is closed right after a query is forwarded to the server, so the client never gets the result
+	DropOnQuery bool
+	// Probability of flipping a byte in a chunk sent by the server
+	CorruptRate float64
+}
+
+// TCP proxy that sits between the API and Postgres. Chunks are inspected as they are read,
+// so faults that depend on the protocol (DropOnQuery) only look at the first message of a chunk.
+type Proxy struct {
+	listener net.Listener
+	target   string
+
+	mutex  sync.Mutex
+	fault  Fault
+	random *rand.Rand
+	conns  map[net.Conn]struct{}
+	wg     sync.WaitGroup
+}
+
+func Start(listenAddr string, target string, seed int64) (*Proxy, error) {
+	listener, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return nil, fmt.Errorf("unable to start proxy at '%s': %v", listenAddr, err)
+	}
+	p := &Proxy{
+		listener: listener,
+		target:   target,
+		random:   rand.New(rand.NewSource(seed)),
+		conns:    make(map[net.Conn]struct{}),
+	}
+	p.wg.Add(1)
+	go p.serve()
+	return p, nil
+}
+
+func (p *Proxy) Addr() string {
+	return p.listener.Addr().String()
+}
+
+func (p *Proxy) Set(fault Fault) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.fault = fault
+}
+
+func (p *Proxy) Clear() {
+	p.Set(Fault{})
+}
+
+func (p *Proxy) Fault() Fault {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.fault
+}
+
+// Closes all open connections, e.g. to make a pool reconnect through a new fault or after one
+func (p *Proxy) DropAll() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for conn := range p.conns {
+		conn.Close()
+	}
+}
+
+func (p *Proxy) Close() error {
+	err := p.listener.Close()
+	p.DropAll()
+	p.wg.Wait()
+	return err
+}
+
+func (p *Proxy) serve() {
+	defer p.wg.Done()
+	for {
+		client, err := p.listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
+		if p.Fault().Refuse {
+			if tcp, ok := client.(*net.TCPConn); ok {
+				tcp.SetLinger(0)
+			}
+			client.Close()
+			continue
+		}
+		p.wg.Add(1)
+		go p.handle(client)
+	}
+}
+
+func (p *Proxy) handle(client net.Conn) {
+	defer p.wg.Done()
+	server, err := net.Dial("tcp", p.target)
+	if err != nil {
+		client.Close()
+		return
+	}
+	p.track(client, server)
+	defer p.untrack(client, server)
+
+	done := make(chan struct{}, 2)
+	go func() {
+		p.pipe(server, client, true)
+		done <- struct{}{}
+	}()
+	go func() {
+		p.pipe(client, server, false)
+		done <- struct{}{}
+	}()
+	<-done
+	client.Close()
+	server.Close()
+	<-done
+}
+
+func (p *Proxy) pipe(dst net.Conn, src net.Conn, fromClient bool) {
+	buffer := make([]byte, BUFFER_SIZE)
+	for {
+		n, err := src.Read(buffer)
+		if n > 0 {
+			chunk := buffer[:n]
+			fault := p.Fault()
+			if fault.Latency > 0 {
+				time.Sleep(fault.Latency)
+			}
+			if !fromClient && fault.CorruptRate > 0 {
+				p.corrupt(chunk, fault.CorruptRate)
+			}
+			if _, werr := dst.Write(chunk); werr != nil {
+				return
+			}
+			if fromClient && fault.DropOnQuery && queryMessageTypes[chunk[0]] {
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (p *Proxy) corrupt(chunk []byte, rate float64) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.random.Float64() >= rate {
+		return
+	}
+	i := p.random.Intn(len(chunk))
+	chunk[i] ^= byte(1 + p.random.Intn(255))
+}
+
+func (p *Proxy) track(conns ...net.Conn) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for _, conn := range conns {
+		p.conns[conn] = struct{}{}
+	}
+}
+
+func (p *Proxy) untrack(conns ...net.Conn) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for _, conn := range conns {
+		delete(p.conns, conn)
+	}
+}
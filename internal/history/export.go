@@ -0,0 +1,95 @@
+package history
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	EXPORT_JSON string = "json"
+	EXPORT_CSV  string = "csv"
+)
+
+// A test of a run, flat for dashboards
+type Row struct {
+	ApiVersion string    `json:"api_version"`
+	RunId      uint64    `json:"run_id"`
+	Commit     string    `json:"commit"`
+	Started    time.Time `json:"started"`
+	Test       string    `json:"test"`
+	Outcome    string    `json:"outcome"`
+	Attempts   int       `json:"attempts"`
+	DurationMs int64     `json:"duration_ms"`
+	// First line of the failure that is not a log of the test run itself
+	Failure string `json:"failure,omitempty"`
+}
+
+func Rows(runs []Run) []Row {
+	var result []Row
+	for _, run := range runs {
+		for _, test := range run.Tests {
+			result = append(result, Row{
+				ApiVersion: run.ApiVersion,
+				RunId:      run.Id,
+				Commit:     run.Commit,
+				Started:    run.Started,
+				Test:       test.Name,
+				Outcome:    test.Outcome,
+				Attempts:   test.Attempts,
+				DurationMs: test.Duration.Milliseconds(),
+				Failure:    FailureMessage(test.Failure),
+			})
+		}
+	}
+	return result
+}
+
+// First meaningful line of the failure output, `go test` status lines are skipped
+func FailureMessage(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "=== ") || strings.HasPrefix(line, "--- ") {
+			continue
+		}
+		return line
+	}
+	return ""
+}
+
+func Export(w io.Writer, runs []Run, format string) error {
+	rows := Rows(runs)
+	switch format {
+	case EXPORT_JSON:
+		if rows == nil {
+			rows = []Row{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(rows)
+	case EXPORT_CSV:
+		writer := csv.NewWriter(w)
+		writer.Write([]string{"api_version", "run_id", "commit", "started", "test", "outcome", "attempts", "duration_ms", "failure"})
+		for _, row := range rows {
+			writer.Write([]string{
+				row.ApiVersion,
+				strconv.FormatUint(row.RunId, 10),
+				row.Commit,
+				row.Started.Format(time.RFC3339),
+				row.Test,
+				row.Outcome,
+				strconv.Itoa(row.Attempts),
+				strconv.FormatInt(row.DurationMs, 10),
+				row.Failure,
+			})
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		return fmt.Errorf("unknown export format '%s', expected '%s' or '%s'", format, EXPORT_JSON, EXPORT_CSV)
+	}
+}
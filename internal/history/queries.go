@@ -0,0 +1,170 @@
+package history
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/runner"
+)
+
+// Failures of a test since it last passed
+type Streak struct {
+	Test string
+	// Runs that failed in a row, up to the latest one
+	Runs int
+	// First run of the streak
+	Since Run
+	// Latest run the test passed in, nil if it never passed
+	LastPass *Run
+	// Failure of the latest run
+	Failure string
+}
+
+// Commit of the first run of the streak, the commits between it and the last passing one are suspects.
+// Runs are taken in the order they were stored, not ordered by commit: after a run against an older
+// commit is stored, e.g. a rerun of a release branch, this is the commit of the earliest stored run, not the
+// earliest commit.
+func (p Streak) FirstFailingCommit() string {
+	return p.Since.Commit
+}
+
+// Tests that fail in the latest run, those that failed for the fewest runs first
+func StartedFailing(runs []Run) []Streak {
+	if len(runs) == 0 {
+		return nil
+	}
+	var result []Streak
+	for _, test := range runs[len(runs)-1].Tests {
+		if streak, ok := FirstFailing(runs, test.Name); ok {
+			result = append(result, streak)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Runs < result[j].Runs
+	})
+	return result
+}
+
+// Failure streak of the test up to the latest run it ran in, runs go in the order they were stored.
+// Runs that skipped or did not have the test neither break nor extend the streak.
+func FirstFailing(runs []Run, name string) (Streak, bool) {
+	result := Streak{Test: name}
+	for i := len(runs) - 1; i >= 0; i-- {
+		test, ok := runs[i].Test(name)
+		if !ok || test.Outcome == runner.OUTCOME_SKIP {
+			continue
+		}
+		if test.Outcome != runner.OUTCOME_FAIL {
+			lastPass := runs[i]
+			result.LastPass = &lastPass
+			break
+		}
+		if result.Runs == 0 {
+			result.Failure = test.Failure
+		}
+		result.Runs++
+		result.Since = runs[i]
+	}
+	return result, result.Runs > 0
+}
+
+type Trend struct {
+	Test string
+	// Average duration over the latest runs
+	Recent time.Duration
+	// Average duration over the runs before them
+	Previous time.Duration
+}
+
+// Relative change of the duration, 0.5 is 50% slower
+func (p Trend) Change() float64 {
+	if p.Previous == 0 {
+		return 0
+	}
+	return float64(p.Recent-p.Previous) / float64(p.Previous)
+}
+
+// Average durations of the tests over the latest window runs against the window before, the most slowed
+// down first. Only runs the test passed in count, tests without such runs in both windows are left out.
+func DurationTrends(runs []Run, window int, prefix string) []Trend {
+	if window <= 0 || len(runs) == 0 {
+		return nil
+	}
+	recentFrom := len(runs) - window
+	if recentFrom < 0 {
+		recentFrom = 0
+	}
+	previousFrom := recentFrom - window
+	if previousFrom < 0 {
+		previousFrom = 0
+	}
+	recent := averages(runs[recentFrom:], prefix)
+	previous := averages(runs[previousFrom:recentFrom], prefix)
+
+	var result []Trend
+	for name, duration := range recent {
+		before, ok := previous[name]
+		if !ok {
+			continue
+		}
+		result = append(result, Trend{Test: name, Recent: duration, Previous: before})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].Change(), result[j].Change()
+		if a != b {
+			return a > b
+		}
+		return result[i].Test < result[j].Test
+	})
+	return result
+}
+
+func averages(runs []Run, prefix string) map[string]time.Duration {
+	sums := make(map[string]time.Duration)
+	counts := make(map[string]int)
+	for _, run := range runs {
+		for _, test := range run.Tests {
+			if !strings.HasPrefix(test.Name, prefix) || test.Outcome != runner.OUTCOME_PASS {
+				continue
+			}
+			sums[test.Name] += test.Duration
+			counts[test.Name]++
+		}
+	}
+	result := make(map[string]time.Duration)
+	for name, sum := range sums {
+		result[name] = sum / time.Duration(counts[name])
+	}
+	return result
+}
+
+// Outcome of a test in a run
+type Point struct {
+	RunId    uint64
+	Commit   string
+	Started  time.Time
+	Outcome  string
+	Duration time.Duration
+}
+
+// Outcomes and durations of the test over the runs it ran in, the oldest first
+func Series(runs []Run, name string) []Point {
+	var result []Point
+	for _, run := range runs {
+		test, ok := run.Test(name)
+		if !ok {
+			continue
+		}
+		result = append(result, Point{RunId: run.Id, Commit: run.Commit, Started: run.Started, Outcome: test.Outcome, Duration: test.Duration})
+	}
+	return result
+}
+
+// Latest limit runs, all if limit is not positive
+func Latest(runs []Run, limit int) []Run {
+	if limit > 0 && len(runs) > limit {
+		return runs[len(runs)-limit:]
+	}
+	return runs
+}
@@ -0,0 +1,129 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/runner"
+	"github.com/stretchr/testify/assert"
+)
+
+// Runs of the commits a day apart, tests[i] are the tests of the i-th run
+func testRuns(commits []string, tests ...[]TestRecord) []Run {
+	var result []Run
+	for i, commit := range commits {
+		result = append(result, Run{Id: uint64(i + 1), Commit: commit, Started: time.Date(2026, 10, 1+i, 0, 0, 0, 0, time.UTC), Tests: tests[i]})
+	}
+	return result
+}
+
+func pass(name string, duration time.Duration) TestRecord {
+	return TestRecord{Name: name, Outcome: runner.OUTCOME_PASS, Duration: duration}
+}
+
+func fail(name string, failure string) TestRecord {
+	return TestRecord{Name: name, Outcome: runner.OUTCOME_FAIL, Failure: failure}
+}
+
+func skip(name string) TestRecord {
+	return TestRecord{Name: name, Outcome: runner.OUTCOME_SKIP}
+}
+
+func TestFirstFailing(t *testing.T) {
+	runs := testRuns(
+		[]string{"c1", "c2", "c3", "c4", "c5"},
+		[]TestRecord{pass("TestA", 0), pass("TestB", 0), fail("TestC", "old")},
+		[]TestRecord{fail("TestA", "first"), pass("TestB", 0), fail("TestC", "old")},
+		[]TestRecord{skip("TestA"), pass("TestB", 0), fail("TestC", "old")},
+		[]TestRecord{pass("TestB", 0), fail("TestC", "old")},
+		[]TestRecord{fail("TestA", "latest"), {Name: "TestB", Outcome: runner.OUTCOME_FLAKY}, fail("TestC", "latest")},
+	)
+	tests := []struct {
+		Test             string
+		ExpectedOk       bool
+		ExpectedRuns     int
+		ExpectedCommit   string
+		ExpectedLastPass string
+		ExpectedFailure  string
+	}{
+		// Skipped and missing runs do not break the streak
+		{Test: "TestA", ExpectedOk: true, ExpectedRuns: 2, ExpectedCommit: "c2", ExpectedLastPass: "c1", ExpectedFailure: "latest"},
+		// Flaky is not a failure
+		{Test: "TestB", ExpectedOk: false},
+		{Test: "TestC", ExpectedOk: true, ExpectedRuns: 5, ExpectedCommit: "c1", ExpectedFailure: "latest"},
+		{Test: "TestUnknown", ExpectedOk: false},
+	}
+	for _, test := range tests {
+		streak, ok := FirstFailing(runs, test.Test)
+
+		assert.Equal(t, test.ExpectedOk, ok, test.Test)
+		if !ok {
+			continue
+		}
+		assert.Equal(t, test.ExpectedRuns, streak.Runs, test.Test)
+		assert.Equal(t, test.ExpectedCommit, streak.FirstFailingCommit(), test.Test)
+		assert.Equal(t, test.ExpectedFailure, streak.Failure, test.Test)
+		if test.ExpectedLastPass == "" {
+			assert.Nil(t, streak.LastPass, test.Test)
+		} else {
+			assert.Equal(t, test.ExpectedLastPass, streak.LastPass.Commit, test.Test)
+		}
+	}
+}
+
+func TestFirstFailingInStoredOrder(t *testing.T) {
+	// c0 was run after c2, e.g. a rerun of an older commit
+	runs := testRuns(
+		[]string{"c1", "c2", "c0"},
+		[]TestRecord{pass("TestA", 0)},
+		[]TestRecord{fail("TestA", "")},
+		[]TestRecord{fail("TestA", "")},
+	)
+
+	streak, ok := FirstFailing(runs, "TestA")
+
+	assert.True(t, ok)
+	assert.Equal(t, "c2", streak.FirstFailingCommit())
+}
+
+func TestStartedFailing(t *testing.T) {
+	runs := testRuns(
+		[]string{"c1", "c2", "c3"},
+		[]TestRecord{fail("TestOld", ""), pass("TestNew", 0), pass("TestFixed", 0), pass("TestMiddle", 0)},
+		[]TestRecord{fail("TestOld", ""), pass("TestNew", 0), fail("TestFixed", ""), fail("TestMiddle", "")},
+		[]TestRecord{fail("TestOld", ""), fail("TestNew", ""), pass("TestFixed", 0), fail("TestMiddle", "")},
+	)
+
+	streaks := StartedFailing(runs)
+
+	var names []string
+	for _, streak := range streaks {
+		names = append(names, streak.Test)
+	}
+	assert.Equal(t, []string{"TestNew", "TestMiddle", "TestOld"}, names)
+	assert.Nil(t, StartedFailing(nil))
+}
+
+func TestDurationTrends(t *testing.T) {
+	runs := testRuns(
+		[]string{"c1", "c2", "c3", "c4", "c5"},
+		[]TestRecord{pass("TestApi/Slower", 10*time.Second), pass("TestApi/Faster", 10*time.Second), pass("TestOther", time.Second)},
+		[]TestRecord{pass("TestApi/Slower", 10*time.Second), pass("TestApi/Faster", 10*time.Second), pass("TestApi/New", time.Second)},
+		[]TestRecord{pass("TestApi/Slower", 20*time.Second), pass("TestApi/Faster", 4*time.Second), pass("TestApi/New", time.Second)},
+		// Failed runs do not count
+		[]TestRecord{pass("TestApi/Slower", 10*time.Second), fail("TestApi/Faster", ""), pass("TestApi/New", time.Second)},
+		[]TestRecord{pass("TestApi/Slower", 30*time.Second), pass("TestApi/Faster", 6*time.Second), pass("TestApi/New", time.Second)},
+	)
+
+	trends := DurationTrends(runs, 2, "TestApi/")
+
+	assert.Equal(t, []Trend{
+		{Test: "TestApi/Slower", Recent: 20 * time.Second, Previous: 15 * time.Second},
+		{Test: "TestApi/New", Recent: time.Second, Previous: time.Second},
+		{Test: "TestApi/Faster", Recent: 6 * time.Second, Previous: 7 * time.Second},
+	}, trends)
+	assert.InDelta(t, 1.0/3, trends[0].Change(), 0.0001)
+	assert.Nil(t, DurationTrends(runs, 0, ""))
+	// The window before the latest one is empty
+	assert.Empty(t, DurationTrends(runs, 5, ""))
+}
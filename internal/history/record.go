@@ -0,0 +1,73 @@
+package history
+
+import (
+	"time"
+	"unicode/utf8"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/runner"
+)
+
+// Failure output beyond the limit is cut, the store keeps every run
+const MAX_FAILURE_SIZE int = 8 * 1024
+
+type Run struct {
+	// Sequence number of the run in the store
+	Id         uint64
+	ApiVersion string
+	Commit     string
+	Started    time.Time
+	Elapsed    time.Duration
+	Tests      []TestRecord
+}
+
+type TestRecord struct {
+	Name     string
+	Outcome  string
+	Attempts int
+	Duration time.Duration
+	Failure  string `json:",omitempty"`
+}
+
+func FromResult(result runner.Result, apiVersion string, commit string) Run {
+	run := Run{ApiVersion: apiVersion, Commit: commit, Started: result.Started, Elapsed: result.Elapsed}
+	for _, test := range result.Tests {
+		record := TestRecord{Name: test.Name, Outcome: test.Outcome, Attempts: test.Attempts, Duration: test.Elapsed}
+		if test.Outcome == runner.OUTCOME_FAIL {
+			record.Failure = cut(test.Output, MAX_FAILURE_SIZE)
+		}
+		run.Tests = append(run.Tests, record)
+	}
+	return run
+}
+
+// Last limit bytes of the text, the end of a failure has the assertion. A rune is not split,
+// so the result may be a few bytes shorter.
+func cut(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	start := len(text) - limit
+	for start < len(text) && !utf8.RuneStart(text[start]) {
+		start++
+	}
+	return text[start:]
+}
+
+func (p Run) Test(name string) (TestRecord, bool) {
+	for _, test := range p.Tests {
+		if test.Name == name {
+			return test, true
+		}
+	}
+	return TestRecord{}, false
+}
+
+func (p Run) Count(outcome string) int {
+	result := 0
+	for _, test := range p.Tests {
+		if test.Outcome == outcome {
+			result++
+		}
+	}
+	return result
+}
@@ -0,0 +1,31 @@
+package history
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCut(t *testing.T) {
+	tests := []struct {
+		Text     string
+		Limit    int
+		Expected string
+	}{
+		{Text: "short", Limit: 10, Expected: "short"},
+		{Text: "exactly", Limit: 7, Expected: "exactly"},
+		{Text: "head and tail", Limit: 4, Expected: "tail"},
+		// "ё" is 2 bytes, "€" is 3
+		{Text: "ёж", Limit: 3, Expected: "ж"},
+		{Text: "a€b", Limit: 3, Expected: "b"},
+		{Text: "a€b", Limit: 4, Expected: "€b"},
+		{Text: "€", Limit: 2, Expected: ""},
+	}
+	for _, test := range tests {
+		result := cut(test.Text, test.Limit)
+
+		assert.Equal(t, test.Expected, result, test.Text)
+		assert.True(t, utf8.ValidString(result), test.Text)
+	}
+}
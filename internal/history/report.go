@@ -0,0 +1,89 @@
+package history
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/runner"
+)
+
+func WriteRuns(w io.Writer, runs []Run) error {
+	if len(runs) == 0 {
+		_, err := fmt.Fprintln(w, "no runs")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RUN\tCOMMIT\tSTARTED\tELAPSED\tPASSED\tFAILED\tFLAKY\tSKIPPED\t")
+	for _, run := range runs {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%d\t%d\t%d\t%d\t\n", run.Id, commit(run.Commit), run.Started.Format(time.RFC3339), run.Elapsed.Round(time.Millisecond),
+			run.Count(runner.OUTCOME_PASS), run.Count(runner.OUTCOME_FAIL), run.Count(runner.OUTCOME_FLAKY), run.Count(runner.OUTCOME_SKIP))
+	}
+	return tw.Flush()
+}
+
+func WriteStreaks(w io.Writer, streaks []Streak) error {
+	if len(streaks) == 0 {
+		_, err := fmt.Fprintln(w, "no failing tests")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TEST\tFAILING RUNS\tFIRST FAILING COMMIT\tSINCE\tLAST PASSING COMMIT\tFAILURE\t")
+	for _, streak := range streaks {
+		lastPass := "never passed"
+		if streak.LastPass != nil {
+			lastPass = commit(streak.LastPass.Commit)
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%s\t\n", streak.Test, streak.Runs, commit(streak.FirstFailingCommit()),
+			streak.Since.Started.Format(time.RFC3339), lastPass, FailureMessage(streak.Failure))
+	}
+	return tw.Flush()
+}
+
+func WriteStreak(w io.Writer, streak Streak) error {
+	fmt.Fprintf(w, "%s fails in the latest %d run(s)\n", streak.Test, streak.Runs)
+	fmt.Fprintf(w, "first failing commit: %s (run %d at %s)\n", commit(streak.FirstFailingCommit()), streak.Since.Id, streak.Since.Started.Format(time.RFC3339))
+	if streak.LastPass != nil {
+		fmt.Fprintf(w, "last passing commit: %s (run %d at %s)\n", commit(streak.LastPass.Commit), streak.LastPass.Id, streak.LastPass.Started.Format(time.RFC3339))
+	} else {
+		fmt.Fprintln(w, "last passing commit: never passed")
+	}
+	if streak.Failure != "" {
+		fmt.Fprintf(w, "\n%s", streak.Failure)
+	}
+	return nil
+}
+
+func WriteTrends(w io.Writer, trends []Trend) error {
+	if len(trends) == 0 {
+		_, err := fmt.Fprintln(w, "not enough runs to compare")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TEST\tPREVIOUS\tRECENT\tCHANGE\t")
+	for _, trend := range trends {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%+.1f%%\t\n", trend.Test, trend.Previous.Round(time.Millisecond), trend.Recent.Round(time.Millisecond), 100*trend.Change())
+	}
+	return tw.Flush()
+}
+
+func WriteSeries(w io.Writer, points []Point) error {
+	if len(points) == 0 {
+		_, err := fmt.Fprintln(w, "the test has no runs")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RUN\tCOMMIT\tSTARTED\tRESULT\tDURATION\t")
+	for _, point := range points {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t\n", point.RunId, commit(point.Commit), point.Started.Format(time.RFC3339), point.Outcome, point.Duration.Round(time.Millisecond))
+	}
+	return tw.Flush()
+}
+
+func commit(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
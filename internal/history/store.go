@@ -0,0 +1,128 @@
+package history
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Runs without an API version are kept under it
+const UNKNOWN_VERSION string = "unknown"
+
+var RUNS_BUCKET = []byte("runs")
+
+// Runs are kept in a bucket per API version, in the order they were added
+type Store struct {
+	db   *bolt.DB
+	path string
+}
+
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("unable to create history dir for '%s': %v", path, err)
+	}
+	db, err := bolt.Open(path, 0644, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("unable to open history '%s': %v", path, err)
+	}
+	return &Store{db: db, path: path}, nil
+}
+
+func (p *Store) Close() error {
+	return p.db.Close()
+}
+
+// Stores the run and sets its id
+func (p *Store) Add(run *Run) error {
+	err := p.db.Update(func(tx *bolt.Tx) error {
+		runs, err := tx.CreateBucketIfNotExists(RUNS_BUCKET)
+		if err != nil {
+			return err
+		}
+		if run.ApiVersion == "" {
+			run.ApiVersion = UNKNOWN_VERSION
+		}
+		version, err := runs.CreateBucketIfNotExists([]byte(run.ApiVersion))
+		if err != nil {
+			return err
+		}
+		id, err := version.NextSequence()
+		if err != nil {
+			return err
+		}
+		run.Id = id
+		data, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		return version.Put(key(id), data)
+	})
+	if err != nil {
+		return fmt.Errorf("unable to store run in history '%s': %v", p.path, err)
+	}
+	return nil
+}
+
+// Runs of the API version, the oldest first
+func (p *Store) Runs(apiVersion string) ([]Run, error) {
+	if apiVersion == "" {
+		apiVersion = UNKNOWN_VERSION
+	}
+	var result []Run
+	err := p.db.View(func(tx *bolt.Tx) error {
+		runs := tx.Bucket(RUNS_BUCKET)
+		if runs == nil {
+			return nil
+		}
+		version := runs.Bucket([]byte(apiVersion))
+		if version == nil {
+			return nil
+		}
+		return version.ForEach(func(k, v []byte) error {
+			var run Run
+			if err := json.Unmarshal(v, &run); err != nil {
+				return fmt.Errorf("run %d: %v", binary.BigEndian.Uint64(k), err)
+			}
+			result = append(result, run)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read history '%s': %v", p.path, err)
+	}
+	return result, nil
+}
+
+// API versions that have runs
+func (p *Store) Versions() ([]string, error) {
+	var result []string
+	err := p.db.View(func(tx *bolt.Tx) error {
+		runs := tx.Bucket(RUNS_BUCKET)
+		if runs == nil {
+			return nil
+		}
+		return runs.ForEach(func(k, v []byte) error {
+			// Nested buckets have no value
+			if v == nil {
+				result = append(result, string(k))
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read history '%s': %v", p.path, err)
+	}
+	return result, nil
+}
+
+// Keys sort in the order of ids
+func key(id uint64) []byte {
+	result := make([]byte, 8)
+	binary.BigEndian.PutUint64(result, id)
+	return result
+}
@@ -0,0 +1,364 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/scenario"
+)
+
+const (
+	FORMAT_POSTMAN  string = "postman"
+	FORMAT_INSOMNIA string = "insomnia"
+)
+
+// {{name}} of Postman and {{ _.name }} of Insomnia
+var templateVariable = regexp.MustCompile(`\{\{\s*(?:_\.)?([A-Za-z0-9_.\-$]+)\s*\}\}`)
+
+var (
+	absoluteUrl   = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*://[^/?#]*`)
+	leadingVar    = regexp.MustCompile(`^\$\{([A-Za-z0-9_.-]+)\}`)
+	quotedVar     = regexp.MustCompile(`"(\$\{[A-Za-z0-9_.-]+\})"`)
+	bareVar       = regexp.MustCompile(`\$\{[A-Za-z0-9_.-]+\}`)
+	statusScripts = []*regexp.Regexp{
+		regexp.MustCompile(`pm\.response\.to\.have\.status\(\s*(\d{3})\s*\)`),
+		regexp.MustCompile(`pm\.expect\(\s*pm\.response\.code\s*\)\.to\.(?:eql|equal|be\.equal)\(\s*(\d{3})\s*\)`),
+		// Unit tests of Insomnia
+		regexp.MustCompile(`expect\(\s*response\d*\.status\s*\)\.to\.(?:eql|equal|be\.equal)\(\s*(\d{3})\s*\)`),
+	}
+	setScript   = regexp.MustCompile(`pm\.(?:environment|collectionVariables|globals|variables)\.set\(\s*["']([^"']+)["']\s*,\s*(.+?)\s*\)\s*;?\s*$`)
+	aliasScript = regexp.MustCompile(`^(?:var|let|const)\s+(\w+)\s*=\s*pm\.response\.json\(\)\s*;?\s*$`)
+	// Lines of a script that carry no assertion, e.g. pm.test("Status is 201", function () {
+	structureScript = regexp.MustCompile(`^(pm\.test\(.*(function\s*\(\)|=>)\s*\{|\}\s*\)\s*;?|//.*|(const|let|var)\s+response\d*\s*=\s*await\s+insomnia\.send\(\)\s*;?|)$`)
+)
+
+type Options struct {
+	// Values of an environment exported from Postman or Insomnia
+	Environment map[string]string
+	// Values of .env.test, only to check the mapped keys: the scenarios refer to them as ${env:KEY}
+	DotEnv map[string]string
+	// Collection variable -> key of .env.test, e.g. adminPassword -> ADMIN_PASSWORD
+	DotEnvMapping map[string]string
+	// Sub environment of an Insomnia export, only the base environment is used if it is empty
+	EnvironmentName string
+}
+
+type Result struct {
+	Scenarios []*scenario.Scenario
+	// Script lines and settings that have no counterpart in the scenario format
+	Warnings []string
+}
+
+func (p *Result) warn(format string, args ...any) {
+	p.Warnings = append(p.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Detects the format of the export and converts it
+func Import(data []byte, options Options) (Result, error) {
+	format, err := Detect(data)
+	if err != nil {
+		return Result{}, err
+	}
+	if format == FORMAT_INSOMNIA {
+		return ImportInsomnia(data, options)
+	}
+	return ImportPostman(data, options)
+}
+
+func Detect(data []byte) (string, error) {
+	var probe struct {
+		Info struct {
+			Schema string `json:"schema"`
+		} `json:"info"`
+		Type string `json:"_type"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return "", fmt.Errorf("unable to parse collection: %v", err)
+	}
+	switch {
+	case strings.Contains(probe.Info.Schema, "v2.1"):
+		return FORMAT_POSTMAN, nil
+	case probe.Type == "export":
+		return FORMAT_INSOMNIA, nil
+	}
+	return "", fmt.Errorf("unknown collection format, expected a Postman v2.1 collection or an Insomnia export")
+}
+
+// Builds scenarios of one collection: converts templates, strips base URLs and resolves the variables
+type converter struct {
+	options Options
+	result  *Result
+	// Variables the URLs start with, they point to the API and the runner sends requests to it anyway
+	baseUrlVars map[string]bool
+	// Variables used in JSON bodies without quotes, e.g. "TagId": {{tagId}}, and with them
+	bareVars   map[string]bool
+	quotedVars map[string]bool
+}
+
+func newConverter(options Options, result *Result) *converter {
+	return &converter{options: options, result: result, baseUrlVars: make(map[string]bool), bareVars: make(map[string]bool), quotedVars: make(map[string]bool)}
+}
+
+// {{name}} -> ${name}
+func (p *converter) template(text string, where string) string {
+	return templateVariable.ReplaceAllStringFunc(text, func(match string) string {
+		name := templateVariable.FindStringSubmatch(match)[1]
+		if strings.HasPrefix(name, "$") {
+			p.result.warn("%s: dynamic variable %s is not supported", where, match)
+			return match
+		}
+		return "${" + name + "}"
+	})
+}
+
+func (p *converter) request(method string, rawUrl string, where string) string {
+	path := p.template(strings.TrimSpace(rawUrl), where)
+	if match := absoluteUrl.FindString(path); match != "" {
+		path = path[len(match):]
+	} else if match := leadingVar.FindStringSubmatch(path); match != nil {
+		p.baseUrlVars[match[1]] = true
+		path = path[len(match[0]):]
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return strings.ToUpper(method) + " " + path
+}
+
+// JSON bodies become YAML, the others are kept as text. A bare variable, e.g. "TagId": {{tagId}},
+// is quoted so that the body parses, the runner puts the value back as it is and vars keeps the value
+// a number or a boolean like Postman sends it.
+func (p *converter) body(text string, where string) any {
+	text = strings.TrimSpace(p.template(text, where))
+	if text == "" {
+		return nil
+	}
+	quoted, bare := quoteBareVars(text)
+	var result any
+	if err := json.Unmarshal([]byte(quoted), &result); err != nil {
+		return text
+	}
+	for _, name := range bare {
+		p.bareVars[name] = true
+	}
+	// A string that is only a variable gets the value as is, it must stay text
+	for _, match := range quotedVar.FindAllStringSubmatch(text, -1) {
+		p.quotedVars[match[1][2:len(match[1])-1]] = true
+	}
+	return result
+}
+
+// Quotes the variables outside of JSON strings and returns their names
+func quoteBareVars(text string) (string, []string) {
+	var result strings.Builder
+	var names []string
+	inString, escaped := false, false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case inString && escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case inString && c == '"':
+			inString = false
+		case c == '"':
+			inString = true
+		case !inString && strings.HasPrefix(text[i:], "${"):
+			if match := leadingVar.FindStringSubmatch(text[i:]); match != nil {
+				names = append(names, match[1])
+				result.WriteString(strconv.Quote(match[0]))
+				i += len(match[0]) - 1
+				continue
+			}
+		}
+		result.WriteByte(c)
+	}
+	return result.String(), names
+}
+
+// Value of a variable used bare in a body: JSON numbers and booleans stay such, the others stay text
+func bareValue(value string) any {
+	var result any
+	if err := json.Unmarshal([]byte(value), &result); err == nil {
+		switch result.(type) {
+		case float64, bool:
+			return result
+		}
+	}
+	return value
+}
+
+func (p *converter) headers(headers map[string]string, where string) map[string]string {
+	var result map[string]string
+	for name, value := range headers {
+		// The runner sets it for every body
+		if strings.EqualFold(name, "Content-Type") {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]string)
+		}
+		result[name] = p.template(value, where)
+	}
+	return result
+}
+
+// Status assertions become the expected status, variables set from the response become saves
+func (p *converter) script(lines []string, step *scenario.Step, where string) {
+	aliases := make(map[string]bool)
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if match := aliasScript.FindStringSubmatch(line); match != nil {
+			aliases[match[1]] = true
+			continue
+		}
+		if status, ok := statusOf(line); ok {
+			step.Expect.Status = status
+			continue
+		}
+		if match := setScript.FindStringSubmatch(line); match != nil {
+			path, ok := jsonPathOf(match[2], aliases)
+			if !ok {
+				p.result.warn("%s: unable to convert '%s'", where, line)
+				continue
+			}
+			if step.Save == nil {
+				step.Save = make(map[string]string)
+			}
+			step.Save[match[1]] = path
+			continue
+		}
+		if !structureScript.MatchString(line) {
+			p.result.warn("%s: unable to convert '%s'", where, line)
+		}
+	}
+}
+
+func statusOf(line string) (int, bool) {
+	for _, pattern := range statusScripts {
+		if match := pattern.FindStringSubmatch(line); match != nil {
+			status, _ := strconv.Atoi(match[1])
+			return status, true
+		}
+	}
+	return 0, false
+}
+
+var accessor = regexp.MustCompile(`^(?:\.[A-Za-z_$][A-Za-z0-9_$]*|\[\d+\]|\[["'][^"']+["']\])`)
+
+// pm.response.json().Data[0].Id or jsonData["Id"] -> $.Data[0].Id and $['Id']
+func jsonPathOf(expression string, aliases map[string]bool) (string, bool) {
+	rest := strings.TrimPrefix(expression, "pm.response.json()")
+	if rest == expression {
+		name := expression
+		if i := strings.IndexAny(expression, ".["); i >= 0 {
+			name = expression[:i]
+		}
+		if !aliases[name] {
+			return "", false
+		}
+		rest = expression[len(name):]
+	}
+	path := "$"
+	for rest != "" {
+		match := accessor.FindString(rest)
+		if match == "" {
+			return "", false
+		}
+		if strings.HasPrefix(match, "[\"") {
+			match = "['" + match[2:len(match)-2] + "']"
+		}
+		path, rest = path+match, rest[len(match):]
+	}
+	return path, true
+}
+
+// Variables of the scenarios: the collection ones, overridden by the environment, overridden by references
+// to the mapped .env.test keys, the runner reads them when the scenario runs. Base URLs are left out.
+func (p *converter) vars(collection map[string]string) map[string]any {
+	result := make(map[string]any)
+	for _, values := range []map[string]string{collection, p.options.Environment} {
+		for name, value := range values {
+			result[name] = value
+		}
+	}
+	for name := range p.bareVars {
+		value, ok := result[name].(string)
+		if !ok {
+			continue
+		}
+		if p.quotedVars[name] {
+			p.result.warn("variable '%s' is used in bodies with and without quotes, its value is kept as text", name)
+			continue
+		}
+		result[name] = bareValue(value)
+	}
+	for name, key := range p.options.DotEnvMapping {
+		if _, ok := p.options.DotEnv[key]; !ok {
+			p.result.warn("%s is mapped to %s, .env.test has no such key", name, key)
+			continue
+		}
+		if p.bareVars[name] {
+			p.result.warn("variable '%s' is used in a body without quotes, its value from .env.test is sent as text", name)
+		}
+		result[name] = "${" + scenario.ENV_PREFIX + key + "}"
+	}
+	for name := range p.baseUrlVars {
+		delete(result, name)
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// Warns about variables a step refers to that neither have a value nor are saved by a previous step
+func (p *converter) checkVars(s *scenario.Scenario) {
+	known := make(map[string]bool)
+	for name := range s.Vars {
+		known[name] = true
+	}
+	for _, step := range s.Steps {
+		texts := []string{step.Request}
+		for _, value := range step.Headers {
+			texts = append(texts, value)
+		}
+		if body, err := json.Marshal(step.Body); err == nil {
+			texts = append(texts, string(body))
+		}
+		warned := make(map[string]bool)
+		for _, text := range texts {
+			for _, match := range bareVar.FindAllString(text, -1) {
+				name := match[2 : len(match)-1]
+				if !known[name] && !warned[name] {
+					p.result.warn("%s / %s: variable '%s' has no value", s.Name, step.Title(), name)
+					warned[name] = true
+				}
+			}
+		}
+		for name := range step.Save {
+			known[name] = true
+		}
+	}
+}
+
+func splitLines(texts []string) []string {
+	var result []string
+	for _, text := range texts {
+		result = append(result, strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")...)
+	}
+	return result
+}
+
+func isMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,230 @@
+package importer
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/scenario"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImport(t *testing.T) {
+	options := Options{
+		DotEnv:        map[string]string{"ADMIN_PASSWORD": "secret"},
+		DotEnvMapping: map[string]string{"adminPassword": "ADMIN_PASSWORD", "admin.password": "ADMIN_PASSWORD"},
+	}
+	tests := []struct {
+		Name             string
+		File             string
+		Options          Options
+		ExpectedFormat   string
+		ExpectedVars     map[string]any
+		ExpectedSteps    map[string][]scenario.Step
+		ExpectedWarnings []string
+	}{
+		{
+			Name: "Postman", File: "testdata/postman.json", Options: options,
+			ExpectedFormat: FORMAT_POSTMAN,
+			// ownerId is used without quotes in a body and stays a number
+			ExpectedVars: map[string]any{"adminEmail": "admin@example.com", "adminPassword": "${env:ADMIN_PASSWORD}", "admin.password": "${env:ADMIN_PASSWORD}", "ownerId": float64(7)},
+			ExpectedSteps: map[string][]scenario.Step{
+				"Notes": {
+					{Name: "Ping", Request: "GET /ping", Headers: map[string]string{"Authorization": "Bearer ${accessToken}"}, Expect: scenario.Expect{Status: 200}},
+				},
+				"Notes / Tags": {
+					{
+						Name: "Login", Request: "POST /auth/login",
+						Body:   map[string]any{"Email": "${adminEmail}", "Password": "${adminPassword}"},
+						Expect: scenario.Expect{Status: 200},
+						Save:   map[string]string{"accessToken": "$.AccessToken"},
+					},
+					{
+						Name: "Create tag", Request: "POST /tags",
+						Headers: map[string]string{"Authorization": "Bearer ${accessToken}"},
+						Body:    map[string]any{"Name": "books", "State": "NEW", "Owner": "${ownerId}"},
+						Expect:  scenario.Expect{Status: 201},
+						Save:    map[string]string{"tagId": "$"},
+					},
+					{Name: "Get tag", Request: "GET /tags/${tagId}?ts={{$timestamp}}", Headers: map[string]string{"Authorization": "Bearer ${accessToken}"}},
+				},
+			},
+			ExpectedWarnings: []string{
+				"Create tag: prerequest script is not converted",
+				"Create tag: unable to convert 'pm.sendRequest('/metrics');'",
+				"Get tag: dynamic variable {{$timestamp}} is not supported",
+				"Notes / Ping: variable 'accessToken' has no value",
+			},
+		},
+		{
+			Name: "Insomnia", File: "testdata/insomnia.json", Options: Options{EnvironmentName: "Staging", DotEnv: options.DotEnv, DotEnvMapping: options.DotEnvMapping},
+			ExpectedFormat: FORMAT_INSOMNIA,
+			ExpectedVars:   map[string]any{"admin.email": "staging@example.com", "adminPassword": "${env:ADMIN_PASSWORD}", "admin.password": "${env:ADMIN_PASSWORD}"},
+			ExpectedSteps: map[string][]scenario.Step{
+				"Notes": {
+					{Name: "Ping", Request: "GET /ping"},
+				},
+				"Notes / Notes": {
+					{
+						Name: "Login", Request: "POST /auth/login",
+						Body:   map[string]any{"Email": "${admin.email}", "Password": "${admin.password}"},
+						Expect: scenario.Expect{Status: 200},
+					},
+					{Name: "Get note", Request: "GET /notes/${noteId}", Headers: map[string]string{"Authorization": "Bearer ${accessToken}"}},
+				},
+			},
+			ExpectedWarnings: []string{
+				"Login: authentication 'basic' is not converted",
+				"Notes / Notes / Get note: variable 'noteId' has no value",
+				"Notes / Notes / Get note: variable 'accessToken' has no value",
+			},
+		},
+		{
+			Name: "MissingEnvKey", File: "testdata/postman.json",
+			Options:        Options{DotEnv: map[string]string{}, DotEnvMapping: map[string]string{"adminPassword": "ADMIN_PASSWORD"}},
+			ExpectedFormat: FORMAT_POSTMAN,
+			// The collection value is kept
+			ExpectedVars: map[string]any{"adminEmail": "admin@example.com", "adminPassword": "collection password", "ownerId": float64(7)},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			data, err := os.ReadFile(test.File)
+			assert.Nil(t, err)
+
+			format, err := Detect(data)
+			assert.Nil(t, err)
+			assert.Equal(t, test.ExpectedFormat, format)
+
+			result, err := Import(data, test.Options)
+			assert.Nil(t, err)
+			for _, s := range result.Scenarios {
+				assert.Equal(t, test.ExpectedVars, s.Vars, s.Name)
+			}
+			if test.ExpectedSteps == nil {
+				assert.Contains(t, result.Warnings, "adminPassword is mapped to ADMIN_PASSWORD, .env.test has no such key")
+				return
+			}
+			steps := make(map[string][]scenario.Step)
+			for _, s := range result.Scenarios {
+				steps[s.Name] = s.Steps
+			}
+			assert.Equal(t, test.ExpectedSteps, steps)
+			assert.ElementsMatch(t, test.ExpectedWarnings, result.Warnings)
+		})
+	}
+}
+
+func TestBody(t *testing.T) {
+	collection := map[string]string{"id": "7", "ratio": "0.5", "public": "true", "name": "books", "code": "007"}
+	tests := []struct {
+		Name             string
+		Body             string
+		Expected         any
+		ExpectedVars     map[string]any
+		ExpectedWarnings []string
+	}{
+		{
+			Name: "BareValues", Body: `{"TagId": {{id}}, "Ratio": {{ratio}}, "Public": {{public}}, "Name": {{name}}, "Code": {{code}}}`,
+			Expected: map[string]any{"TagId": "${id}", "Ratio": "${ratio}", "Public": "${public}", "Name": "${name}", "Code": "${code}"},
+			// Values that are not JSON numbers or booleans are sent as text
+			ExpectedVars: map[string]any{"id": float64(7), "ratio": 0.5, "public": true, "name": "books", "code": "007"},
+		},
+		{
+			Name: "QuotedValues", Body: `{"TagId": "{{id}}", "Text": "tag {{id}} of \"{{name}}\""}`,
+			Expected:     map[string]any{"TagId": "${id}", "Text": `tag ${id} of "${name}"`},
+			ExpectedVars: map[string]any{"id": "7", "ratio": "0.5", "public": "true", "name": "books", "code": "007"},
+		},
+		{
+			Name: "BareAndQuoted", Body: `[{"TagId": {{id}}}, {"Name": "{{id}}"}, {"Text": "#{{public}}", "Public": {{public}}}]`,
+			Expected:         []any{map[string]any{"TagId": "${id}"}, map[string]any{"Name": "${id}"}, map[string]any{"Text": "#${public}", "Public": "${public}"}},
+			ExpectedVars:     map[string]any{"id": "7", "ratio": "0.5", "public": true, "name": "books", "code": "007"},
+			ExpectedWarnings: []string{"variable 'id' is used in bodies with and without quotes, its value is kept as text"},
+		},
+		{
+			Name: "NotJson", Body: "name={{name}}&id={{id}}",
+			Expected:     "name=${name}&id=${id}",
+			ExpectedVars: map[string]any{"id": "7", "ratio": "0.5", "public": "true", "name": "books", "code": "007"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var result Result
+			c := newConverter(Options{}, &result)
+
+			body := c.body(test.Body, test.Name)
+
+			assert.Equal(t, test.Expected, body)
+			assert.Equal(t, test.ExpectedVars, c.vars(collection))
+			assert.Equal(t, test.ExpectedWarnings, result.Warnings)
+		})
+	}
+}
+
+func TestBareEnvVariable(t *testing.T) {
+	var result Result
+	c := newConverter(Options{DotEnv: map[string]string{"OWNER_ID": "7"}, DotEnvMapping: map[string]string{"ownerId": "OWNER_ID"}}, &result)
+
+	c.body(`{"Owner": {{ownerId}}}`, "Create tag")
+
+	assert.Equal(t, map[string]any{"ownerId": "${env:OWNER_ID}"}, c.vars(nil))
+	assert.Equal(t, []string{"variable 'ownerId' is used in a body without quotes, its value from .env.test is sent as text"}, result.Warnings)
+}
+
+func TestDetectUnknownFormat(t *testing.T) {
+	for _, data := range []string{`{"info": {"schema": "v1.0"}}`, `[]`, `not json`} {
+		_, err := Detect([]byte(data))
+
+		assert.NotNil(t, err, data)
+	}
+}
+
+func TestJsonPathOf(t *testing.T) {
+	aliases := map[string]bool{"jsonData": true}
+	tests := []struct {
+		Expression string
+		Expected   string
+		ExpectedOk bool
+	}{
+		{Expression: "pm.response.json()", Expected: "$", ExpectedOk: true},
+		{Expression: "pm.response.json().Id", Expected: "$.Id", ExpectedOk: true},
+		{Expression: "pm.response.json().Data[0].Id", Expected: "$.Data[0].Id", ExpectedOk: true},
+		{Expression: `pm.response.json()["Access-Token"]`, Expected: "$['Access-Token']", ExpectedOk: true},
+		{Expression: "pm.response.json()['Id']", Expected: "$['Id']", ExpectedOk: true},
+		{Expression: "jsonData.AccessToken", Expected: "$.AccessToken", ExpectedOk: true},
+		{Expression: "jsonData", Expected: "$", ExpectedOk: true},
+		{Expression: "other.AccessToken", ExpectedOk: false},
+		{Expression: "pm.response.json().Data.length", Expected: "$.Data.length", ExpectedOk: true},
+		{Expression: "pm.response.json().Data[i]", ExpectedOk: false},
+		{Expression: "pm.response.json().Id + 1", ExpectedOk: false},
+		{Expression: `"constant"`, ExpectedOk: false},
+	}
+	for _, test := range tests {
+		path, ok := jsonPathOf(test.Expression, aliases)
+
+		assert.Equal(t, test.ExpectedOk, ok, test.Expression)
+		assert.Equal(t, test.Expected, path, test.Expression)
+	}
+}
+
+func TestStatusOf(t *testing.T) {
+	tests := []struct {
+		Line       string
+		Expected   int
+		ExpectedOk bool
+	}{
+		{Line: "pm.response.to.have.status(201);", Expected: 201, ExpectedOk: true},
+		{Line: "    pm.response.to.have.status( 404 )", Expected: 404, ExpectedOk: true},
+		{Line: "pm.expect(pm.response.code).to.eql(200);", Expected: 200, ExpectedOk: true},
+		{Line: "pm.expect(pm.response.code).to.be.equal(400);", Expected: 400, ExpectedOk: true},
+		{Line: "expect(response.status).to.equal(204);", Expected: 204, ExpectedOk: true},
+		{Line: "expect(response2.status).to.eql(500);", Expected: 500, ExpectedOk: true},
+		{Line: "pm.response.to.have.status(\"Created\");", ExpectedOk: false},
+		{Line: "pm.expect(pm.response.responseTime).to.be.below(200);", ExpectedOk: false},
+	}
+	for _, test := range tests {
+		status, ok := statusOf(test.Line)
+
+		assert.Equal(t, test.ExpectedOk, ok, test.Line)
+		assert.Equal(t, test.Expected, status, test.Line)
+	}
+}
@@ -0,0 +1,189 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/scenario"
+)
+
+const (
+	INSOMNIA_WORKSPACE     string = "workspace"
+	INSOMNIA_REQUEST_GROUP string = "request_group"
+	INSOMNIA_REQUEST       string = "request"
+	INSOMNIA_ENVIRONMENT   string = "environment"
+	INSOMNIA_UNIT_TEST     string = "unit_test"
+)
+
+// Parts of the resources of an Insomnia export (format 4) the importer understands
+type insomniaResource struct {
+	Id          string         `json:"_id"`
+	Type        string         `json:"_type"`
+	ParentId    string         `json:"parentId"`
+	Name        string         `json:"name"`
+	MetaSortKey float64        `json:"metaSortKey"`
+	Data        map[string]any `json:"data"`
+
+	Method  string `json:"method"`
+	Url     string `json:"url"`
+	Headers []struct {
+		Name     string `json:"name"`
+		Value    string `json:"value"`
+		Disabled bool   `json:"disabled"`
+	} `json:"headers"`
+	Body struct {
+		MimeType string `json:"mimeType"`
+		Text     string `json:"text"`
+		Params   []any  `json:"params"`
+	} `json:"body"`
+	Authentication struct {
+		Type     string `json:"type"`
+		Token    string `json:"token"`
+		Prefix   string `json:"prefix"`
+		Disabled bool   `json:"disabled"`
+	} `json:"authentication"`
+
+	RequestId string `json:"requestId"`
+	Code      string `json:"code"`
+}
+
+// Every request group of a workspace becomes a scenario with the requests of its subgroups in order,
+// the requests outside of groups go to a scenario named after the workspace
+func ImportInsomnia(data []byte, options Options) (Result, error) {
+	var export struct {
+		Resources []insomniaResource `json:"resources"`
+	}
+	if err := json.Unmarshal(data, &export); err != nil {
+		return Result{}, fmt.Errorf("unable to parse Insomnia export: %v", err)
+	}
+
+	children := make(map[string][]insomniaResource)
+	tests := make(map[string][]string)
+	for _, resource := range export.Resources {
+		children[resource.ParentId] = append(children[resource.ParentId], resource)
+		if resource.Type == INSOMNIA_UNIT_TEST {
+			tests[resource.RequestId] = append(tests[resource.RequestId], splitLines([]string{resource.Code})...)
+		}
+	}
+	for _, resources := range children {
+		sort.SliceStable(resources, func(i, j int) bool {
+			return resources[i].MetaSortKey < resources[j].MetaSortKey
+		})
+	}
+
+	result := Result{}
+	for _, workspace := range children[""] {
+		if workspace.Type != INSOMNIA_WORKSPACE {
+			continue
+		}
+		c := newConverter(options, &result)
+		var scenarios []*scenario.Scenario
+		root := &scenario.Scenario{Name: workspace.Name}
+		for _, resource := range children[workspace.Id] {
+			switch resource.Type {
+			case INSOMNIA_REQUEST:
+				root.Steps = append(root.Steps, c.insomniaSteps(resource, "", children, tests)...)
+			case INSOMNIA_REQUEST_GROUP:
+				group := &scenario.Scenario{Name: workspace.Name + " / " + resource.Name}
+				for _, child := range children[resource.Id] {
+					group.Steps = append(group.Steps, c.insomniaSteps(child, "", children, tests)...)
+				}
+				if len(group.Steps) > 0 {
+					scenarios = append(scenarios, group)
+				}
+			}
+		}
+		if len(root.Steps) > 0 {
+			scenarios = append([]*scenario.Scenario{root}, scenarios...)
+		}
+
+		vars := c.vars(insomniaEnvironment(workspace.Id, options.EnvironmentName, children))
+		for _, s := range scenarios {
+			s.Vars = vars
+			c.checkVars(s)
+		}
+		result.Scenarios = append(result.Scenarios, scenarios...)
+	}
+	return result, nil
+}
+
+func (p *converter) insomniaSteps(resource insomniaResource, prefix string, children map[string][]insomniaResource, tests map[string][]string) []scenario.Step {
+	where := prefix + resource.Name
+	if resource.Type == INSOMNIA_REQUEST_GROUP {
+		var result []scenario.Step
+		for _, child := range children[resource.Id] {
+			result = append(result, p.insomniaSteps(child, where+" / ", children, tests)...)
+		}
+		return result
+	}
+	if resource.Type != INSOMNIA_REQUEST {
+		return nil
+	}
+
+	method := resource.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	if !isMethod(method) {
+		p.result.warn("%s: method %s is skipped", where, method)
+		return nil
+	}
+	step := scenario.Step{Name: where, Request: p.request(method, resource.Url, where)}
+	headers := make(map[string]string)
+	for _, header := range resource.Headers {
+		if !header.Disabled {
+			headers[header.Name] = header.Value
+		}
+	}
+	if auth := resource.Authentication; !auth.Disabled {
+		switch auth.Type {
+		case "bearer":
+			prefix := auth.Prefix
+			if prefix == "" {
+				prefix = "Bearer"
+			}
+			headers["Authorization"] = prefix + " " + auth.Token
+		case "", "none":
+		default:
+			p.result.warn("%s: authentication '%s' is not converted", where, auth.Type)
+		}
+	}
+	step.Headers = p.headers(headers, where)
+	if resource.Body.Text != "" {
+		step.Body = p.body(resource.Body.Text, where)
+	} else if len(resource.Body.Params) > 0 {
+		p.result.warn("%s: body of type '%s' is not converted", where, resource.Body.MimeType)
+	}
+	p.script(tests[resource.Id], &step, where)
+	return []scenario.Step{step}
+}
+
+// Base environment of the workspace, overridden by the named sub environment. Nested values are flattened
+// the way Insomnia refers to them, e.g. {{ _.api.token }}.
+func insomniaEnvironment(workspaceId string, name string, children map[string][]insomniaResource) map[string]string {
+	result := make(map[string]string)
+	for _, base := range children[workspaceId] {
+		if base.Type != INSOMNIA_ENVIRONMENT {
+			continue
+		}
+		flatten("", base.Data, result)
+		for _, sub := range children[base.Id] {
+			if sub.Type == INSOMNIA_ENVIRONMENT && name != "" && sub.Name == name {
+				flatten("", sub.Data, result)
+			}
+		}
+	}
+	return result
+}
+
+func flatten(prefix string, data map[string]any, result map[string]string) {
+	for key, value := range data {
+		if nested, ok := value.(map[string]any); ok {
+			flatten(prefix+key+".", nested, result)
+			continue
+		}
+		result[prefix+key] = fmt.Sprint(value)
+	}
+}
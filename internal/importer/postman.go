@@ -0,0 +1,225 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/scenario"
+)
+
+// Parts of the Postman collection format v2.1 the importer understands
+type postmanCollection struct {
+	Info struct {
+		Name string `json:"name"`
+	} `json:"info"`
+	Item     []postmanItem     `json:"item"`
+	Variable []postmanVariable `json:"variable"`
+	Auth     *postmanAuth      `json:"auth"`
+}
+
+// Either a folder with items or a request
+type postmanItem struct {
+	Name    string          `json:"name"`
+	Item    []postmanItem   `json:"item"`
+	Request json.RawMessage `json:"request"`
+	Event   []postmanEvent  `json:"event"`
+}
+
+type postmanRequest struct {
+	Method string          `json:"method"`
+	Header []postmanHeader `json:"header"`
+	Url    json.RawMessage `json:"url"`
+	Body   *struct {
+		Mode string `json:"mode"`
+		Raw  string `json:"raw"`
+	} `json:"body"`
+	Auth *postmanAuth `json:"auth"`
+}
+
+type postmanHeader struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled"`
+}
+
+type postmanAuth struct {
+	Type   string            `json:"type"`
+	Bearer []postmanVariable `json:"bearer"`
+}
+
+type postmanEvent struct {
+	Listen string `json:"listen"`
+	Script struct {
+		Exec json.RawMessage `json:"exec"`
+	} `json:"script"`
+}
+
+type postmanVariable struct {
+	Key      string `json:"key"`
+	Value    any    `json:"value"`
+	Disabled bool   `json:"disabled"`
+	// Environments use enabled instead of disabled
+	Enabled *bool `json:"enabled"`
+}
+
+func (p postmanVariable) isOn() bool {
+	return !p.Disabled && (p.Enabled == nil || *p.Enabled)
+}
+
+func postmanValues(variables []postmanVariable) map[string]string {
+	result := make(map[string]string, len(variables))
+	for _, v := range variables {
+		if v.isOn() && v.Key != "" {
+			result[v.Key] = fmt.Sprint(v.Value)
+		}
+	}
+	return result
+}
+
+// Values of an environment exported from Postman
+func ParsePostmanEnvironment(data []byte) (map[string]string, error) {
+	var environment struct {
+		Values []postmanVariable `json:"values"`
+	}
+	if err := json.Unmarshal(data, &environment); err != nil {
+		return nil, fmt.Errorf("unable to parse Postman environment: %v", err)
+	}
+	return postmanValues(environment.Values), nil
+}
+
+// Every top level folder becomes a scenario with the requests of its subfolders in order,
+// the requests outside of folders go to a scenario named after the collection
+func ImportPostman(data []byte, options Options) (Result, error) {
+	var collection postmanCollection
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return Result{}, fmt.Errorf("unable to parse Postman collection: %v", err)
+	}
+
+	result := Result{}
+	c := newConverter(options, &result)
+	root := &scenario.Scenario{Name: collection.Info.Name}
+	for _, item := range collection.Item {
+		if item.Request != nil {
+			root.Steps = append(root.Steps, c.postmanSteps(item, "", collection.Auth)...)
+			continue
+		}
+		if len(item.Event) > 0 {
+			c.result.warn("%s: scripts of folders are not converted", item.Name)
+		}
+		folder := &scenario.Scenario{Name: collection.Info.Name + " / " + item.Name}
+		for _, child := range item.Item {
+			folder.Steps = append(folder.Steps, c.postmanSteps(child, "", collection.Auth)...)
+		}
+		if len(folder.Steps) > 0 {
+			result.Scenarios = append(result.Scenarios, folder)
+		}
+	}
+	if len(root.Steps) > 0 {
+		result.Scenarios = append([]*scenario.Scenario{root}, result.Scenarios...)
+	}
+
+	vars := c.vars(postmanValues(collection.Variable))
+	for _, s := range result.Scenarios {
+		s.Vars = vars
+		c.checkVars(s)
+	}
+	return result, nil
+}
+
+func (p *converter) postmanSteps(item postmanItem, prefix string, auth *postmanAuth) []scenario.Step {
+	where := prefix + item.Name
+	if item.Request == nil {
+		if len(item.Event) > 0 {
+			p.result.warn("%s: scripts of folders are not converted", where)
+		}
+		var result []scenario.Step
+		for _, child := range item.Item {
+			result = append(result, p.postmanSteps(child, where+" / ", auth)...)
+		}
+		return result
+	}
+
+	var request postmanRequest
+	if err := json.Unmarshal(item.Request, &request); err != nil {
+		// A request may be just the URL
+		var url string
+		if json.Unmarshal(item.Request, &url) != nil {
+			p.result.warn("%s: unable to parse the request", where)
+			return nil
+		}
+		request = postmanRequest{Url: item.Request}
+	}
+	if request.Method == "" {
+		request.Method = http.MethodGet
+	}
+	if !isMethod(request.Method) {
+		p.result.warn("%s: method %s is skipped", where, request.Method)
+		return nil
+	}
+
+	step := scenario.Step{Name: prefix + item.Name, Request: p.request(request.Method, postmanUrl(request.Url), where)}
+	headers := make(map[string]string)
+	for _, header := range request.Header {
+		if !header.Disabled {
+			headers[header.Key] = header.Value
+		}
+	}
+	if request.Auth != nil {
+		auth = request.Auth
+	}
+	if auth != nil {
+		switch auth.Type {
+		case "bearer":
+			headers["Authorization"] = "Bearer " + postmanValues(auth.Bearer)["token"]
+		case "noauth", "inherit":
+		default:
+			p.result.warn("%s: auth type '%s' is not converted", where, auth.Type)
+		}
+	}
+	step.Headers = p.headers(headers, where)
+	if request.Body != nil {
+		if request.Body.Mode == "raw" {
+			step.Body = p.body(request.Body.Raw, where)
+		} else if request.Body.Mode != "" {
+			p.result.warn("%s: body mode '%s' is not converted", where, request.Body.Mode)
+		}
+	}
+
+	for _, event := range item.Event {
+		lines := scriptLines(event.Script.Exec)
+		switch {
+		case event.Listen == "test":
+			p.script(lines, &step, where)
+		case len(lines) > 0:
+			p.result.warn("%s: %s script is not converted", where, event.Listen)
+		}
+	}
+	return []scenario.Step{step}
+}
+
+// The URL is either a string or an object with the raw string
+func postmanUrl(data json.RawMessage) string {
+	var raw string
+	if json.Unmarshal(data, &raw) == nil {
+		return raw
+	}
+	var url struct {
+		Raw string `json:"raw"`
+	}
+	json.Unmarshal(data, &url)
+	return url.Raw
+}
+
+// Exec of a script is either a list of lines or a single string
+func scriptLines(data json.RawMessage) []string {
+	var lines []string
+	if json.Unmarshal(data, &lines) == nil {
+		return splitLines(lines)
+	}
+	var text string
+	if json.Unmarshal(data, &text) == nil {
+		return splitLines([]string{text})
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package liquibase
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+const (
+	DEFAULT_SERVICE  string = "liquibase"
+	DEFAULT_PROFILE  string = "integration-tests-only"
+	DEFAULT_ENV_FILE string = "./.env.test"
+)
+
+// Runs liquibase commands either through a docker-compose service or through a local command line
+type Runner struct {
+	// Directory with docker-compose.yml
+	Dir string
+	// Replaces the docker-compose invocation, e.g. "liquibase --url=jdbc:postgresql://localhost:5432/test --changelog-file=changelog.xml"
+	Command string
+	Service string
+	Profile string
+	EnvFile string
+}
+
+func NewRunner(dir string, command string, service string) Runner {
+	if service == "" {
+		service = DEFAULT_SERVICE
+	}
+	return Runner{Dir: dir, Command: command, Service: service, Profile: DEFAULT_PROFILE, EnvFile: DEFAULT_ENV_FILE}
+}
+
+func (p Runner) args(liquibaseArgs ...string) []string {
+	if p.Command != "" {
+		return append(strings.Fields(p.Command), liquibaseArgs...)
+	}
+	result := []string{"docker-compose", "--env-file", p.EnvFile, "--profile", p.Profile, "run", "--rm", p.Service}
+	return append(result, liquibaseArgs...)
+}
+
+func (p Runner) Run(liquibaseArgs ...string) (string, error) {
+	args := p.args(liquibaseArgs...)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = p.Dir
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		return output.String(), fmt.Errorf("error at running '%s': %v\n%s", strings.Join(args, " "), err, output.String())
+	}
+	return output.String(), nil
+}
+
+func (p Runner) UpdateCount(count int) error {
+	_, err := p.Run("update-count", fmt.Sprint(count))
+	return err
+}
+
+func (p Runner) RollbackCount(count int) error {
+	_, err := p.Run("rollback-count", fmt.Sprint(count))
+	return err
+}
+
+func (p Runner) Update() error {
+	_, err := p.Run("update")
+	return err
+}
+
+func (p Runner) DropAll() error {
+	_, err := p.Run("drop-all")
+	return err
+}
+
+type Changeset struct {
+	Id       string
+	Author   string
+	Filename string
+}
+
+func (p Changeset) String() string {
+	return p.Filename + "::" + p.Id + "::" + p.Author
+}
+
+// Changesets applied so far in the order of execution, empty if liquibase has not run yet
+func Applied(ctx context.Context, db *sql.DB) ([]Changeset, error) {
+	var exists bool
+	if err := db.QueryRowContext(ctx, "SELECT to_regclass('databasechangelog') IS NOT NULL").Scan(&exists); err != nil {
+		return nil, fmt.Errorf("error at checking databasechangelog: %v", err)
+	}
+	if !exists {
+		return nil, nil
+	}
+	rows, err := db.QueryContext(ctx, "SELECT id, author, filename FROM databasechangelog ORDER BY orderexecuted")
+	if err != nil {
+		return nil, fmt.Errorf("error at reading databasechangelog: %v", err)
+	}
+	defer rows.Close()
+
+	var result []Changeset
+	for rows.Next() {
+		var changeset Changeset
+		if err := rows.Scan(&changeset.Id, &changeset.Author, &changeset.Filename); err != nil {
+			return nil, fmt.Errorf("error at reading databasechangelog: %v", err)
+		}
+		result = append(result, changeset)
+	}
+	return result, rows.Err()
+}
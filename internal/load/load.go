@@ -0,0 +1,233 @@
+package load
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Request to the system under test. Do returns the HTTP status code of the response.
+type Operation struct {
+	Name     string
+	Endpoint string
+	Weight   int
+	Do       func() (int, error)
+}
+
+type Options struct {
+	// Workers sending the requests, with Rps it limits the requests in flight
+	Concurrency int
+	Rps         float64
+	Duration    time.Duration
+	Requests    int
+	Seed        int64
+}
+
+type Sample struct {
+	Operation      string
+	Endpoint       string
+	HttpStatusCode int
+	Latency        time.Duration
+	Err            error
+}
+
+func IsErrorSample(sample Sample) bool {
+	return sample.Err != nil || sample.HttpStatusCode < 200 || sample.HttpStatusCode >= 400
+}
+
+// Drives the weighted mix of operations either at the target RPS (open model)
+// or with a fixed number of workers issuing requests back to back (closed model).
+// In the open model a request is due on its schedule, its latency is measured from that time,
+// so the time it waited for a free worker counts as well.
+// Stops when Duration is elapsed, Requests are sent or the context is done.
+func Run(ctx context.Context, options Options, mix []Operation) (Report, error) {
+	if len(mix) == 0 {
+		return Report{}, fmt.Errorf("empty operations mix")
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = 1
+	}
+	if options.Duration <= 0 && options.Requests <= 0 {
+		return Report{}, fmt.Errorf("either duration or requests count is required")
+	}
+
+	picker, err := newPicker(mix, options.Seed)
+	if err != nil {
+		return Report{}, err
+	}
+
+	if options.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Duration)
+		defer cancel()
+	}
+
+	jobs := make(chan job)
+	samples := make(chan Sample, options.Concurrency)
+
+	var wg sync.WaitGroup
+	for i := 0; i < options.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				samples <- execute(j)
+			}
+		}()
+	}
+
+	collected := make(chan []Sample)
+	go func() {
+		var result []Sample
+		for sample := range samples {
+			result = append(result, sample)
+		}
+		collected <- result
+	}()
+
+	start := time.Now()
+	dispatch(ctx, options, picker, jobs)
+	close(jobs)
+	wg.Wait()
+	close(samples)
+	elapsed := time.Since(start)
+
+	return NewReport(<-collected, elapsed), nil
+}
+
+type job struct {
+	op Operation
+	// Time the request is due in the open model, zero in the closed one
+	scheduled time.Time
+}
+
+func dispatch(ctx context.Context, options Options, picker *picker, jobs chan<- job) {
+	var interval time.Duration
+	var timer *time.Timer
+	if options.Rps > 0 {
+		interval = time.Duration(float64(time.Second) / options.Rps)
+		timer = time.NewTimer(interval)
+		timer.Stop()
+		defer timer.Stop()
+	}
+
+	start := time.Now()
+	for sent := 0; options.Requests <= 0 || sent < options.Requests; sent++ {
+		j := job{op: picker.next()}
+		if timer != nil {
+			// The schedule does not shift when the workers fall behind, the late requests are sent at once
+			j.scheduled = start.Add(time.Duration(sent) * interval)
+			if wait := time.Until(j.scheduled); wait > 0 {
+				timer.Reset(wait)
+				select {
+				case <-ctx.Done():
+					return
+				case <-timer.C:
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case jobs <- j:
+		}
+	}
+}
+
+func execute(j job) Sample {
+	start := j.scheduled
+	if start.IsZero() {
+		start = time.Now()
+	}
+	httpStatusCode, err := j.op.Do()
+	return Sample{
+		Operation:      j.op.Name,
+		Endpoint:       j.op.Endpoint,
+		HttpStatusCode: httpStatusCode,
+		Latency:        time.Since(start),
+		Err:            err,
+	}
+}
+
+type picker struct {
+	mutex  sync.Mutex
+	random *rand.Rand
+	mix    []Operation
+	total  int
+}
+
+func newPicker(mix []Operation, seed int64) (*picker, error) {
+	total := 0
+	for _, op := range mix {
+		if op.Weight < 0 {
+			return nil, fmt.Errorf("negative weight of operation '%s'", op.Name)
+		}
+		total += op.Weight
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("total weight of operations mix is 0")
+	}
+	return &picker{random: rand.New(rand.NewSource(seed)), mix: mix, total: total}, nil
+}
+
+func (p *picker) next() Operation {
+	p.mutex.Lock()
+	n := p.random.Intn(p.total)
+	p.mutex.Unlock()
+
+	for _, op := range p.mix {
+		if n < op.Weight {
+			return op
+		}
+		n -= op.Weight
+	}
+	return p.mix[len(p.mix)-1]
+}
+
+// Parses a mix specification like "GetNotes=70,CreateNote=20,UpdateNote=10" into weights by operation name
+func ParseMix(spec string) (map[string]int, error) {
+	result := make(map[string]int)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("wrong format of mix item '%s', expected 'Name=Weight'", item)
+		}
+		weight, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(parts[1]), "%"))
+		if err != nil {
+			return nil, fmt.Errorf("wrong weight of mix item '%s': %v", item, err)
+		}
+		result[strings.TrimSpace(parts[0])] = weight
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("empty mix specification")
+	}
+	return result, nil
+}
+
+// Selects operations from the registry according to the weights
+func BuildMix(registry map[string]Operation, weights map[string]int) ([]Operation, error) {
+	var result []Operation
+	for name, weight := range weights {
+		op, ok := registry[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown operation '%s'", name)
+		}
+		op.Weight = weight
+		result = append(result, op)
+	}
+	sortOperations(result)
+	return result, nil
+}
+
+func sortOperations(ops []Operation) {
+	sort.Slice(ops, func(i, j int) bool { return ops[i].Name < ops[j].Name })
+}
@@ -0,0 +1,35 @@
+package load
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sleeping(latency time.Duration) []Operation {
+	return []Operation{{Name: "Sleep", Endpoint: "/sleep", Weight: 1, Do: func() (int, error) {
+		time.Sleep(latency)
+		return http.StatusOK, nil
+	}}}
+}
+
+// The worker is 5 times slower than the schedule, the later requests wait for it and their latency grows
+func TestOpenModelCountsWaitingForWorker(t *testing.T) {
+	report, err := Run(context.Background(), Options{Concurrency: 1, Rps: 100, Requests: 10}, sleeping(50*time.Millisecond))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 10, report.Total.Count)
+	// The last request was due at 90ms and finished at about 500ms
+	assert.GreaterOrEqual(t, report.Total.Max, 350*time.Millisecond)
+}
+
+func TestClosedModelLatency(t *testing.T) {
+	report, err := Run(context.Background(), Options{Concurrency: 1, Requests: 5}, sleeping(20*time.Millisecond))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 5, report.Total.Count)
+	assert.Less(t, report.Total.Max, 200*time.Millisecond)
+}
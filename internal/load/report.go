@@ -0,0 +1,184 @@
+package load
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Upper bounds of the latency histogram buckets, the last bucket is unbounded
+var HistogramBuckets = []time.Duration{
+	1 * time.Millisecond,
+	2 * time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	2500 * time.Millisecond,
+}
+
+type Bucket struct {
+	UpperBound time.Duration
+	Count      int
+}
+
+type Stats struct {
+	Operation  string
+	Endpoint   string
+	Count      int
+	Errors     int
+	ErrorRate  float64
+	Throughput float64
+	Min        time.Duration
+	Mean       time.Duration
+	P50        time.Duration
+	P95        time.Duration
+	P99        time.Duration
+	Max        time.Duration
+	StatusCode map[int]int
+	Histogram  []Bucket
+}
+
+type Report struct {
+	Elapsed    time.Duration
+	Total      Stats
+	Operations []Stats
+}
+
+func NewReport(samples []Sample, elapsed time.Duration) Report {
+	byOperation := make(map[string][]Sample)
+	for _, sample := range samples {
+		byOperation[sample.Operation] = append(byOperation[sample.Operation], sample)
+	}
+
+	result := Report{Elapsed: elapsed, Total: newStats("TOTAL", "", samples, elapsed)}
+	for name, operationSamples := range byOperation {
+		result.Operations = append(result.Operations, newStats(name, operationSamples[0].Endpoint, operationSamples, elapsed))
+	}
+	sort.Slice(result.Operations, func(i, j int) bool { return result.Operations[i].Operation < result.Operations[j].Operation })
+	return result
+}
+
+func newStats(name string, endpoint string, samples []Sample, elapsed time.Duration) Stats {
+	result := Stats{Operation: name, Endpoint: endpoint, Count: len(samples), StatusCode: make(map[int]int)}
+	if len(samples) == 0 {
+		return result
+	}
+
+	latencies := make([]time.Duration, 0, len(samples))
+	var sum time.Duration
+	for _, sample := range samples {
+		if IsErrorSample(sample) {
+			result.Errors++
+		}
+		result.StatusCode[sample.HttpStatusCode]++
+		latencies = append(latencies, sample.Latency)
+		sum += sample.Latency
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	result.ErrorRate = float64(result.Errors) / float64(result.Count)
+	if elapsed > 0 {
+		result.Throughput = float64(result.Count) / elapsed.Seconds()
+	}
+	result.Min = latencies[0]
+	result.Max = latencies[len(latencies)-1]
+	result.Mean = sum / time.Duration(len(latencies))
+	result.P50 = Percentile(latencies, 50)
+	result.P95 = Percentile(latencies, 95)
+	result.P99 = Percentile(latencies, 99)
+	result.Histogram = histogram(latencies)
+	return result
+}
+
+// Nearest-rank percentile of sorted latencies
+func Percentile(sorted []time.Duration, percentile float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(percentile / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+func histogram(sorted []time.Duration) []Bucket {
+	result := make([]Bucket, len(HistogramBuckets)+1)
+	for i, bound := range HistogramBuckets {
+		result[i].UpperBound = bound
+	}
+	result[len(HistogramBuckets)].UpperBound = -1
+
+	i := 0
+	for _, latency := range sorted {
+		for i < len(HistogramBuckets) && latency > HistogramBuckets[i] {
+			i++
+		}
+		result[i].Count++
+	}
+	return result
+}
+
+func (p Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(p)
+}
+
+func (p Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "elapsed: %v\n\n", p.Elapsed.Round(time.Millisecond))
+	fmt.Fprintln(tw, "OPERATION\tENDPOINT\tCOUNT\tRPS\tERRORS\tP50\tP95\tP99\tMAX\t")
+	for _, stats := range append(p.Operations, p.Total) {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%.1f\t%.2f%%\t%v\t%v\t%v\t%v\t\n",
+			stats.Operation, stats.Endpoint, stats.Count, stats.Throughput, stats.ErrorRate*100,
+			round(stats.P50), round(stats.P95), round(stats.P99), round(stats.Max))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, stats := range p.Operations {
+		fmt.Fprintf(w, "\n%s latency histogram:\n", stats.Operation)
+		writeHistogram(w, stats)
+	}
+	return nil
+}
+
+func writeHistogram(w io.Writer, stats Stats) {
+	const width = 40
+	maxCount := 0
+	for _, bucket := range stats.Histogram {
+		if bucket.Count > maxCount {
+			maxCount = bucket.Count
+		}
+	}
+	for _, bucket := range stats.Histogram {
+		if bucket.Count == 0 {
+			continue
+		}
+		label := "+Inf"
+		if bucket.UpperBound >= 0 {
+			label = "<= " + bucket.UpperBound.String()
+		}
+		bar := strings.Repeat("#", int(math.Ceil(float64(bucket.Count)/float64(maxCount)*width)))
+		fmt.Fprintf(w, "  %10s  %-*s %d\n", label, width, bar, bucket.Count)
+	}
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(10 * time.Microsecond)
+}
@@ -0,0 +1,83 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	ALERT_FIRING   string = "firing"
+	ALERT_RESOLVED string = "resolved"
+)
+
+// Body of the webhook request
+type Alert struct {
+	Status string
+	Probe  string
+	Target string
+	// Failures in a row, 0 when resolved
+	ConsecutiveFailures int
+	// Error of the last failed run
+	Error string `json:",omitempty"`
+	At    time.Time
+}
+
+// Posts an alert once a probe failed Threshold times in a row, and a resolved one when it passes again
+type Webhook struct {
+	Url       string
+	Threshold int
+	Client    *http.Client
+	mutex     sync.Mutex
+	// Probes with a firing alert
+	firing map[string]bool
+}
+
+func NewWebhook(url string, threshold int) *Webhook {
+	if threshold <= 0 {
+		threshold = 1
+	}
+	return &Webhook{Url: url, Threshold: threshold, Client: &http.Client{Timeout: 10 * time.Second}, firing: make(map[string]bool)}
+}
+
+// The alert to send after the run of the probe, nil if nothing changed
+func (p *Webhook) Update(alert Alert) *Alert {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	switch {
+	case alert.ConsecutiveFailures >= p.Threshold && !p.firing[alert.Probe]:
+		p.firing[alert.Probe] = true
+		alert.Status = ALERT_FIRING
+		return &alert
+	case alert.ConsecutiveFailures == 0 && p.firing[alert.Probe]:
+		delete(p.firing, alert.Probe)
+		alert.Status = ALERT_RESOLVED
+		return &alert
+	}
+	return nil
+}
+
+func (p *Webhook) Send(ctx context.Context, alert Alert) error {
+	data, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.Url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("unable to create webhook request '%s': %v", p.Url, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to send alert to '%s': %v", p.Url, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook '%s' returned %d", p.Url, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookUpdate(t *testing.T) {
+	webhook := NewWebhook("http://localhost/alerts", 2)
+	tests := []struct {
+		Probe          string
+		Failures       int
+		ExpectedStatus string
+	}{
+		{Probe: PROBE_PING, Failures: 0},
+		{Probe: PROBE_PING, Failures: 1},
+		{Probe: PROBE_PING, Failures: 2, ExpectedStatus: ALERT_FIRING},
+		// Fires once per streak
+		{Probe: PROBE_PING, Failures: 3},
+		{Probe: PROBE_NOTE, Failures: 2, ExpectedStatus: ALERT_FIRING},
+		{Probe: PROBE_PING, Failures: 0, ExpectedStatus: ALERT_RESOLVED},
+		{Probe: PROBE_PING, Failures: 0},
+		{Probe: PROBE_PING, Failures: 2, ExpectedStatus: ALERT_FIRING},
+		{Probe: PROBE_NOTE, Failures: 0, ExpectedStatus: ALERT_RESOLVED},
+	}
+	for i, test := range tests {
+		alert := webhook.Update(Alert{Probe: test.Probe, ConsecutiveFailures: test.Failures})
+
+		if test.ExpectedStatus == "" {
+			assert.Nil(t, alert, "update %d", i)
+			continue
+		}
+		if assert.NotNil(t, alert, "update %d", i) {
+			assert.Equal(t, test.ExpectedStatus, alert.Status, "update %d", i)
+			assert.Equal(t, test.Probe, alert.Probe, "update %d", i)
+		}
+	}
+}
+
+func TestWebhookSend(t *testing.T) {
+	var received []Alert
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var alert Alert
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&alert))
+		received = append(received, alert)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	webhook := NewWebhook(server.URL, 1)
+	alert := Alert{Status: ALERT_FIRING, Probe: PROBE_PING, Target: "https://staging.example.com", ConsecutiveFailures: 1, Error: "connection refused", At: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)}
+
+	assert.Nil(t, webhook.Send(context.Background(), alert))
+	status = http.StatusInternalServerError
+	err := webhook.Send(context.Background(), alert)
+
+	assert.EqualError(t, err, "webhook '"+server.URL+"' returned 500")
+	assert.Equal(t, []Alert{alert, alert}, received)
+}
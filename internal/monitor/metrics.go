@@ -0,0 +1,131 @@
+package monitor
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Upper bounds of the latency histogram, in seconds
+var LATENCY_BUCKETS = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type probeMetrics struct {
+	successes           int
+	failures            int
+	consecutiveFailures int
+	lastSuccess         bool
+	lastLatency         time.Duration
+	lastRun             time.Time
+	// Counts per bucket of LATENCY_BUCKETS, not cumulative
+	buckets    []int
+	latencySum float64
+}
+
+// Results of the probes in the Prometheus text exposition format
+type Metrics struct {
+	mutex  sync.Mutex
+	probes map[string]*probeMetrics
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{probes: make(map[string]*probeMetrics)}
+}
+
+// Records a run of the probe and returns the failures in a row including it
+func (p *Metrics) Observe(probe string, success bool, latency time.Duration, at time.Time) int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	m, ok := p.probes[probe]
+	if !ok {
+		m = &probeMetrics{buckets: make([]int, len(LATENCY_BUCKETS))}
+		p.probes[probe] = m
+	}
+	if success {
+		m.successes++
+		m.consecutiveFailures = 0
+	} else {
+		m.failures++
+		m.consecutiveFailures++
+	}
+	m.lastSuccess = success
+	m.lastLatency = latency
+	m.lastRun = at
+	seconds := latency.Seconds()
+	m.latencySum += seconds
+	for i, bound := range LATENCY_BUCKETS {
+		if seconds <= bound {
+			m.buckets[i]++
+			break
+		}
+	}
+	return m.consecutiveFailures
+}
+
+func (p *Metrics) Write(w io.Writer) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	var names []string
+	for name := range p.probes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	gauge := func(metric string, help string, value func(m *probeMetrics) float64) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n", metric, help, metric)
+		for _, name := range names {
+			fmt.Fprintf(&b, "%s{probe=\"%s\"} %s\n", metric, escape(name), number(value(p.probes[name])))
+		}
+	}
+	gauge("qa_probe_success", "Whether the last run of the probe succeeded.", func(m *probeMetrics) float64 {
+		if m.lastSuccess {
+			return 1
+		}
+		return 0
+	})
+	gauge("qa_probe_latency_seconds", "Latency of the last run of the probe.", func(m *probeMetrics) float64 {
+		return m.lastLatency.Seconds()
+	})
+	gauge("qa_probe_consecutive_failures", "Failed runs of the probe in a row.", func(m *probeMetrics) float64 {
+		return float64(m.consecutiveFailures)
+	})
+	gauge("qa_probe_last_run_timestamp_seconds", "Unix time of the last run of the probe.", func(m *probeMetrics) float64 {
+		return float64(m.lastRun.UnixNano()) / 1e9
+	})
+
+	b.WriteString("# HELP qa_probe_runs_total Runs of the probe by result.\n# TYPE qa_probe_runs_total counter\n")
+	for _, name := range names {
+		m := p.probes[name]
+		fmt.Fprintf(&b, "qa_probe_runs_total{probe=\"%s\",result=\"success\"} %d\n", escape(name), m.successes)
+		fmt.Fprintf(&b, "qa_probe_runs_total{probe=\"%s\",result=\"failure\"} %d\n", escape(name), m.failures)
+	}
+
+	b.WriteString("# HELP qa_probe_duration_seconds Latency of the runs of the probe.\n# TYPE qa_probe_duration_seconds histogram\n")
+	for _, name := range names {
+		m := p.probes[name]
+		cumulative := 0
+		for i, bound := range LATENCY_BUCKETS {
+			cumulative += m.buckets[i]
+			fmt.Fprintf(&b, "qa_probe_duration_seconds_bucket{probe=\"%s\",le=\"%s\"} %d\n", escape(name), number(bound), cumulative)
+		}
+		count := m.successes + m.failures
+		fmt.Fprintf(&b, "qa_probe_duration_seconds_bucket{probe=\"%s\",le=\"+Inf\"} %d\n", escape(name), count)
+		fmt.Fprintf(&b, "qa_probe_duration_seconds_sum{probe=\"%s\"} %s\n", escape(name), number(m.latencySum))
+		fmt.Fprintf(&b, "qa_probe_duration_seconds_count{probe=\"%s\"} %d\n", escape(name), count)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func number(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// Label values escape backslashes, quotes and line feeds
+func escape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
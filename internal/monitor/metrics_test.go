@@ -0,0 +1,49 @@
+package monitor
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsObserve(t *testing.T) {
+	metrics := NewMetrics()
+	at := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 1, metrics.Observe(PROBE_PING, false, time.Second, at))
+	assert.Equal(t, 2, metrics.Observe(PROBE_PING, false, time.Second, at))
+	// Every probe has its own count
+	assert.Equal(t, 1, metrics.Observe(PROBE_SAFE_PING, false, time.Second, at))
+	assert.Equal(t, 0, metrics.Observe(PROBE_PING, true, time.Second, at))
+	assert.Equal(t, 1, metrics.Observe(PROBE_PING, false, time.Second, at))
+}
+
+func TestMetricsWrite(t *testing.T) {
+	metrics := NewMetrics()
+	at := time.Date(2026, 10, 18, 12, 0, 0, 500000000, time.UTC)
+	metrics.Observe(PROBE_PING, true, 3*time.Millisecond, at)
+	metrics.Observe(PROBE_PING, true, 75*time.Millisecond, at)
+	metrics.Observe(PROBE_PING, false, 30*time.Second, at.Add(time.Minute))
+	metrics.Observe(PROBE_NOTE, true, 250*time.Millisecond, at)
+	metrics.Observe("quote\"and\\slash\nline", false, 10*time.Millisecond, at)
+	var body bytes.Buffer
+
+	assert.Nil(t, metrics.Write(&body))
+
+	expected, err := os.ReadFile("testdata/metrics.txt")
+	assert.Nil(t, err)
+	assert.Equal(t, string(expected), body.String())
+}
+
+func TestMetricsWriteEmpty(t *testing.T) {
+	var body bytes.Buffer
+
+	assert.Nil(t, NewMetrics().Write(&body))
+
+	assert.True(t, strings.HasPrefix(body.String(), "# HELP qa_probe_success Whether the last run of the probe succeeded.\n# TYPE qa_probe_success gauge\n"))
+	assert.NotContains(t, body.String(), "probe=")
+}
@@ -0,0 +1,107 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Config struct {
+	// Base URL of the API, the alerts refer to it
+	TargetUrl string
+	// Time between the starts of two rounds of the probes
+	Interval time.Duration
+	// Time a probe may take, a slower one fails
+	Timeout time.Duration
+	Probes  []Probe
+	// Nil disables the alerts
+	Webhook *Webhook
+	// Receives a line per failed probe and per alert
+	Log io.Writer
+}
+
+// Runs the probes in a loop and keeps their results for /metrics
+type Monitor struct {
+	config  Config
+	target  Target
+	metrics *Metrics
+}
+
+func New(target Target, config Config) *Monitor {
+	if config.Log == nil {
+		config.Log = io.Discard
+	}
+	return &Monitor{config: config, target: target, metrics: NewMetrics()}
+}
+
+func (p *Monitor) Metrics() *Metrics {
+	return p.metrics
+}
+
+// Runs a round of the probes every Interval until the context is done
+func (p *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.config.Interval)
+	defer ticker.Stop()
+	for {
+		p.RunOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Runs every probe once, one after another
+func (p *Monitor) RunOnce(ctx context.Context) {
+	for _, probe := range p.config.Probes {
+		if ctx.Err() != nil {
+			return
+		}
+		p.runProbe(ctx, probe)
+	}
+}
+
+func (p *Monitor) runProbe(ctx context.Context, probe Probe) {
+	probeCtx, cancel := context.WithTimeout(ctx, p.config.Timeout)
+	started := time.Now()
+	err := probe.Run(probeCtx, p.target)
+	latency := time.Since(started)
+	cancel()
+	if ctx.Err() != nil {
+		// Stopped by the shutdown, not a failure of the API
+		return
+	}
+	failures := p.metrics.Observe(probe.Name, err == nil, latency, started)
+	if err != nil {
+		fmt.Fprintf(p.config.Log, "probe %s failed (%d in a row): %v\n", probe.Name, failures, err)
+	}
+	if p.config.Webhook == nil {
+		return
+	}
+	alert := Alert{Probe: probe.Name, Target: p.config.TargetUrl, ConsecutiveFailures: failures, At: started.UTC()}
+	if err != nil {
+		alert.Error = err.Error()
+	}
+	if update := p.config.Webhook.Update(alert); update != nil {
+		fmt.Fprintf(p.config.Log, "alert %s for probe %s\n", update.Status, update.Probe)
+		if err := p.config.Webhook.Send(ctx, *update); err != nil {
+			fmt.Fprintf(p.config.Log, "error: %v\n", err)
+		}
+	}
+}
+
+func (p *Monitor) Router() *gin.Engine {
+	r := gin.New()
+	r.Use(gin.Recovery())
+	r.GET("/metrics", func(c *gin.Context) {
+		c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		c.Status(http.StatusOK)
+		p.metrics.Write(c.Writer)
+	})
+	return r
+}
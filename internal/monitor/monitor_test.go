@@ -0,0 +1,79 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonitorMetricsAndAlerts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var mutex sync.Mutex
+	var alerts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert Alert
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&alert))
+		mutex.Lock()
+		defer mutex.Unlock()
+		alerts = append(alerts, alert.Status+" "+alert.Probe+" "+alert.Error)
+	}))
+	defer server.Close()
+
+	healthy := false
+	probes := []Probe{
+		{Name: PROBE_PING, Run: func(ctx context.Context, target Target) error {
+			return nil
+		}},
+		{Name: PROBE_NOTE, Run: func(ctx context.Context, target Target) error {
+			if healthy {
+				return nil
+			}
+			return fmt.Errorf("expected 201, got 500")
+		}},
+	}
+	var log strings.Builder
+	m := New(nil, Config{TargetUrl: "https://staging.example.com", Interval: time.Minute, Timeout: time.Second, Probes: probes, Webhook: NewWebhook(server.URL, 2), Log: &log})
+
+	m.RunOnce(context.Background())
+	m.RunOnce(context.Background())
+	healthy = true
+	m.RunOnce(context.Background())
+
+	assert.Equal(t, []string{"firing note expected 201, got 500", "resolved note "}, alerts)
+	assert.Contains(t, log.String(), "probe note failed (2 in a row): expected 201, got 500\n")
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/metrics", nil)
+	m.Router().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "qa_probe_success{probe=\"note\"} 1\n")
+	assert.Contains(t, w.Body.String(), "qa_probe_runs_total{probe=\"note\",result=\"failure\"} 2\n")
+	assert.Contains(t, w.Body.String(), "qa_probe_runs_total{probe=\"ping\",result=\"success\"} 3\n")
+	assert.Contains(t, w.Body.String(), "qa_probe_duration_seconds_count{probe=\"ping\"} 3\n")
+}
+
+func TestMonitorStoppedProbeIsNotObserved(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	probes := []Probe{{Name: PROBE_PING, Run: func(ctx context.Context, target Target) error {
+		cancel()
+		return ctx.Err()
+	}}}
+	m := New(nil, Config{Interval: time.Minute, Timeout: time.Second, Probes: probes})
+
+	m.RunOnce(ctx)
+
+	var body strings.Builder
+	assert.Nil(t, m.Metrics().Write(&body))
+	assert.NotContains(t, body.String(), "probe=")
+}
@@ -0,0 +1,177 @@
+package monitor
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/apiclient"
+)
+
+const (
+	PROBE_PING      string = "ping"
+	PROBE_SAFE_PING string = "safe-ping"
+	PROBE_NOTE      string = "note"
+)
+
+// API under monitoring, e.g. difftest.NewUrlTarget
+type Target = apiclient.Target
+
+type Probe struct {
+	Name string
+	Run  func(ctx context.Context, target Target) error
+}
+
+// Login of the user the probes act as
+type Credentials struct {
+	Email    string
+	Password string
+}
+
+// Entities the note probe relies on. The user and the tag must exist unless Temporary is set,
+// then a zero UserId or TagId makes the probe create a user or a tag for the note and delete it together with the note.
+//
+// DELETE of the API is a soft delete: every round leaves a note row in the deleted state, and with Temporary
+// a user and a tag row as well. Their names start with "monitor-", purge them from the DB of the target periodically.
+type NoteFixtures struct {
+	UserId    int
+	TagId     int
+	Temporary bool
+	UserRole  string
+	UserState string
+	TagState  string
+	NoteState string
+}
+
+func (p NoteFixtures) Validate() error {
+	if p.Temporary {
+		return nil
+	}
+	if p.UserId <= 0 || p.TagId <= 0 {
+		return fmt.Errorf("the note probe needs ids of an existing user and tag, or temporary fixtures")
+	}
+	return nil
+}
+
+func Probes(credentials Credentials, fixtures NoteFixtures) []Probe {
+	return []Probe{
+		{Name: PROBE_PING, Run: ping},
+		{Name: PROBE_SAFE_PING, Run: func(ctx context.Context, target Target) error {
+			token, err := login(ctx, target, credentials)
+			if err != nil {
+				return err
+			}
+			_, err = apiclient.Expect(ctx, target, http.MethodGet, "/safe-ping", token, nil, http.StatusOK)
+			return err
+		}},
+		{Name: PROBE_NOTE, Run: func(ctx context.Context, target Target) error {
+			token, err := login(ctx, target, credentials)
+			if err != nil {
+				return err
+			}
+			return note(ctx, target, token, fixtures)
+		}},
+	}
+}
+
+func ping(ctx context.Context, target Target) error {
+	_, err := apiclient.Expect(ctx, target, http.MethodGet, "/ping", "", nil, http.StatusOK)
+	return err
+}
+
+func login(ctx context.Context, target Target, credentials Credentials) (string, error) {
+	body, err := apiclient.Expect(ctx, target, http.MethodPost, "/auth/login", "", map[string]any{"Email": credentials.Email, "Password": credentials.Password}, http.StatusOK)
+	if err != nil {
+		return "", err
+	}
+	var tokens struct {
+		AccessToken string
+	}
+	if err := json.Unmarshal([]byte(body), &tokens); err != nil || tokens.AccessToken == "" {
+		return "", fmt.Errorf("login returned no access token: %s", body)
+	}
+	return tokens.AccessToken, nil
+}
+
+// Creates a note, reads it back and deletes it. Whatever was created is deleted even if a step failed.
+func note(ctx context.Context, target Target, token string, fixtures NoteFixtures) (err error) {
+	var cleanup []string
+	defer func() {
+		// The probe may have timed out, the cleanup gets its own time
+		cleanupCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		for i := len(cleanup) - 1; i >= 0; i-- {
+			if _, deleteErr := apiclient.Expect(cleanupCtx, target, http.MethodDelete, cleanup[i], token, nil, http.StatusOK); deleteErr != nil {
+				if err == nil {
+					err = fmt.Errorf("cleanup: %v", deleteErr)
+				} else {
+					err = fmt.Errorf("%v; cleanup: %v", err, deleteErr)
+				}
+			}
+		}
+	}()
+
+	userId := fixtures.UserId
+	if userId == 0 {
+		name := apiclient.Unique("monitor-user")
+		userId, err = apiclient.Create(ctx, target, token, "/users", map[string]any{
+			"Login":    name,
+			"Email":    name + "@monitor.qa",
+			"Password": password(),
+			"Role":     fixtures.UserRole,
+			"State":    fixtures.UserState,
+		})
+		if err != nil {
+			return err
+		}
+		cleanup = append(cleanup, "/users/"+strconv.Itoa(userId))
+	}
+	tagId := fixtures.TagId
+	if tagId == 0 {
+		tagId, err = apiclient.Create(ctx, target, token, "/tags", map[string]any{"Name": apiclient.Unique("monitor-tag"), "State": fixtures.TagState})
+		if err != nil {
+			return err
+		}
+		cleanup = append(cleanup, "/tags/"+strconv.Itoa(tagId))
+	}
+
+	topic := apiclient.Unique("monitor-note")
+	noteId, err := apiclient.Create(ctx, target, token, "/notes", map[string]any{
+		"Text":   "Synthetic monitoring note",
+		"Topic":  topic,
+		"TagId":  tagId,
+		"UserId": userId,
+		"State":  fixtures.NoteState,
+	})
+	if err != nil {
+		return err
+	}
+	path := "/notes/" + strconv.Itoa(noteId)
+	cleanup = append(cleanup, path)
+	body, err := apiclient.Expect(ctx, target, http.MethodGet, path, token, nil, http.StatusOK)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(body, topic) {
+		return fmt.Errorf("GET %s returned another note: %s", path, body)
+	}
+	// Deleting the note is a step of the probe, the cleanup deletes it only if the probe failed before
+	if _, err = apiclient.Expect(ctx, target, http.MethodDelete, path, token, nil, http.StatusOK); err != nil {
+		return err
+	}
+	cleanup = cleanup[:len(cleanup)-1]
+	return nil
+}
+
+// Nobody logs in as a temporary user, the password only has to be valid
+func password() string {
+	buffer := make([]byte, 16)
+	rand.Read(buffer)
+	return "Monitor-" + hex.EncodeToString(buffer)
+}
@@ -0,0 +1,13 @@
+package pathname
+
+import (
+	"regexp"
+	"strings"
+)
+
+var unsafePathChars = regexp.MustCompile(`[^A-Za-z0-9._=-]+`)
+
+// File or directory name made of the value, e.g. "GET /notes?limit=100" -> "GET_notes_limit=100"
+func Slug(value string) string {
+	return strings.Trim(unsafePathChars.ReplaceAllString(value, "_"), "_")
+}
@@ -0,0 +1,121 @@
+package perf
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/load"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/pathname"
+)
+
+// Stored results of one endpoint in one scenario
+type Baseline struct {
+	Scenario   string
+	Endpoint   string
+	Operation  string
+	Count      int
+	ErrorRate  float64
+	Throughput float64
+	Mean       time.Duration
+	P50        time.Duration
+	P95        time.Duration
+	P99        time.Duration
+	RecordedAt time.Time
+}
+
+func BaselinePath(dir string, scenario string, endpoint string) string {
+	return filepath.Join(dir, pathname.Slug(scenario), pathname.Slug(endpoint)+".json")
+}
+
+func FromReport(scenario string, report load.Report) []Baseline {
+	var result []Baseline
+	now := time.Now().UTC()
+	for _, stats := range report.Operations {
+		result = append(result, Baseline{
+			Scenario:   scenario,
+			Endpoint:   stats.Endpoint,
+			Operation:  stats.Operation,
+			Count:      stats.Count,
+			ErrorRate:  stats.ErrorRate,
+			Throughput: stats.Throughput,
+			Mean:       stats.Mean,
+			P50:        stats.P50,
+			P95:        stats.P95,
+			P99:        stats.P99,
+			RecordedAt: now,
+		})
+	}
+	return result
+}
+
+func LoadReport(path string) (load.Report, error) {
+	var result load.Report
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return result, fmt.Errorf("unable to read load report '%s': %v", path, err)
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return result, fmt.Errorf("unable to parse load report '%s': %v", path, err)
+	}
+	return result, nil
+}
+
+// Returns nil baseline without error if it has not been recorded yet
+func ReadBaseline(dir string, scenario string, endpoint string) (*Baseline, error) {
+	path := BaselinePath(dir, scenario, endpoint)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read baseline '%s': %v", path, err)
+	}
+	var result Baseline
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("unable to parse baseline '%s': %v", path, err)
+	}
+	return &result, nil
+}
+
+// Baselines of every endpoint recorded for the scenario, sorted by endpoint
+func ListBaselines(dir string, scenario string) ([]Baseline, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, pathname.Slug(scenario), "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to list baselines of '%s': %v", scenario, err)
+	}
+	var result []Baseline
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read baseline '%s': %v", path, err)
+		}
+		var baseline Baseline
+		if err := json.Unmarshal(data, &baseline); err != nil {
+			return nil, fmt.Errorf("unable to parse baseline '%s': %v", path, err)
+		}
+		result = append(result, baseline)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Endpoint < result[j].Endpoint })
+	return result, nil
+}
+
+func WriteBaselines(dir string, baselines []Baseline) error {
+	for _, baseline := range baselines {
+		path := BaselinePath(dir, baseline.Scenario, baseline.Endpoint)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("unable to create baseline dir for '%s': %v", path, err)
+		}
+		data, err := json.MarshalIndent(baseline, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+			return fmt.Errorf("unable to write baseline '%s': %v", path, err)
+		}
+	}
+	return nil
+}
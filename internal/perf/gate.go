@@ -0,0 +1,244 @@
+package perf
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	METRIC_P50         string = "p50"
+	METRIC_P95         string = "p95"
+	METRIC_P99         string = "p99"
+	METRIC_MEAN        string = "mean"
+	METRIC_THROUGHPUT  string = "throughput"
+	METRIC_ERROR_RATE  string = "error_rate"
+	DEFAULT_TOLERANCES string = "p50=20%,p95=20%,p99=30%,error_rate=1%"
+
+	STATUS_OK        string = "OK"
+	STATUS_REGRESSED string = "REGRESSED"
+	STATUS_IMPROVED  string = "IMPROVED"
+	STATUS_NEW       string = "NEW"
+	STATUS_MISSING   string = "MISSING"
+)
+
+func GetPossibleMetrics() []string {
+	return []string{METRIC_P50, METRIC_P95, METRIC_P99, METRIC_MEAN, METRIC_THROUGHPUT, METRIC_ERROR_RATE}
+}
+
+// Allowed regression per metric in percents. For latencies and throughput it's a relative change,
+// for error rate it's an absolute change in percentage points. Endpoint specific values override defaults.
+type Tolerances struct {
+	Default   map[string]float64
+	Endpoints map[string]map[string]float64
+}
+
+// Parses specification like "p95=20%,GET /notes?limit=100@p95=10%,error_rate=1%"
+func ParseTolerances(spec string) (Tolerances, error) {
+	result := Tolerances{Default: make(map[string]float64), Endpoints: make(map[string]map[string]float64)}
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		endpoint := ""
+		if i := strings.LastIndex(item, "@"); i >= 0 {
+			endpoint = item[:i]
+			item = item[i+1:]
+		}
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 {
+			return result, fmt.Errorf("wrong format of tolerance '%s', expected '[endpoint@]metric=N%%'", item)
+		}
+		metric := strings.TrimSpace(parts[0])
+		if !isKnownMetric(metric) {
+			return result, fmt.Errorf("unknown metric '%s'. Possible values: %v", metric, GetPossibleMetrics())
+		}
+		value, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(parts[1]), "%"), 64)
+		if err != nil {
+			return result, fmt.Errorf("wrong value of tolerance '%s': %v", item, err)
+		}
+		if endpoint == "" {
+			result.Default[metric] = value
+			continue
+		}
+		if result.Endpoints[endpoint] == nil {
+			result.Endpoints[endpoint] = make(map[string]float64)
+		}
+		result.Endpoints[endpoint][metric] = value
+	}
+	return result, nil
+}
+
+func isKnownMetric(metric string) bool {
+	for _, m := range GetPossibleMetrics() {
+		if m == metric {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Tolerances) For(endpoint string) map[string]float64 {
+	result := make(map[string]float64)
+	for metric, value := range p.Default {
+		result[metric] = value
+	}
+	for metric, value := range p.Endpoints[endpoint] {
+		result[metric] = value
+	}
+	return result
+}
+
+type Comparison struct {
+	Scenario  string
+	Endpoint  string
+	Metric    string
+	Baseline  float64
+	Current   float64
+	Change    float64
+	Tolerance float64
+	Status    string
+}
+
+type GateResult struct {
+	Comparisons []Comparison
+}
+
+func (p GateResult) Failed() bool {
+	for _, c := range p.Comparisons {
+		if c.Status == STATUS_REGRESSED || c.Status == STATUS_MISSING {
+			return true
+		}
+	}
+	return false
+}
+
+// Compares current results with the stored baselines. Endpoints without baseline are reported as NEW,
+// endpoints of the same scenarios that have a baseline but were not measured are reported as MISSING.
+func Compare(dir string, current []Baseline, tolerances Tolerances) (GateResult, error) {
+	var result GateResult
+	measured := make(map[string]map[string]bool)
+	var scenarios []string
+	for _, cur := range current {
+		if measured[cur.Scenario] == nil {
+			measured[cur.Scenario] = make(map[string]bool)
+			scenarios = append(scenarios, cur.Scenario)
+		}
+		measured[cur.Scenario][cur.Endpoint] = true
+
+		base, err := ReadBaseline(dir, cur.Scenario, cur.Endpoint)
+		if err != nil {
+			return result, err
+		}
+		if base == nil {
+			result.Comparisons = append(result.Comparisons, Comparison{Scenario: cur.Scenario, Endpoint: cur.Endpoint, Status: STATUS_NEW})
+			continue
+		}
+		limits := tolerances.For(cur.Endpoint)
+		metrics := make([]string, 0, len(limits))
+		for metric := range limits {
+			metrics = append(metrics, metric)
+		}
+		sort.Strings(metrics)
+		for _, metric := range metrics {
+			comparison, err := compareMetric(*base, cur, metric, limits[metric])
+			if err != nil {
+				return result, err
+			}
+			result.Comparisons = append(result.Comparisons, comparison)
+		}
+	}
+	for _, scenario := range scenarios {
+		baselines, err := ListBaselines(dir, scenario)
+		if err != nil {
+			return result, err
+		}
+		for _, base := range baselines {
+			if !measured[scenario][base.Endpoint] {
+				result.Comparisons = append(result.Comparisons, Comparison{Scenario: scenario, Endpoint: base.Endpoint, Status: STATUS_MISSING})
+			}
+		}
+	}
+	return result, nil
+}
+
+func compareMetric(base Baseline, cur Baseline, metric string, tolerance float64) (Comparison, error) {
+	result := Comparison{Scenario: cur.Scenario, Endpoint: cur.Endpoint, Metric: metric, Tolerance: tolerance}
+	result.Baseline = metricValue(base, metric)
+	result.Current = metricValue(cur, metric)
+
+	// positive change is always a degradation
+	switch metric {
+	case METRIC_ERROR_RATE:
+		result.Change = (result.Current - result.Baseline) * 100
+	default:
+		if result.Baseline == 0 {
+			return result, fmt.Errorf("baseline of '%s' '%s' has zero %s, record it again", cur.Scenario, cur.Endpoint, metric)
+		}
+		result.Change = (result.Current - result.Baseline) / result.Baseline * 100
+		if metric == METRIC_THROUGHPUT {
+			result.Change = -result.Change
+		}
+	}
+
+	switch {
+	// Nothing was measured, e.g. every request failed
+	case result.Current == 0 && metric != METRIC_ERROR_RATE:
+		result.Status = STATUS_REGRESSED
+	case result.Change > tolerance:
+		result.Status = STATUS_REGRESSED
+	case result.Change < -tolerance:
+		result.Status = STATUS_IMPROVED
+	default:
+		result.Status = STATUS_OK
+	}
+	return result, nil
+}
+
+func metricValue(b Baseline, metric string) float64 {
+	switch metric {
+	case METRIC_P50:
+		return float64(b.P50)
+	case METRIC_P95:
+		return float64(b.P95)
+	case METRIC_P99:
+		return float64(b.P99)
+	case METRIC_MEAN:
+		return float64(b.Mean)
+	case METRIC_THROUGHPUT:
+		return b.Throughput
+	case METRIC_ERROR_RATE:
+		return b.ErrorRate
+	}
+	return 0
+}
+
+func formatValue(metric string, value float64) string {
+	switch metric {
+	case METRIC_THROUGHPUT:
+		return fmt.Sprintf("%.1f rps", value)
+	case METRIC_ERROR_RATE:
+		return fmt.Sprintf("%.2f%%", value*100)
+	}
+	return time.Duration(value).Round(10 * time.Microsecond).String()
+}
+
+func (p GateResult) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SCENARIO\tENDPOINT\tMETRIC\tBASELINE\tCURRENT\tCHANGE\tTOLERANCE\tSTATUS\t")
+	for _, c := range p.Comparisons {
+		if c.Status == STATUS_NEW || c.Status == STATUS_MISSING {
+			fmt.Fprintf(tw, "%s\t%s\t-\t-\t-\t-\t-\t%s\t\n", c.Scenario, c.Endpoint, c.Status)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%+.1f%%\t%.1f%%\t%s\t\n",
+			c.Scenario, c.Endpoint, c.Metric, formatValue(c.Metric, c.Baseline), formatValue(c.Metric, c.Current),
+			c.Change, c.Tolerance, c.Status)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,109 @@
+package perf
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTolerances(t *testing.T) {
+	tolerances, err := ParseTolerances("p95=20%, GET /notes?limit=100@p95=10%,error_rate=1,GET /notes?limit=100@throughput=5%")
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]float64{METRIC_P95: 20, METRIC_ERROR_RATE: 1}, tolerances.Default)
+	assert.Equal(t, map[string]float64{METRIC_P95: 10, METRIC_THROUGHPUT: 5, METRIC_ERROR_RATE: 1}, tolerances.For("GET /notes?limit=100"))
+	assert.Equal(t, map[string]float64{METRIC_P95: 20, METRIC_ERROR_RATE: 1}, tolerances.For("GET /tags"))
+}
+
+func TestParseTolerancesErrors(t *testing.T) {
+	for _, spec := range []string{"p95", "p42=10%", "p95=ten%", "GET /notes@p95"} {
+		_, err := ParseTolerances(spec)
+
+		assert.NotNil(t, err, spec)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	dir := t.TempDir()
+	base := Baseline{Scenario: "read", Endpoint: "GET /notes", P95: 100 * time.Millisecond, Throughput: 200, ErrorRate: 0.01}
+	assert.Nil(t, WriteBaselines(dir, []Baseline{base, {Scenario: "read", Endpoint: "GET /tags", P95: time.Millisecond, Throughput: 1}}))
+	tolerances := Tolerances{Default: map[string]float64{METRIC_P95: 20, METRIC_THROUGHPUT: 10, METRIC_ERROR_RATE: 1}}
+
+	tests := []struct {
+		Name           string
+		P95            time.Duration
+		Throughput     float64
+		ErrorRate      float64
+		ExpectedChange map[string]float64
+		ExpectedStatus map[string]string
+	}{
+		{
+			Name: "Same", P95: 100 * time.Millisecond, Throughput: 200, ErrorRate: 0.01,
+			ExpectedChange: map[string]float64{METRIC_P95: 0, METRIC_THROUGHPUT: 0, METRIC_ERROR_RATE: 0},
+			ExpectedStatus: map[string]string{METRIC_P95: STATUS_OK, METRIC_THROUGHPUT: STATUS_OK, METRIC_ERROR_RATE: STATUS_OK},
+		},
+		{
+			// Relative changes are taken from the baseline
+			Name: "Degraded", P95: 150 * time.Millisecond, Throughput: 150, ErrorRate: 0.03,
+			ExpectedChange: map[string]float64{METRIC_P95: 50, METRIC_THROUGHPUT: 25, METRIC_ERROR_RATE: 2},
+			ExpectedStatus: map[string]string{METRIC_P95: STATUS_REGRESSED, METRIC_THROUGHPUT: STATUS_REGRESSED, METRIC_ERROR_RATE: STATUS_REGRESSED},
+		},
+		{
+			Name: "Improved", P95: 50 * time.Millisecond, Throughput: 300, ErrorRate: 0,
+			ExpectedChange: map[string]float64{METRIC_P95: -50, METRIC_THROUGHPUT: -50, METRIC_ERROR_RATE: -1},
+			ExpectedStatus: map[string]string{METRIC_P95: STATUS_IMPROVED, METRIC_THROUGHPUT: STATUS_IMPROVED, METRIC_ERROR_RATE: STATUS_OK},
+		},
+		{
+			Name: "NothingMeasured", P95: 0, Throughput: 0, ErrorRate: 1,
+			ExpectedChange: map[string]float64{METRIC_P95: -100, METRIC_THROUGHPUT: 100, METRIC_ERROR_RATE: 99},
+			ExpectedStatus: map[string]string{METRIC_P95: STATUS_REGRESSED, METRIC_THROUGHPUT: STATUS_REGRESSED, METRIC_ERROR_RATE: STATUS_REGRESSED},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			current := []Baseline{
+				{Scenario: "read", Endpoint: "GET /notes", P95: test.P95, Throughput: test.Throughput, ErrorRate: test.ErrorRate},
+				{Scenario: "read", Endpoint: "GET /users"},
+			}
+
+			result, err := Compare(dir, current, tolerances)
+
+			assert.Nil(t, err)
+			assert.True(t, result.Failed())
+			changes := make(map[string]float64)
+			statuses := make(map[string]string)
+			for _, c := range result.Comparisons {
+				if c.Endpoint == "GET /notes" {
+					changes[c.Metric] = c.Change
+					statuses[c.Metric] = c.Status
+				}
+			}
+			assert.InDeltaMapValues(t, test.ExpectedChange, changes, 0.001)
+			assert.Equal(t, test.ExpectedStatus, statuses)
+			assert.Contains(t, result.Comparisons, Comparison{Scenario: "read", Endpoint: "GET /users", Status: STATUS_NEW})
+			assert.Contains(t, result.Comparisons, Comparison{Scenario: "read", Endpoint: "GET /tags", Status: STATUS_MISSING})
+		})
+	}
+}
+
+func TestCompareOnlyMeasuredScenarios(t *testing.T) {
+	dir := t.TempDir()
+	base := Baseline{Scenario: "read", Endpoint: "GET /notes", P95: time.Millisecond, Throughput: 1}
+	assert.Nil(t, WriteBaselines(dir, []Baseline{base, {Scenario: "write", Endpoint: "POST /notes", P95: time.Millisecond, Throughput: 1}}))
+
+	result, err := Compare(dir, []Baseline{base}, Tolerances{Default: map[string]float64{METRIC_P95: 20}})
+
+	assert.Nil(t, err)
+	assert.False(t, result.Failed())
+	assert.Equal(t, []Comparison{{Scenario: "read", Endpoint: "GET /notes", Metric: METRIC_P95, Baseline: float64(time.Millisecond), Current: float64(time.Millisecond), Tolerance: 20, Status: STATUS_OK}}, result.Comparisons)
+}
+
+func TestCompareZeroBaseline(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, WriteBaselines(dir, []Baseline{{Scenario: "read", Endpoint: "GET /notes", P95: time.Millisecond}}))
+
+	_, err := Compare(dir, []Baseline{{Scenario: "read", Endpoint: "GET /notes", P95: time.Millisecond, Throughput: 10}}, Tolerances{Default: map[string]float64{METRIC_THROUGHPUT: 10}})
+
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,138 @@
+package runner
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	ACTION_RUN    string = "run"
+	ACTION_PASS   string = "pass"
+	ACTION_FAIL   string = "fail"
+	ACTION_SKIP   string = "skip"
+	ACTION_OUTPUT string = "output"
+	// Compiler errors, newer `go test -json` reports them as events instead of printing to stderr
+	ACTION_BUILD_OUTPUT string = "build-output"
+)
+
+// Line of `go test -json`, see `go doc test2json`
+type Event struct {
+	Time    time.Time
+	Action  string
+	Package string
+	Test    string
+	Elapsed float64
+	Output  string
+}
+
+// Outcome of every test of one `go test` invocation
+type attempt struct {
+	outcomes map[string]string
+	elapsed  map[string]time.Duration
+	output   map[string]*strings.Builder
+	// Output of packages that failed outside of tests, e.g. build errors
+	packageFailures map[string]string
+	packageOutput   map[string]*strings.Builder
+	// Package of every test
+	packages map[string]string
+	order    []string
+}
+
+func newAttempt() *attempt {
+	return &attempt{
+		outcomes:        make(map[string]string),
+		elapsed:         make(map[string]time.Duration),
+		output:          make(map[string]*strings.Builder),
+		packageFailures: make(map[string]string),
+		packageOutput:   make(map[string]*strings.Builder),
+		packages:        make(map[string]string),
+	}
+}
+
+// Reads the events until EOF, lines that are not JSON, e.g. from a failed build, go to the package output.
+// Output events are passed to w as they come.
+func (p *attempt) read(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		var event Event
+		if err := json.Unmarshal(line, &event); err != nil || event.Action == "" {
+			p.packageLog("", string(line)+"\n")
+			if w != nil {
+				w.Write(append(line, '\n'))
+			}
+			continue
+		}
+		if event.Action == ACTION_OUTPUT && w != nil {
+			io.WriteString(w, event.Output)
+		}
+		p.add(event)
+	}
+	return scanner.Err()
+}
+
+func (p *attempt) add(event Event) {
+	if event.Test == "" {
+		switch event.Action {
+		case ACTION_BUILD_OUTPUT:
+			p.packageLog("", event.Output)
+		case ACTION_OUTPUT:
+			p.packageLog(event.Package, event.Output)
+		case ACTION_FAIL:
+			p.packageFailures[event.Package] = p.packageLog(event.Package, "")
+		}
+		return
+	}
+	switch event.Action {
+	case ACTION_RUN:
+		if _, ok := p.packages[event.Test]; !ok {
+			p.order = append(p.order, event.Test)
+		}
+		p.packages[event.Test] = event.Package
+	case ACTION_OUTPUT:
+		if p.output[event.Test] == nil {
+			p.output[event.Test] = &strings.Builder{}
+		}
+		p.output[event.Test].WriteString(event.Output)
+	case ACTION_PASS, ACTION_FAIL, ACTION_SKIP:
+		p.outcomes[event.Test] = event.Action
+		p.elapsed[event.Test] = time.Duration(event.Elapsed * float64(time.Second))
+	}
+}
+
+func (p *attempt) packageLog(pkg string, output string) string {
+	if p.packageOutput[pkg] == nil {
+		p.packageOutput[pkg] = &strings.Builder{}
+	}
+	p.packageOutput[pkg].WriteString(output)
+	return p.packageOutput[pkg].String()
+}
+
+// Package failures not explained by a failed test
+func (p *attempt) failedPackages() map[string]string {
+	result := make(map[string]string)
+	for pkg, output := range p.packageFailures {
+		explained := false
+		for test, outcome := range p.outcomes {
+			if outcome == ACTION_FAIL && p.packages[test] == pkg {
+				explained = true
+				break
+			}
+		}
+		if !explained {
+			result[pkg] = output + p.packageLog("", "")
+		}
+	}
+	return result
+}
+
+func (p *attempt) testOutput(test string) string {
+	if p.output[test] == nil {
+		return ""
+	}
+	return p.output[test].String()
+}
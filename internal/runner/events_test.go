@@ -0,0 +1,78 @@
+package runner
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readAttempt(t *testing.T, path string) *attempt {
+	file, err := os.Open(path)
+	assert.Nil(t, err)
+	defer file.Close()
+
+	result := newAttempt()
+	var output strings.Builder
+	assert.Nil(t, result.read(file, &output))
+	assert.Contains(t, output.String(), "FAIL\t")
+	return result
+}
+
+func TestReadEvents(t *testing.T) {
+	result := readAttempt(t, "testdata/tests.jsonl")
+
+	assert.Equal(t, []string{"TestStable", "TestGroup", "TestGroup/flaky_one", "TestGroup/always", "TestGroup/ok", "TestSkip"}, result.order)
+	assert.Equal(t, map[string]string{
+		"TestStable":          OUTCOME_PASS,
+		"TestGroup":           OUTCOME_FAIL,
+		"TestGroup/flaky_one": OUTCOME_FAIL,
+		"TestGroup/always":    OUTCOME_FAIL,
+		"TestGroup/ok":        OUTCOME_PASS,
+		"TestSkip":            OUTCOME_SKIP,
+	}, result.outcomes)
+	assert.Equal(t, 1500*time.Millisecond, result.elapsed["TestGroup"])
+	assert.Equal(t, "qa/ft", result.packages["TestGroup/always"])
+	assert.Equal(t, "=== RUN   TestGroup/always\n    ft_test.go:17: broken\n--- FAIL: TestGroup/always (0.50s)\n", result.testOutput("TestGroup/always"))
+	// The package failed because of its tests
+	assert.Empty(t, result.failedPackages())
+}
+
+func TestFailedPackages(t *testing.T) {
+	result := readAttempt(t, "testdata/package-failures.jsonl")
+
+	failures := result.failedPackages()
+
+	assert.Equal(t, []string{"qa/broken", "qa/panics"}, keys(failures))
+	assert.Contains(t, failures["qa/broken"], "FAIL\tqa/broken [build failed]\n")
+	assert.Contains(t, failures["qa/broken"], "broken/b_test.go:5:28: undefined: undefined\n")
+	assert.Contains(t, failures["qa/panics"], "panic: test timed out after 1s\n")
+	// Started but never finished
+	assert.Equal(t, []string{"TestHangs"}, result.order)
+	assert.Empty(t, result.outcomes)
+}
+
+func TestReadPlainLines(t *testing.T) {
+	result := newAttempt()
+	stream := "# qa/broken\nbroken/b_test.go:5:28: undefined: undefined\n" +
+		`{"Action":"output","Package":"qa/broken","Output":"FAIL\tqa/broken [build failed]\n"}` + "\n" +
+		`{"Action":"fail","Package":"qa/broken"}` + "\n"
+
+	assert.Nil(t, result.read(strings.NewReader(stream), nil))
+
+	assert.Equal(t, map[string]string{
+		"qa/broken": "FAIL\tqa/broken [build failed]\n# qa/broken\nbroken/b_test.go:5:28: undefined: undefined\n",
+	}, result.failedPackages())
+}
+
+func keys(m map[string]string) []string {
+	var result []string
+	for key := range m {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,51 @@
+package runner
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Tests whose failures do not fail the run. An entry covers the test and all of its subtests, e.g.
+//
+//	# token timing, see the refresh token TTL in .env.test
+//	TestApiAuthRefresh/ExpiredRefreshToken
+type Quarantine []string
+
+// Entries of the file, a missing file is an empty quarantine
+func LoadQuarantine(path string) (Quarantine, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read quarantine '%s': %v", path, err)
+	}
+	defer file.Close()
+
+	var result Quarantine
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// Names as `go test` prints them
+		result = append(result, strings.ReplaceAll(line, " ", "_"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read quarantine '%s': %v", path, err)
+	}
+	return result, nil
+}
+
+func (p Quarantine) Contains(test string) bool {
+	for _, entry := range p {
+		if test == entry || strings.HasPrefix(test, entry+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadQuarantine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quarantine.txt")
+	assert.Nil(t, os.WriteFile(path, []byte("# token timing\nTestApiAuthRefresh/Expired refresh token\n\n  TestGroup  \n"), 0644))
+
+	quarantine, err := LoadQuarantine(path)
+
+	assert.Nil(t, err)
+	assert.Equal(t, Quarantine{"TestApiAuthRefresh/Expired_refresh_token", "TestGroup"}, quarantine)
+}
+
+func TestLoadMissingQuarantine(t *testing.T) {
+	quarantine, err := LoadQuarantine(filepath.Join(t.TempDir(), "quarantine.txt"))
+
+	assert.Nil(t, err)
+	assert.Empty(t, quarantine)
+}
+
+func TestQuarantineContains(t *testing.T) {
+	quarantine := Quarantine{"TestApiAuthRefresh/Expired_refresh_token", "TestGroup"}
+	tests := []struct {
+		Test     string
+		Expected bool
+	}{
+		{Test: "TestGroup", Expected: true},
+		{Test: "TestGroup/always", Expected: true},
+		{Test: "TestGroup/always/nested", Expected: true},
+		{Test: "TestGroupOther", Expected: false},
+		{Test: "TestApiAuthRefresh/Expired_refresh_token", Expected: true},
+		{Test: "TestApiAuthRefresh/Expired_refresh_token#01", Expected: false},
+		{Test: "TestApiAuthRefresh", Expected: false},
+		{Test: "TestApiAuthRefresh/Valid_refresh_token", Expected: false},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.Expected, quarantine.Contains(test.Test), test.Test)
+	}
+}
@@ -0,0 +1,77 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// Table of the tests that did not simply pass or have flaked before, all tests if verbose,
+// with their flakiness rate over the recorded runs
+func WriteReport(w io.Writer, result Result, stats Stats, verbose bool) error {
+	for pkg, output := range result.PackageFailures {
+		fmt.Fprintf(w, "package %s failed:\n%s\n", pkg, output)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TEST\tRESULT\tATTEMPTS\tFLAKY RATE\tRUNS\t")
+	for _, test := range result.Tests {
+		s := stats[test.Name]
+		if s == nil {
+			s = &TestStats{}
+		}
+		if !verbose && test.Outcome == OUTCOME_PASS && s.Flakes == 0 {
+			continue
+		}
+		outcome := test.Outcome
+		if test.Quarantined {
+			outcome += " (quarantined)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%.1f%%\t%d\t\n", test.Name, outcome, test.Attempts, 100*s.FlakyRate(), s.Runs)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, test := range result.Tests {
+		if test.Outcome == OUTCOME_FAIL && test.Output != "" {
+			fmt.Fprintf(w, "\n--- %s\n%s", test.Name, test.Output)
+		}
+	}
+	_, err := fmt.Fprintf(w, "\n%d passed, %d failed, %d flaky, %d skipped in %s\n",
+		result.Count(OUTCOME_PASS), result.Count(OUTCOME_FAIL), result.Count(OUTCOME_FLAKY), result.Count(OUTCOME_SKIP), result.Elapsed.Round(time.Millisecond))
+	return err
+}
+
+// Tests that flaked at least once, the flakiest first
+func WriteFlakiest(w io.Writer, stats Stats, limit int) error {
+	var names []string
+	for name, s := range stats {
+		if s.Flakes > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := stats[names[i]].FlakyRate(), stats[names[j]].FlakyRate()
+		if a != b {
+			return a > b
+		}
+		return names[i] < names[j]
+	})
+	if limit > 0 && len(names) > limit {
+		names = names[:limit]
+	}
+	if len(names) == 0 {
+		_, err := fmt.Fprintln(w, "no flaky tests")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TEST\tFLAKY RATE\tFLAKES\tFAILURES\tRUNS\tLAST FLAKY\t")
+	for _, name := range names {
+		s := stats[name]
+		fmt.Fprintf(tw, "%s\t%.1f%%\t%d\t%d\t%d\t%s\t\n", name, 100*s.FlakyRate(), s.Flakes, s.Failures, s.Runs, s.LastFlakyAt.Format(time.RFC3339))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,266 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	OUTCOME_PASS  string = "pass"
+	OUTCOME_FAIL  string = "fail"
+	OUTCOME_FLAKY string = "flaky"
+	OUTCOME_SKIP  string = "skip"
+
+	DEFAULT_PACKAGE string = "./test/integration/..."
+	DEFAULT_TAGS    string = "integration"
+)
+
+type Options struct {
+	// Directory `go test` runs in, the module root
+	Dir      string
+	Packages []string
+	Tags     string
+	// -run pattern of the first attempt
+	Run string
+	// Other arguments of `go test`, e.g. -timeout 30m
+	Args []string
+	// Added to the environment of the tests, e.g. QA_TARGET=stub
+	Env []string
+	// Times a failed test is run again
+	Retries    int
+	Quarantine Quarantine
+	// Receives the output of the tests as they run
+	Output io.Writer
+}
+
+type TestResult struct {
+	Package string
+	Name    string
+	Outcome string
+	// Runs of the test, 1 if it was not retried
+	Attempts    int
+	Elapsed     time.Duration
+	Quarantined bool
+	// Output of the last failed attempt
+	Output string `json:",omitempty"`
+}
+
+type Result struct {
+	Started time.Time
+	Elapsed time.Duration
+	Tests   []TestResult
+	// Packages that failed outside of tests, e.g. did not build, with their output
+	PackageFailures map[string]string `json:",omitempty"`
+}
+
+// True if a test failed on every attempt and is not quarantined, or a package failed outside of tests
+func (p Result) Failed() bool {
+	if len(p.PackageFailures) > 0 {
+		return true
+	}
+	for _, test := range p.Tests {
+		if test.Outcome == OUTCOME_FAIL && !test.Quarantined {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Result) Count(outcome string) int {
+	result := 0
+	for _, test := range p.Tests {
+		if test.Outcome == outcome {
+			result++
+		}
+	}
+	return result
+}
+
+// Runs the tests, then runs every failed test again up to Retries times. A test that passes on a retry
+// is flaky. Failed parents are retried only if none of their subtests failed, otherwise the subtests are
+// and then the parent is, if they all passed.
+func Run(ctx context.Context, options Options) (Result, error) {
+	result := Result{Started: time.Now().UTC()}
+	first, err := goTest(ctx, options, options.Packages, options.Run)
+	if err != nil {
+		return result, err
+	}
+	result.PackageFailures = first.failedPackages()
+
+	tests := make(map[string]*TestResult)
+	for _, name := range first.order {
+		outcome, ok := first.outcomes[name]
+		if !ok {
+			// Did not finish, e.g. the binary panicked or timed out
+			outcome = OUTCOME_FAIL
+		}
+		test := &TestResult{Package: first.packages[name], Name: name, Outcome: outcome, Attempts: 1, Elapsed: first.elapsed[name]}
+		if outcome == OUTCOME_FAIL {
+			test.Output = first.testOutput(name)
+		}
+		tests[name] = test
+	}
+
+	retry := func(name string) error {
+		test := tests[name]
+		for test.Outcome == OUTCOME_FAIL && test.Attempts <= options.Retries {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			attempt, err := goTest(ctx, options, []string{test.Package}, runPattern(name))
+			if err != nil {
+				return err
+			}
+			test.Attempts++
+			test.Elapsed = attempt.elapsed[name]
+			if attempt.outcomes[name] == OUTCOME_PASS {
+				test.Outcome = OUTCOME_FLAKY
+			} else {
+				test.Output = attempt.testOutput(name)
+			}
+		}
+		return nil
+	}
+	for _, name := range retryTargets(tests) {
+		if err := retry(name); err != nil {
+			return result, err
+		}
+	}
+	if err := settleParents(tests, retry); err != nil {
+		return result, err
+	}
+
+	for _, name := range first.order {
+		test := tests[name]
+		if hasChildren(tests, name) && test.Attempts == 1 {
+			continue
+		}
+		test.Quarantined = options.Quarantine.Contains(name)
+		result.Tests = append(result.Tests, *test)
+	}
+	result.Elapsed = time.Since(result.Started)
+	return result, nil
+}
+
+// Failed tests without failed subtests
+func retryTargets(tests map[string]*TestResult) []string {
+	var result []string
+	for name, test := range tests {
+		if test.Outcome == OUTCOME_FAIL && !hasFailedChild(tests, name) {
+			result = append(result, name)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// A parent whose failed subtests all passed on a retry may still have failed on its own assertions,
+// so it is retried as a whole and is flaky only if it passes. Deeper parents are settled first.
+func settleParents(tests map[string]*TestResult, retry func(name string) error) error {
+	var parents []string
+	for name, test := range tests {
+		if test.Outcome == OUTCOME_FAIL && test.Attempts == 1 && hasChildren(tests, name) {
+			parents = append(parents, name)
+		}
+	}
+	sort.Slice(parents, func(i, j int) bool {
+		if depth, other := strings.Count(parents[i], "/"), strings.Count(parents[j], "/"); depth != other {
+			return depth > other
+		}
+		return parents[i] < parents[j]
+	})
+	for _, name := range parents {
+		if hasFailedChild(tests, name) {
+			continue
+		}
+		if err := retry(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func hasFailedChild(tests map[string]*TestResult, name string) bool {
+	for other, child := range tests {
+		if child.Outcome == OUTCOME_FAIL && strings.HasPrefix(other, name+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func hasChildren(tests map[string]*TestResult, name string) bool {
+	for other := range tests {
+		if strings.HasPrefix(other, name+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// -run pattern that selects exactly the test, every level of the name is matched separately
+func runPattern(name string) string {
+	levels := strings.Split(name, "/")
+	for i, level := range levels {
+		levels[i] = "^" + regexp.QuoteMeta(level) + "$"
+	}
+	return strings.Join(levels, "/")
+}
+
+func goTest(ctx context.Context, options Options, packages []string, run string) (*attempt, error) {
+	args := []string{"test", "-json", "-count=1"}
+	if options.Tags != "" {
+		args = append(args, "-tags", options.Tags)
+	}
+	if run != "" {
+		args = append(args, "-run", run)
+	}
+	args = append(args, options.Args...)
+	if len(packages) == 0 {
+		packages = []string{DEFAULT_PACKAGE}
+	}
+	args = append(args, packages...)
+
+	cmd := exec.Command("go", args...)
+	// Killing only `go` would leave the test binary running, the whole group is killed on cancel
+	setProcessGroup(cmd)
+	cmd.Dir = options.Dir
+	cmd.Env = append(os.Environ(), options.Env...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	// Build errors come to stderr, they are read as package output
+	cmd.Stderr = cmd.Stdout
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("unable to run 'go %s': %v", strings.Join(args, " "), err)
+	}
+	finished := make(chan struct{})
+	defer close(finished)
+	go func() {
+		select {
+		case <-ctx.Done():
+			killProcessGroup(cmd)
+		case <-finished:
+		}
+	}()
+	result := newAttempt()
+	readErr := result.read(stdout, options.Output)
+	// A failed test makes `go test` exit with 1, failures are taken from the events
+	if err := cmd.Wait(); err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			return nil, fmt.Errorf("error at running 'go %s': %v", strings.Join(args, " "), err)
+		}
+	}
+	if readErr != nil {
+		return nil, fmt.Errorf("unable to read output of 'go %s': %v", strings.Join(args, " "), readErr)
+	}
+	return result, nil
+}
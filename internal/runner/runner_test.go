@@ -0,0 +1,137 @@
+package runner
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testResults(outcomes map[string]string) map[string]*TestResult {
+	result := make(map[string]*TestResult)
+	for name, outcome := range outcomes {
+		result[name] = &TestResult{Name: name, Outcome: outcome, Attempts: 1}
+	}
+	return result
+}
+
+func outcomes(tests map[string]*TestResult) map[string]string {
+	result := make(map[string]string)
+	for name, test := range tests {
+		result[name] = test.Outcome
+	}
+	return result
+}
+
+func TestRetryTargets(t *testing.T) {
+	tests := testResults(map[string]string{
+		"TestStable":              OUTCOME_PASS,
+		"TestOwnFailure":          OUTCOME_FAIL,
+		"TestGroup":               OUTCOME_FAIL,
+		"TestGroup/ok":            OUTCOME_PASS,
+		"TestGroup/failed":        OUTCOME_FAIL,
+		"TestGroup/nested":        OUTCOME_FAIL,
+		"TestGroup/nested/failed": OUTCOME_FAIL,
+		"TestParent":              OUTCOME_FAIL,
+		"TestParent/ok":           OUTCOME_PASS,
+		"TestGroupOther":          OUTCOME_SKIP,
+	})
+
+	assert.Equal(t, []string{"TestGroup/failed", "TestGroup/nested/failed", "TestOwnFailure", "TestParent"}, retryTargets(tests))
+}
+
+func TestSettleParents(t *testing.T) {
+	tests := []struct {
+		Name             string
+		Outcomes         map[string]string
+		Retried          map[string]int
+		Passes           map[string]bool
+		ExpectedRetries  []string
+		ExpectedOutcomes map[string]string
+	}{
+		{
+			Name:             "SubtestsPassedOnRetry",
+			Outcomes:         map[string]string{"TestGroup": OUTCOME_FAIL, "TestGroup/a": OUTCOME_FLAKY, "TestGroup/b": OUTCOME_PASS},
+			Retried:          map[string]int{"TestGroup/a": 2},
+			Passes:           map[string]bool{"TestGroup": true},
+			ExpectedRetries:  []string{"TestGroup"},
+			ExpectedOutcomes: map[string]string{"TestGroup": OUTCOME_FLAKY, "TestGroup/a": OUTCOME_FLAKY, "TestGroup/b": OUTCOME_PASS},
+		},
+		{
+			Name:             "OwnAssertionsFail",
+			Outcomes:         map[string]string{"TestGroup": OUTCOME_FAIL, "TestGroup/a": OUTCOME_FLAKY},
+			Retried:          map[string]int{"TestGroup/a": 2},
+			ExpectedRetries:  []string{"TestGroup"},
+			ExpectedOutcomes: map[string]string{"TestGroup": OUTCOME_FAIL, "TestGroup/a": OUTCOME_FLAKY},
+		},
+		{
+			Name:             "SubtestStillFails",
+			Outcomes:         map[string]string{"TestGroup": OUTCOME_FAIL, "TestGroup/a": OUTCOME_FAIL, "TestGroup/b": OUTCOME_FLAKY},
+			Retried:          map[string]int{"TestGroup/a": 3, "TestGroup/b": 2},
+			ExpectedOutcomes: map[string]string{"TestGroup": OUTCOME_FAIL, "TestGroup/a": OUTCOME_FAIL, "TestGroup/b": OUTCOME_FLAKY},
+		},
+		{
+			Name: "NestedParentsDeepestFirst",
+			Outcomes: map[string]string{
+				"TestGroup": OUTCOME_FAIL, "TestGroup/nested": OUTCOME_FAIL, "TestGroup/nested/a": OUTCOME_FLAKY,
+			},
+			Retried:         map[string]int{"TestGroup/nested/a": 2},
+			Passes:          map[string]bool{"TestGroup/nested": true, "TestGroup": true},
+			ExpectedRetries: []string{"TestGroup/nested", "TestGroup"},
+			ExpectedOutcomes: map[string]string{
+				"TestGroup": OUTCOME_FLAKY, "TestGroup/nested": OUTCOME_FLAKY, "TestGroup/nested/a": OUTCOME_FLAKY,
+			},
+		},
+		{
+			Name: "NestedParentFails",
+			Outcomes: map[string]string{
+				"TestGroup": OUTCOME_FAIL, "TestGroup/nested": OUTCOME_FAIL, "TestGroup/nested/a": OUTCOME_FLAKY,
+			},
+			Retried:         map[string]int{"TestGroup/nested/a": 2},
+			ExpectedRetries: []string{"TestGroup/nested"},
+			ExpectedOutcomes: map[string]string{
+				"TestGroup": OUTCOME_FAIL, "TestGroup/nested": OUTCOME_FAIL, "TestGroup/nested/a": OUTCOME_FLAKY,
+			},
+		},
+		{
+			Name:             "AlreadyRetried",
+			Outcomes:         map[string]string{"TestGroup": OUTCOME_FAIL, "TestGroup/a": OUTCOME_PASS},
+			Retried:          map[string]int{"TestGroup": 3},
+			ExpectedOutcomes: map[string]string{"TestGroup": OUTCOME_FAIL, "TestGroup/a": OUTCOME_PASS},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			results := testResults(test.Outcomes)
+			for name, attempts := range test.Retried {
+				results[name].Attempts = attempts
+			}
+			var retries []string
+			retry := func(name string) error {
+				retries = append(retries, name)
+				results[name].Attempts++
+				if test.Passes[name] {
+					results[name].Outcome = OUTCOME_FLAKY
+				}
+				return nil
+			}
+
+			assert.Nil(t, settleParents(results, retry))
+
+			assert.Equal(t, test.ExpectedRetries, retries)
+			assert.Equal(t, test.ExpectedOutcomes, outcomes(results))
+		})
+	}
+}
+
+func TestSettleParentsRetryError(t *testing.T) {
+	results := testResults(map[string]string{"TestGroup": OUTCOME_FAIL, "TestGroup/a": OUTCOME_PASS})
+
+	err := settleParents(results, func(name string) error { return fmt.Errorf("unable to run '%s'", name) })
+
+	assert.EqualError(t, err, "unable to run 'TestGroup'")
+}
+
+func TestRunPattern(t *testing.T) {
+	assert.Equal(t, `^TestGroup$/^flaky_one\(1\)$`, runPattern("TestGroup/flaky_one(1)"))
+}
@@ -0,0 +1,86 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Outcomes of a test over all recorded runs
+type TestStats struct {
+	Runs        int
+	Passes      int
+	Failures    int
+	Flakes      int
+	Skips       int
+	LastOutcome string
+	LastFlakyAt *time.Time `json:",omitempty"`
+}
+
+// Share of the runs the test passed only after a retry
+func (p TestStats) FlakyRate() float64 {
+	if p.Runs == 0 {
+		return 0
+	}
+	return float64(p.Flakes) / float64(p.Runs)
+}
+
+// Stats by test name
+type Stats map[string]*TestStats
+
+// Stats of the file, a missing file has no stats yet
+func LoadStats(path string) (Stats, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return make(Stats), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read stats '%s': %v", path, err)
+	}
+	result := make(Stats)
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("unable to parse stats '%s': %v", path, err)
+	}
+	return result, nil
+}
+
+func (p Stats) Add(result Result) {
+	for _, test := range result.Tests {
+		stats, ok := p[test.Name]
+		if !ok {
+			stats = &TestStats{}
+			p[test.Name] = stats
+		}
+		stats.Runs++
+		stats.LastOutcome = test.Outcome
+		switch test.Outcome {
+		case OUTCOME_PASS:
+			stats.Passes++
+		case OUTCOME_FAIL:
+			stats.Failures++
+		case OUTCOME_FLAKY:
+			stats.Flakes++
+			at := result.Started
+			stats.LastFlakyAt = &at
+		case OUTCOME_SKIP:
+			stats.Skips++
+		}
+	}
+}
+
+func (p Stats) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("unable to create stats dir for '%s': %v", path, err)
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("unable to write stats '%s': %v", path, err)
+	}
+	return nil
+}
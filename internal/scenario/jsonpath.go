@@ -0,0 +1,101 @@
+package scenario
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const LENGTH_FUNCTION string = "length()"
+
+// Subset of JSONPath: $ for the whole document, .name and ['name'] for fields, [n] for array items
+// (negative from the end) and a trailing .length() for the size of an array or object, or the number of characters of a string
+func Eval(document any, path string) (any, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("JSONPath '%s' does not start with $", path)
+	}
+	current := document
+	rest := path[1:]
+	for rest != "" {
+		var segment string
+		var index *int
+		switch {
+		case strings.HasPrefix(rest, "['"):
+			end := strings.Index(rest, "']")
+			if end < 0 {
+				return nil, fmt.Errorf("JSONPath '%s' has unclosed ['", path)
+			}
+			segment, rest = rest[2:end], rest[end+2:]
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("JSONPath '%s' has unclosed [", path)
+			}
+			i, err := strconv.Atoi(rest[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("JSONPath '%s' has wrong index '%s'", path, rest[1:end])
+			}
+			index, rest = &i, rest[end+1:]
+		case strings.HasPrefix(rest, "."):
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			segment, rest = rest[1:end+1], rest[end+1:]
+		default:
+			return nil, fmt.Errorf("JSONPath '%s' has unexpected '%s'", path, rest)
+		}
+
+		var err error
+		if index != nil {
+			current, err = item(current, *index)
+		} else if segment == LENGTH_FUNCTION && rest == "" {
+			current, err = length(current)
+		} else {
+			current, err = field(current, segment)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("JSONPath '%s': %v", path, err)
+		}
+	}
+	return current, nil
+}
+
+func item(value any, index int) (any, error) {
+	array, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("[%d] of a non array", index)
+	}
+	if index < 0 {
+		index += len(array)
+	}
+	if index < 0 || index >= len(array) {
+		return nil, fmt.Errorf("index %d is out of %d items", index, len(array))
+	}
+	return array[index], nil
+}
+
+func field(value any, name string) (any, error) {
+	object, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("field '%s' of a non object", name)
+	}
+	result, ok := object[name]
+	if !ok {
+		return nil, fmt.Errorf("no field '%s'", name)
+	}
+	return result, nil
+}
+
+func length(value any) (any, error) {
+	switch v := value.(type) {
+	case []any:
+		return float64(len(v)), nil
+	case map[string]any:
+		return float64(len(v)), nil
+	case string:
+		return float64(utf8.RuneCountInString(v)), nil
+	}
+	return nil, fmt.Errorf("length() of %T", value)
+}
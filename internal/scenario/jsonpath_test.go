@@ -0,0 +1,52 @@
+package scenario
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEval(t *testing.T) {
+	var document any
+	assert.Nil(t, json.Unmarshal([]byte(`{"Id": 1, "Data": [{"Name": "a"}, {"Name": "b"}], "Access-Token": "t", "Topic": "Заметка", "Empty": {}}`), &document))
+	tests := []struct {
+		Path          string
+		Expected      any
+		ExpectedError string
+	}{
+		{Path: "$", Expected: document},
+		{Path: "$.Id", Expected: float64(1)},
+		{Path: "$.Data[1].Name", Expected: "b"},
+		{Path: "$.Data[-1].Name", Expected: "b"},
+		{Path: "$['Access-Token']", Expected: "t"},
+		{Path: "$['Data'][0]['Name']", Expected: "a"},
+		{Path: "$.Data.length()", Expected: float64(2)},
+		{Path: "$.Empty.length()", Expected: float64(0)},
+		// Characters, not bytes
+		{Path: "$.Topic.length()", Expected: float64(7)},
+		{Path: "$.length()", Expected: float64(5)},
+		{Path: "Id", ExpectedError: "JSONPath 'Id' does not start with $"},
+		{Path: "$.Missing", ExpectedError: "JSONPath '$.Missing': no field 'Missing'"},
+		{Path: "$.Id.Name", ExpectedError: "JSONPath '$.Id.Name': field 'Name' of a non object"},
+		{Path: "$.Id[0]", ExpectedError: "JSONPath '$.Id[0]': [0] of a non array"},
+		{Path: "$.Data[2]", ExpectedError: "JSONPath '$.Data[2]': index 2 is out of 2 items"},
+		{Path: "$.Data[x]", ExpectedError: "JSONPath '$.Data[x]' has wrong index 'x'"},
+		{Path: "$.Data[0", ExpectedError: "JSONPath '$.Data[0' has unclosed ["},
+		{Path: "$['Id", ExpectedError: "JSONPath '$['Id' has unclosed ['"},
+		{Path: "$Id", ExpectedError: "JSONPath '$Id' has unexpected 'Id'"},
+		{Path: "$.Id.length()", ExpectedError: "JSONPath '$.Id.length()': length() of float64"},
+		// length() is a function only at the end of the path
+		{Path: "$.Data.length().Name", ExpectedError: "JSONPath '$.Data.length().Name': field 'length()' of a non object"},
+	}
+	for _, test := range tests {
+		result, err := Eval(document, test.Path)
+
+		if test.ExpectedError != "" {
+			assert.EqualError(t, err, test.ExpectedError, test.Path)
+			continue
+		}
+		assert.Nil(t, err, test.Path)
+		assert.Equal(t, test.Expected, result, test.Path)
+	}
+}
@@ -0,0 +1,289 @@
+package scenario
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/dbsnapshot"
+)
+
+const LOGIN_PATH string = "/auth/login"
+
+// Sends a request and returns the status and the body, e.g. the HTTP client of the integration tests
+type Client interface {
+	Serve(req *http.Request) (int, string, error)
+}
+
+// State of one execution of a scenario: captured variables and the access token of the last login
+type Run struct {
+	scenario *Scenario
+	client   Client
+	// DB of the API for the rows expectations, may be nil for scenarios without them
+	db    dbsnapshot.Queryer
+	vars  Vars
+	token string
+}
+
+func NewRun(scenario *Scenario, client Client, db dbsnapshot.Queryer) *Run {
+	vars := make(Vars, len(scenario.Vars))
+	for name, value := range scenario.Vars {
+		vars[name] = value
+	}
+	return &Run{scenario: scenario, client: client, db: db, vars: vars}
+}
+
+// Defines a variable for the following steps, e.g. a message constant of the API
+func (p *Run) Set(name string, value any) {
+	p.vars[name] = value
+}
+
+// Executes the step and returns the failed expectations, later steps usually depend on a step that failed
+func (p *Run) Step(ctx context.Context, step Step) []string {
+	req, err := p.request(ctx, step)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	httpStatusCode, body, err := p.client.Serve(req)
+	if err != nil {
+		return []string{fmt.Sprintf("error at %s %s: %v", req.Method, req.URL.RequestURI(), err)}
+	}
+
+	var failures []string
+	fail := func(format string, args ...any) {
+		failures = append(failures, fmt.Sprintf(format, args...))
+	}
+	expectedStatus := step.Expect.Status
+	if expectedStatus == 0 && step.Login != "" {
+		expectedStatus = http.StatusOK
+	}
+	if expectedStatus != 0 && httpStatusCode != expectedStatus {
+		fail("status is %d, expected %d, body: %s", httpStatusCode, expectedStatus, body)
+	}
+	if step.Expect.Body != nil {
+		expected, err := p.vars.Interpolate(*step.Expect.Body)
+		if err != nil {
+			fail("%v", err)
+		} else if body != expected {
+			fail("body is %s, expected %s", body, expected)
+		}
+	}
+
+	var document any
+	parseErr := json.Unmarshal([]byte(body), &document)
+	// Saved first, so that the expectations of the step may refer to what it created
+	for _, name := range sortedKeys(step.Save) {
+		if parseErr != nil {
+			fail("unable to save '%s', body is not JSON: %s", name, body)
+			continue
+		}
+		value, err := Eval(document, step.Save[name])
+		if err != nil {
+			fail("unable to save '%s': %v", name, err)
+			continue
+		}
+		p.vars[name] = value
+	}
+	for _, path := range sortedKeys(step.Expect.Json) {
+		if parseErr != nil {
+			fail("body is not JSON: %s", body)
+			break
+		}
+		if err := p.checkJson(document, path, step.Expect.Json[path]); err != nil {
+			fail("%v", err)
+		}
+	}
+	for _, rows := range step.Expect.Rows {
+		if err := p.checkRows(ctx, rows); err != nil {
+			fail("%v", err)
+		}
+	}
+
+	if step.Login != "" && httpStatusCode == http.StatusOK && parseErr == nil {
+		if token, err := Eval(document, "$.AccessToken"); err == nil {
+			p.token = fmt.Sprint(token)
+		}
+	}
+	return failures
+}
+
+func (p *Run) request(ctx context.Context, step Step) (*http.Request, error) {
+	method, path, body := http.MethodPost, LOGIN_PATH, step.Body
+	if step.Login != "" {
+		credentials, ok := p.scenario.Users[step.Login]
+		if !ok {
+			return nil, fmt.Errorf("unknown user '%s'", step.Login)
+		}
+		body = map[string]any{"Email": credentials.Email, "Password": credentials.Password}
+	} else {
+		var err error
+		if method, path, err = splitRequest(step.Request); err != nil {
+			return nil, err
+		}
+	}
+
+	path, err := p.vars.Interpolate(path)
+	if err != nil {
+		return nil, err
+	}
+	var payload io.Reader
+	if body != nil {
+		data, err := p.body(body)
+		if err != nil {
+			return nil, err
+		}
+		payload = bytes.NewBuffer(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, path, payload)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request '%s %s': %v", method, path, err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+	for name, value := range step.Headers {
+		value, err := p.vars.Interpolate(value)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set(name, value)
+	}
+	return req, nil
+}
+
+func (p *Run) body(body any) ([]byte, error) {
+	if text, ok := body.(string); ok {
+		text, err := p.vars.Interpolate(text)
+		return []byte(text), err
+	}
+	value, err := p.vars.Resolve(body)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+func (p *Run) checkJson(document any, path string, expected any) error {
+	actual, err := Eval(document, path)
+	if err != nil {
+		return err
+	}
+	expected, err = p.vars.Resolve(expected)
+	if err != nil {
+		return err
+	}
+	expected, err = normalize(expected)
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		return fmt.Errorf("%s is %s, expected %s", path, toJson(actual), toJson(expected))
+	}
+	return nil
+}
+
+func (p *Run) checkRows(ctx context.Context, expectation RowsExpectation) error {
+	if p.db == nil {
+		return fmt.Errorf("no DB to run '%s'", expectation.Query)
+	}
+	query, err := p.vars.Interpolate(expectation.Query)
+	if err != nil {
+		return err
+	}
+	args := make([]any, len(expectation.Args))
+	for i, arg := range expectation.Args {
+		if args[i], err = p.vars.Resolve(arg); err != nil {
+			return err
+		}
+	}
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("error at running '%s': %v", query, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	var first map[string]string
+	count := 0
+	for rows.Next() {
+		count++
+		if first != nil {
+			continue
+		}
+		values := make([]any, len(columns))
+		pointers := make([]any, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return fmt.Errorf("error at reading row of '%s': %v", query, err)
+		}
+		first = make(map[string]string, len(columns))
+		for i, column := range columns {
+			first[column] = dbsnapshot.Format(values[i])
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error at running '%s': %v", query, err)
+	}
+
+	if expectation.Count != nil && count != *expectation.Count {
+		return fmt.Errorf("'%s' returned %d rows, expected %d", query, count, *expectation.Count)
+	}
+	for _, column := range sortedKeys(expectation.First) {
+		if first == nil {
+			return fmt.Errorf("'%s' returned no rows", query)
+		}
+		actual, ok := first[column]
+		if !ok {
+			return fmt.Errorf("'%s' has no column '%s'", query, column)
+		}
+		expected, err := p.vars.Resolve(expectation.First[column])
+		if err != nil {
+			return err
+		}
+		if actual != Format(expected) {
+			return fmt.Errorf("'%s' returned %s = %q, expected %q", query, column, actual, Format(expected))
+		}
+	}
+	return nil
+}
+
+// Same types as json.Unmarshal produces, YAML gives ints and JSON gives float64
+func normalize(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compare %v: %v", value, err)
+	}
+	var result any
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
+
+func toJson(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	result := make([]string, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,172 @@
+package scenario
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const FILE_PATTERN string = "*.yaml"
+
+// Multi-step HTTP flow, e.g.
+//
+//	name: Tag lifecycle
+//	steps:
+//	  - request: POST /tags
+//	    body: {Name: books, State: NEW}
+//	    expect: {status: 201}
+//	    save: {tag: $}
+//	  - request: GET /tags/${tag}
+//	    expect:
+//	      status: 200
+//	      json: {$.Name: books}
+type Scenario struct {
+	Name string `yaml:"name"`
+	// Accounts for login steps by alias
+	Users map[string]Credentials `yaml:"users,omitempty"`
+	// Variables known before the first step
+	Vars  map[string]any `yaml:"vars,omitempty"`
+	Steps []Step         `yaml:"steps"`
+	// File the scenario was loaded from
+	Path string `yaml:"-"`
+}
+
+type Credentials struct {
+	Email    string `yaml:"email"`
+	Password string `yaml:"password"`
+}
+
+// Either a login or a request. Strings of the step may refer to variables as ${name} and to the environment as ${env:NAME}.
+type Step struct {
+	Name string `yaml:"name,omitempty"`
+	// Alias from the users of the scenario, later requests carry the access token of that user
+	Login string `yaml:"login,omitempty"`
+	// Method and path, e.g. "PUT /tags/${tag}"
+	Request string            `yaml:"request,omitempty"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+	// Sent as JSON, a string is sent as it is, e.g. to check malformed bodies
+	Body   any    `yaml:"body,omitempty"`
+	Expect Expect `yaml:"expect,omitempty"`
+	// Variable name -> JSONPath in the response body
+	Save map[string]string `yaml:"save,omitempty"`
+}
+
+type Expect struct {
+	Status int `yaml:"status,omitempty"`
+	// Exact response body
+	Body *string `yaml:"body,omitempty"`
+	// JSONPath -> expected value
+	Json map[string]any    `yaml:"json,omitempty"`
+	Rows []RowsExpectation `yaml:"rows,omitempty"`
+}
+
+// Result of a query against the DB of the API after the step
+type RowsExpectation struct {
+	Query string `yaml:"query"`
+	Args  []any  `yaml:"args,omitempty"`
+	// Number of returned rows
+	Count *int `yaml:"count,omitempty"`
+	// Column -> value of the first returned row
+	First map[string]any `yaml:"first,omitempty"`
+}
+
+func (p Step) Title() string {
+	switch {
+	case p.Name != "":
+		return p.Name
+	case p.Login != "":
+		return "login as " + p.Login
+	}
+	return p.Request
+}
+
+// True if any step checks rows, such scenarios can not run without a DB
+func (p *Scenario) UsesDB() bool {
+	for _, step := range p.Steps {
+		if len(step.Expect.Rows) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Scenario) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("scenario '%s' has no name", p.Path)
+	}
+	for i, step := range p.Steps {
+		if (step.Login == "") == (step.Request == "") {
+			return fmt.Errorf("step %d of '%s' needs either login or request", i+1, p.Name)
+		}
+		if step.Login != "" {
+			if _, ok := p.Users[step.Login]; !ok {
+				return fmt.Errorf("step %d of '%s' logs in as unknown user '%s'", i+1, p.Name, step.Login)
+			}
+			continue
+		}
+		if _, _, err := splitRequest(step.Request); err != nil {
+			return fmt.Errorf("step %d of '%s': %v", i+1, p.Name, err)
+		}
+	}
+	return nil
+}
+
+func splitRequest(request string) (string, string, error) {
+	fields := strings.Fields(request)
+	if len(fields) != 2 || !strings.HasPrefix(fields[1], "/") {
+		return "", "", fmt.Errorf("request '%s' is not like 'GET /path'", request)
+	}
+	return strings.ToUpper(fields[0]), fields[1], nil
+}
+
+func Load(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read scenario '%s': %v", path, err)
+	}
+	var result Scenario
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("unable to parse scenario '%s': %v", path, err)
+	}
+	result.Path = path
+	if err := result.Validate(); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Scenarios of the dir sorted by file name
+func LoadAll(dir string) ([]*Scenario, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, FILE_PATTERN))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	var result []*Scenario
+	for _, path := range paths {
+		s, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+func (p *Scenario) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("unable to create scenario dir for '%s': %v", path, err)
+	}
+	data, err := yaml.Marshal(p)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("unable to write scenario '%s': %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package scenario
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/dbsnapshot"
+)
+
+// ${env:NAME} is read from the environment when the step runs, e.g. a password of .env.test
+const ENV_PREFIX string = "env:"
+
+var (
+	variablePattern = regexp.MustCompile(`\$\{((?:env:)?[A-Za-z0-9_.-]+)\}`)
+	envPattern      = regexp.MustCompile(`\$\{env:[A-Za-z0-9_.-]+\}`)
+)
+
+// Values the strings of a scenario refer to as ${name}. A value may itself refer to the environment,
+// e.g. `adminPassword: ${env:ADMIN_PASSWORD}`, so scenario files carry no secrets.
+type Vars map[string]any
+
+func (p Vars) lookup(name string) (any, error) {
+	if strings.HasPrefix(name, ENV_PREFIX) {
+		value, ok := os.LookupEnv(strings.TrimPrefix(name, ENV_PREFIX))
+		if !ok {
+			return nil, fmt.Errorf("environment variable '%s' is not set", strings.TrimPrefix(name, ENV_PREFIX))
+		}
+		return value, nil
+	}
+	value, ok := p[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown variable '%s'", name)
+	}
+	if text, ok := value.(string); ok && envPattern.MatchString(text) {
+		// Only the environment is looked up, values do not refer to other variables
+		return Vars(nil).Resolve(text)
+	}
+	return value, nil
+}
+
+// Replaces the variables in strings of the value, a string that is only a variable gets its value as is,
+// so that `TagId: ${tag}` stays a number
+func (p Vars) Resolve(value any) (any, error) {
+	switch v := value.(type) {
+	case string:
+		if match := variablePattern.FindStringSubmatch(v); match != nil && match[0] == v {
+			return p.lookup(match[1])
+		}
+		return p.Interpolate(v)
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, item := range v {
+			resolved, err := p.Resolve(item)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = resolved
+		}
+		return result, nil
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			resolved, err := p.Resolve(item)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = resolved
+		}
+		return result, nil
+	}
+	return value, nil
+}
+
+func (p Vars) Interpolate(text string) (string, error) {
+	var err error
+	result := variablePattern.ReplaceAllStringFunc(text, func(match string) string {
+		value, lookupErr := p.lookup(match[2 : len(match)-1])
+		if lookupErr != nil {
+			err = lookupErr
+			return match
+		}
+		return Format(value)
+	})
+	return result, err
+}
+
+// Text of a variable, numbers captured from JSON are float64 and ids must not turn into 1e+06
+func Format(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return dbsnapshot.NULL
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return v
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,22 @@
+package scenario
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveEnv(t *testing.T) {
+	t.Setenv("QA_TEST_PASSWORD", "secret")
+	vars := Vars{"password": "${env:QA_TEST_PASSWORD}", "tagId": float64(1000000), "missing": "${env:QA_TEST_MISSING}"}
+
+	result, err := vars.Resolve(map[string]any{"Password": "${password}", "TagId": "${tagId}", "Url": "/tags/${tagId}?p=${env:QA_TEST_PASSWORD}"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]any{"Password": "secret", "TagId": float64(1000000), "Url": "/tags/1000000?p=secret"}, result)
+
+	_, err = vars.Resolve("${missing}")
+	assert.EqualError(t, err, "environment variable 'QA_TEST_MISSING' is not set")
+
+	_, err = vars.Interpolate("${unknown}")
+	assert.EqualError(t, err, "unknown variable 'unknown'")
+}
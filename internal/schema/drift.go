@@ -0,0 +1,231 @@
+package schema
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode"
+)
+
+const (
+	DRIFT_MISSING_TABLE     string = "MISSING TABLE"
+	DRIFT_MISSING_COLUMN    string = "MISSING COLUMN"
+	DRIFT_TYPE_MISMATCH     string = "TYPE MISMATCH"
+	DRIFT_NULLABLE_MISMATCH string = "NULLABLE MISMATCH"
+	DRIFT_MISSING_UNIQUE    string = "MISSING UNIQUE"
+
+	CONSTRAINT_PRIMARY_KEY string = "PRIMARY KEY"
+	CONSTRAINT_UNIQUE      string = "UNIQUE"
+)
+
+// Postgres data types a Go type can be scanned from
+var compatibleTypes = map[reflect.Kind][]string{
+	reflect.Int:     {"integer", "bigint", "smallint"},
+	reflect.Int64:   {"bigint", "integer", "smallint"},
+	reflect.Int32:   {"integer", "smallint"},
+	reflect.String:  {"character varying", "text", "character", "USER-DEFINED"},
+	reflect.Bool:    {"boolean"},
+	reflect.Float64: {"double precision", "real", "numeric"},
+	reflect.Float32: {"real"},
+}
+
+var timeTypes = []string{"timestamp without time zone", "timestamp with time zone", "date"}
+
+type ExpectedColumn struct {
+	Field    string
+	Column   string
+	Types    []string
+	Nullable bool
+}
+
+type TableExpectation struct {
+	Table   string
+	Entity  string
+	Columns []ExpectedColumn
+	// Column sets that must be unique, e.g. {"email"}
+	Unique [][]string
+}
+
+// Expectation from the fields of the entity struct. Columns are the snake case field names or the `db` tag,
+// pointer and sql.Null* fields may be nullable, all the other fields need NOT NULL columns.
+func FromStruct(table string, entity any, unique ...[]string) TableExpectation {
+	t := reflect.TypeOf(entity)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	result := TableExpectation{Table: table, Entity: t.Name(), Unique: unique}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		column := field.Tag.Get("db")
+		if column == "-" {
+			continue
+		}
+		if column == "" {
+			column = SnakeCase(field.Name)
+		}
+		types, nullable := columnTypes(field.Type)
+		result.Columns = append(result.Columns, ExpectedColumn{Field: field.Name, Column: column, Types: types, Nullable: nullable})
+	}
+	return result
+}
+
+func columnTypes(t reflect.Type) ([]string, bool) {
+	nullable := false
+	if t.Kind() == reflect.Pointer {
+		t, nullable = t.Elem(), true
+	}
+	switch t {
+	case reflect.TypeOf(time.Time{}):
+		return timeTypes, nullable
+	case reflect.TypeOf(sql.NullString{}):
+		return compatibleTypes[reflect.String], true
+	case reflect.TypeOf(sql.NullInt64{}), reflect.TypeOf(sql.NullInt32{}):
+		return compatibleTypes[reflect.Int64], true
+	case reflect.TypeOf(sql.NullBool{}):
+		return compatibleTypes[reflect.Bool], true
+	case reflect.TypeOf(sql.NullFloat64{}):
+		return compatibleTypes[reflect.Float64], true
+	case reflect.TypeOf(sql.NullTime{}):
+		return timeTypes, true
+	}
+	return compatibleTypes[t.Kind()], nullable
+}
+
+// TagId -> tag_id, ExpireAt -> expire_at
+func SnakeCase(name string) string {
+	var sb strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			sb.WriteRune('_')
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
+
+type Drift struct {
+	Table   string
+	Column  string
+	Kind    string
+	Message string
+}
+
+func Detect(snapshot Snapshot, expectations ...TableExpectation) []Drift {
+	var result []Drift
+	for _, expectation := range expectations {
+		table, ok := snapshot.Tables[expectation.Table]
+		if !ok {
+			result = append(result, Drift{Table: expectation.Table, Kind: DRIFT_MISSING_TABLE, Message: fmt.Sprintf("no table for entity %s", expectation.Entity)})
+			continue
+		}
+		for _, expected := range expectation.Columns {
+			result = append(result, detectColumn(expectation, table, expected)...)
+		}
+		for _, columns := range expectation.Unique {
+			if !table.IsUnique(columns) {
+				result = append(result, Drift{
+					Table:   expectation.Table,
+					Column:  strings.Join(columns, ", "),
+					Kind:    DRIFT_MISSING_UNIQUE,
+					Message: "no unique constraint or unique index",
+				})
+			}
+		}
+	}
+	return result
+}
+
+func detectColumn(expectation TableExpectation, table *Table, expected ExpectedColumn) []Drift {
+	actual, ok := table.Columns[expected.Column]
+	if !ok {
+		return []Drift{{Table: expectation.Table, Column: expected.Column, Kind: DRIFT_MISSING_COLUMN, Message: fmt.Sprintf("no column for field %s.%s", expectation.Entity, expected.Field)}}
+	}
+	var result []Drift
+	if len(expected.Types) == 0 {
+		result = append(result, Drift{Table: expectation.Table, Column: expected.Column, Kind: DRIFT_TYPE_MISMATCH, Message: fmt.Sprintf("field %s.%s has a type the detector does not know", expectation.Entity, expected.Field)})
+	} else if !contains(expected.Types, actual.DataType) {
+		result = append(result, Drift{Table: expectation.Table, Column: expected.Column, Kind: DRIFT_TYPE_MISMATCH, Message: fmt.Sprintf("column is %s, field %s.%s expects one of %v", actual.DataType, expectation.Entity, expected.Field, expected.Types)})
+	}
+	if actual.IsNullable && !expected.Nullable {
+		result = append(result, Drift{Table: expectation.Table, Column: expected.Column, Kind: DRIFT_NULLABLE_MISMATCH, Message: fmt.Sprintf("column is nullable, field %s.%s can not hold NULL", expectation.Entity, expected.Field)})
+	}
+	return result
+}
+
+var uniqueIndexColumns = regexp.MustCompile(`^CREATE UNIQUE INDEX .* USING \w+ \(([^)]*)\)`)
+
+// True if a unique or primary key constraint or a unique index covers exactly the columns
+func (p *Table) IsUnique(columns []string) bool {
+	expected := sortedCopy(columns)
+	for _, constraintType := range []string{CONSTRAINT_UNIQUE, CONSTRAINT_PRIMARY_KEY} {
+		for _, actual := range p.ConstraintColumns(constraintType) {
+			if equal(expected, sortedCopy(actual)) {
+				return true
+			}
+		}
+	}
+	for _, index := range p.Indexes {
+		match := uniqueIndexColumns.FindStringSubmatch(index.Definition)
+		if match == nil {
+			continue
+		}
+		var actual []string
+		for _, column := range strings.Split(match[1], ",") {
+			actual = append(actual, strings.Trim(strings.TrimSpace(column), `"`))
+		}
+		if equal(expected, sortedCopy(actual)) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedCopy(values []string) []string {
+	result := append([]string{}, values...)
+	sort.Strings(result)
+	return result
+}
+
+func equal(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func WriteDrifts(w io.Writer, drifts []Drift) error {
+	if len(drifts) == 0 {
+		_, err := fmt.Fprintln(w, "no drift")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TABLE\tCOLUMN\tKIND\tMESSAGE\t")
+	for _, d := range drifts {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t\n", d.Table, d.Column, d.Kind, d.Message)
+	}
+	return tw.Flush()
+}
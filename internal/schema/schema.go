@@ -0,0 +1,258 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const DEFAULT_SCHEMA string = "public"
+
+// Tables of the migration tool itself, they are not a part of the application schema
+var IGNORED_TABLES = []string{"databasechangelog", "databasechangeloglock"}
+
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+type Column struct {
+	Name       string
+	DataType   string
+	IsNullable bool
+	Default    string
+	MaxLength  int
+}
+
+type Constraint struct {
+	Name    string
+	Type    string
+	Columns []string
+}
+
+type Index struct {
+	Name       string
+	Definition string
+}
+
+type Table struct {
+	Name        string
+	Columns     map[string]Column
+	Constraints map[string]Constraint
+	Indexes     map[string]Index
+}
+
+type Snapshot struct {
+	Tables map[string]*Table
+}
+
+func Take(ctx context.Context, queryer Queryer, schemaName string) (Snapshot, error) {
+	result := Snapshot{Tables: make(map[string]*Table)}
+	if err := takeColumns(ctx, queryer, schemaName, result); err != nil {
+		return result, err
+	}
+	if err := takeConstraints(ctx, queryer, schemaName, result); err != nil {
+		return result, err
+	}
+	if err := takeIndexes(ctx, queryer, schemaName, result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (p Snapshot) table(name string) *Table {
+	table, ok := p.Tables[name]
+	if !ok {
+		table = &Table{Name: name, Columns: make(map[string]Column), Constraints: make(map[string]Constraint), Indexes: make(map[string]Index)}
+		p.Tables[name] = table
+	}
+	return table
+}
+
+func isIgnored(table string) bool {
+	for _, ignored := range IGNORED_TABLES {
+		if strings.EqualFold(ignored, table) {
+			return true
+		}
+	}
+	return false
+}
+
+func takeColumns(ctx context.Context, queryer Queryer, schemaName string, snapshot Snapshot) error {
+	rows, err := queryer.QueryContext(ctx, "SELECT table_name, column_name, data_type, is_nullable, COALESCE(column_default, ''), COALESCE(character_maximum_length, 0) "+
+		"FROM information_schema.columns WHERE table_schema = $1", schemaName)
+	if err != nil {
+		return fmt.Errorf("error at reading columns: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var table, nullable string
+		var column Column
+		if err := rows.Scan(&table, &column.Name, &column.DataType, &nullable, &column.Default, &column.MaxLength); err != nil {
+			return fmt.Errorf("error at reading columns: %v", err)
+		}
+		if isIgnored(table) {
+			continue
+		}
+		column.IsNullable = nullable == "YES"
+		snapshot.table(table).Columns[column.Name] = column
+	}
+	return rows.Err()
+}
+
+func takeConstraints(ctx context.Context, queryer Queryer, schemaName string, snapshot Snapshot) error {
+	rows, err := queryer.QueryContext(ctx, "SELECT tc.table_name, tc.constraint_name, tc.constraint_type, COALESCE(kcu.column_name, '') "+
+		"FROM information_schema.table_constraints tc "+
+		"LEFT JOIN information_schema.key_column_usage kcu "+
+		"ON tc.constraint_schema = kcu.constraint_schema AND tc.constraint_name = kcu.constraint_name AND tc.table_name = kcu.table_name "+
+		"WHERE tc.table_schema = $1 AND tc.constraint_type <> 'CHECK' "+
+		"ORDER BY tc.table_name, tc.constraint_name, kcu.ordinal_position", schemaName)
+	if err != nil {
+		return fmt.Errorf("error at reading constraints: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var table, name, constraintType, column string
+		if err := rows.Scan(&table, &name, &constraintType, &column); err != nil {
+			return fmt.Errorf("error at reading constraints: %v", err)
+		}
+		if isIgnored(table) {
+			continue
+		}
+		constraints := snapshot.table(table).Constraints
+		constraint := constraints[name]
+		constraint.Name, constraint.Type = name, constraintType
+		if column != "" {
+			constraint.Columns = append(constraint.Columns, column)
+		}
+		constraints[name] = constraint
+	}
+	return rows.Err()
+}
+
+func takeIndexes(ctx context.Context, queryer Queryer, schemaName string, snapshot Snapshot) error {
+	rows, err := queryer.QueryContext(ctx, "SELECT tablename, indexname, indexdef FROM pg_indexes WHERE schemaname = $1", schemaName)
+	if err != nil {
+		return fmt.Errorf("error at reading indexes: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var table string
+		var index Index
+		if err := rows.Scan(&table, &index.Name, &index.Definition); err != nil {
+			return fmt.Errorf("error at reading indexes: %v", err)
+		}
+		if isIgnored(table) {
+			continue
+		}
+		snapshot.table(table).Indexes[index.Name] = index
+	}
+	return rows.Err()
+}
+
+func (p Snapshot) TableNames() []string {
+	result := make([]string, 0, len(p.Tables))
+	for name := range p.Tables {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Columns of the constraints of the type, e.g. all UNIQUE column sets of the table
+func (p *Table) ConstraintColumns(constraintType string) [][]string {
+	var result [][]string
+	for _, constraint := range p.Constraints {
+		if constraint.Type == constraintType {
+			result = append(result, constraint.Columns)
+		}
+	}
+	return result
+}
+
+func (p Column) String() string {
+	result := p.DataType
+	if p.MaxLength > 0 {
+		result += fmt.Sprintf("(%d)", p.MaxLength)
+	}
+	if !p.IsNullable {
+		result += " NOT NULL"
+	}
+	if p.Default != "" {
+		result += " DEFAULT " + p.Default
+	}
+	return result
+}
+
+func (p Constraint) String() string {
+	return p.Type + " (" + strings.Join(p.Columns, ", ") + ")"
+}
+
+// Readable differences between two snapshots, empty if they are equal
+func Compare(expected Snapshot, actual Snapshot) []string {
+	var result []string
+	for _, name := range union(keys(expected.Tables), keys(actual.Tables)) {
+		e, inExpected := expected.Tables[name]
+		a, inActual := actual.Tables[name]
+		switch {
+		case !inActual:
+			result = append(result, fmt.Sprintf("table '%s' is missing", name))
+			continue
+		case !inExpected:
+			result = append(result, fmt.Sprintf("unexpected table '%s'", name))
+			continue
+		}
+		result = append(result, compareItems(name, "column", e.Columns, a.Columns)...)
+		result = append(result, compareItems(name, "constraint", e.Constraints, a.Constraints)...)
+		result = append(result, compareItems(name, "index", e.Indexes, a.Indexes)...)
+	}
+	return result
+}
+
+func compareItems[T fmt.Stringer](table string, kind string, expected map[string]T, actual map[string]T) []string {
+	var result []string
+	for _, name := range union(keys(expected), keys(actual)) {
+		e, inExpected := expected[name]
+		a, inActual := actual[name]
+		switch {
+		case !inActual:
+			result = append(result, fmt.Sprintf("%s '%s.%s' is missing, expected %s", kind, table, name, e.String()))
+		case !inExpected:
+			result = append(result, fmt.Sprintf("unexpected %s '%s.%s' %s", kind, table, name, a.String()))
+		case e.String() != a.String():
+			result = append(result, fmt.Sprintf("%s '%s.%s' differs: expected %s, actual %s", kind, table, name, e.String(), a.String()))
+		}
+	}
+	return result
+}
+
+func (p Index) String() string {
+	return p.Definition
+}
+
+func keys[T any](m map[string]T) []string {
+	result := make([]string, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	return result
+}
+
+func union(a []string, b []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, values := range [][]string{a, b} {
+		for _, v := range values {
+			if !seen[v] {
+				seen[v] = true
+				result = append(result, v)
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,167 @@
+package seed
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// Samples of counts are not limited
+const NO_LIMIT int = 0
+
+// Distribution of non-negative integers, used for counts (notes per user) and for picking ranks (tag popularity).
+// A sample is in [0, limit) unless limit is NO_LIMIT.
+type Distribution interface {
+	Sample(random *rand.Rand, limit int) int
+	String() string
+	// Counts are sampled with NO_LIMIT, the distribution must be bounded by itself then
+	Validate(counts bool) error
+}
+
+type Fixed struct {
+	Value int
+}
+
+func (p Fixed) Sample(random *rand.Rand, limit int) int {
+	return clamp(p.Value, limit)
+}
+
+func (p Fixed) String() string {
+	return fmt.Sprintf("fixed:%d", p.Value)
+}
+
+func (p Fixed) Validate(counts bool) error {
+	if p.Value < 0 {
+		return fmt.Errorf("wrong distribution '%s', expected N >= 0", p)
+	}
+	return nil
+}
+
+type Uniform struct {
+	Min int
+	Max int
+}
+
+func (p Uniform) Sample(random *rand.Rand, limit int) int {
+	return clamp(p.Min+random.Intn(p.Max-p.Min+1), limit)
+}
+
+func (p Uniform) String() string {
+	return fmt.Sprintf("uniform:%d-%d", p.Min, p.Max)
+}
+
+func (p Uniform) Validate(counts bool) error {
+	if p.Min < 0 || p.Max < p.Min {
+		return fmt.Errorf("wrong distribution '%s', expected 0 <= MIN <= MAX", p)
+	}
+	return nil
+}
+
+// Power law: small values are frequent, large ones are rare.
+// It models a few very active users and a few very popular tags.
+type Zipf struct {
+	S float64
+	// Largest sample, required for counts. Ranks are bounded by the count of tags anyway.
+	Max int
+
+	// Generator of the last random source and bound, building one is expensive
+	zipf   *rand.Zipf
+	random *rand.Rand
+	imax   uint64
+}
+
+func (p *Zipf) Sample(random *rand.Rand, limit int) int {
+	imax := p.Max
+	if limit != NO_LIMIT && (imax <= 0 || imax > limit-1) {
+		imax = limit - 1
+	}
+	if imax <= 0 {
+		return 0
+	}
+	if p.zipf == nil || p.random != random || p.imax != uint64(imax) {
+		p.zipf, p.random, p.imax = rand.NewZipf(random, p.S, 1, uint64(imax)), random, uint64(imax)
+	}
+	return int(p.zipf.Uint64())
+}
+
+func (p *Zipf) String() string {
+	if p.Max == 0 {
+		return fmt.Sprintf("zipf:%g", p.S)
+	}
+	return fmt.Sprintf("zipf:%g:%d", p.S, p.Max)
+}
+
+func (p *Zipf) Validate(counts bool) error {
+	if p.S <= 1 {
+		return fmt.Errorf("wrong distribution '%s', zipf exponent must be greater than 1", p)
+	}
+	if p.Max < 0 {
+		return fmt.Errorf("wrong distribution '%s', expected MAX >= 0", p)
+	}
+	if counts && p.Max == 0 {
+		return fmt.Errorf("wrong distribution '%s', zipf of counts needs MAX, e.g. 'zipf:%g:50'", p, p.S)
+	}
+	return nil
+}
+
+// The result is in [0, limit), or not bounded from above for NO_LIMIT
+func clamp(value int, limit int) int {
+	if value < 0 {
+		return 0
+	}
+	if limit != NO_LIMIT && value > limit-1 {
+		return limit - 1
+	}
+	return value
+}
+
+// Parses "fixed:N", "uniform:MIN-MAX" or "zipf:S[:MAX]", the distribution is validated by Config.Validate
+func ParseDistribution(spec string) (Distribution, error) {
+	parts := strings.Split(spec, ":")
+	switch parts[0] {
+	case "fixed":
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("wrong distribution '%s', expected 'fixed:N'", spec)
+		}
+		value, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("wrong distribution '%s': %v", spec, err)
+		}
+		return Fixed{Value: value}, nil
+	case "uniform":
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("wrong distribution '%s', expected 'uniform:MIN-MAX'", spec)
+		}
+		bounds := strings.SplitN(parts[1], "-", 2)
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf("wrong distribution '%s', expected 'uniform:MIN-MAX'", spec)
+		}
+		min, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("wrong distribution '%s': %v", spec, err)
+		}
+		max, err := strconv.Atoi(bounds[1])
+		if err != nil {
+			return nil, fmt.Errorf("wrong distribution '%s': %v", spec, err)
+		}
+		return Uniform{Min: min, Max: max}, nil
+	case "zipf":
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf("wrong distribution '%s', expected 'zipf:S[:MAX]'", spec)
+		}
+		s, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("wrong distribution '%s': %v", spec, err)
+		}
+		result := &Zipf{S: s}
+		if len(parts) == 3 {
+			result.Max, err = strconv.Atoi(parts[2])
+			if err != nil {
+				return nil, fmt.Errorf("wrong distribution '%s': %v", spec, err)
+			}
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("unknown distribution '%s'. Possible values: fixed:N, uniform:MIN-MAX, zipf:S[:MAX]", spec)
+}
@@ -0,0 +1,146 @@
+package seed
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampleStaysBelowLimit(t *testing.T) {
+	for _, spec := range []string{"fixed:0", "fixed:3", "uniform:0-5", "zipf:1.1", "zipf:2:10"} {
+		distribution, err := ParseDistribution(spec)
+		assert.Nil(t, err)
+		random := rand.New(rand.NewSource(1))
+		for _, limit := range []int{1, 2, 5} {
+			for i := 0; i < 100; i++ {
+				sample := distribution.Sample(random, limit)
+				assert.True(t, sample >= 0 && sample < limit, "%s with limit %d sampled %d", spec, limit, sample)
+			}
+		}
+	}
+}
+
+func TestSampleWithoutLimit(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	assert.Equal(t, 7, Fixed{Value: 7}.Sample(random, NO_LIMIT))
+	assert.Equal(t, 0, Fixed{Value: -1}.Sample(random, NO_LIMIT))
+
+	zipf := &Zipf{S: 1.1, Max: 50}
+	counts := make(map[int]int)
+	for i := 0; i < 1000; i++ {
+		sample := zipf.Sample(random, NO_LIMIT)
+		assert.True(t, sample >= 0 && sample <= 50, "zipf sampled %d", sample)
+		counts[sample]++
+	}
+	// Small counts are the most frequent, but not the only ones
+	assert.Greater(t, counts[0], counts[1])
+	assert.Greater(t, len(counts), 10)
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		NotesPerUser  Distribution
+		TagPopularity Distribution
+		ExpectedError string
+	}{
+		{NotesPerUser: Uniform{Min: 0, Max: 20}, TagPopularity: &Zipf{S: 1.1}},
+		{NotesPerUser: &Zipf{S: 1.1, Max: 50}, TagPopularity: Fixed{Value: 0}},
+		{
+			NotesPerUser: &Zipf{S: 1.1}, TagPopularity: &Zipf{S: 1.1},
+			ExpectedError: "unable to seed notes per user: wrong distribution 'zipf:1.1', zipf of counts needs MAX, e.g. 'zipf:1.1:50'",
+		},
+		{
+			NotesPerUser: Uniform{Min: 5, Max: 1}, TagPopularity: &Zipf{S: 1.1},
+			ExpectedError: "unable to seed notes per user: wrong distribution 'uniform:5-1', expected 0 <= MIN <= MAX",
+		},
+		{
+			NotesPerUser: Fixed{Value: -1}, TagPopularity: &Zipf{S: 1.1},
+			ExpectedError: "unable to seed notes per user: wrong distribution 'fixed:-1', expected N >= 0",
+		},
+		{
+			NotesPerUser: Fixed{Value: 1}, TagPopularity: &Zipf{S: 1},
+			ExpectedError: "unable to seed tag popularity: wrong distribution 'zipf:1', zipf exponent must be greater than 1",
+		},
+		{
+			NotesPerUser: Fixed{Value: 1}, TagPopularity: &Zipf{S: 2, Max: -1},
+			ExpectedError: "unable to seed tag popularity: wrong distribution 'zipf:2:-1', expected MAX >= 0",
+		},
+		{NotesPerUser: Fixed{Value: 1}, ExpectedError: "notes per user and tag popularity distributions are required"},
+	}
+	for _, test := range tests {
+		config := DefaultConfig()
+		config.NotesPerUser, config.TagPopularity = test.NotesPerUser, test.TagPopularity
+
+		err := config.Validate()
+
+		if test.ExpectedError == "" {
+			assert.Nil(t, err)
+		} else {
+			assert.EqualError(t, err, test.ExpectedError)
+		}
+	}
+}
+
+func TestParseDistribution(t *testing.T) {
+	tests := []struct {
+		Spec          string
+		Expected      Distribution
+		ExpectedError string
+	}{
+		{Spec: "fixed:3", Expected: Fixed{Value: 3}},
+		{Spec: "uniform:1-5", Expected: Uniform{Min: 1, Max: 5}},
+		{Spec: "zipf:1.1", Expected: &Zipf{S: 1.1}},
+		{Spec: "zipf:2:10", Expected: &Zipf{S: 2, Max: 10}},
+		{Spec: "fixed", ExpectedError: "wrong distribution 'fixed', expected 'fixed:N'"},
+		{Spec: "uniform:5", ExpectedError: "wrong distribution 'uniform:5', expected 'uniform:MIN-MAX'"},
+		{Spec: "zipf:1:2:3", ExpectedError: "wrong distribution 'zipf:1:2:3', expected 'zipf:S[:MAX]'"},
+		{Spec: "normal:1", ExpectedError: "unknown distribution 'normal:1'. Possible values: fixed:N, uniform:MIN-MAX, zipf:S[:MAX]"},
+	}
+	for _, test := range tests {
+		result, err := ParseDistribution(test.Spec)
+
+		if test.ExpectedError == "" {
+			assert.Nil(t, err, test.Spec)
+			assert.Equal(t, test.Expected, result, test.Spec)
+			assert.Equal(t, test.Spec, result.String())
+		} else {
+			assert.EqualError(t, err, test.ExpectedError)
+		}
+	}
+}
+
+func TestSingleTagNotes(t *testing.T) {
+	config := DefaultConfig()
+	config.NotesPerUser = Fixed{Value: 5}
+	config.TagPopularity = Fixed{Value: 3}
+	g := &generator{config: config, random: rand.New(rand.NewSource(1))}
+
+	notes, err := g.notes([]int{1, 2}, []int{42})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 10, len(notes))
+	for _, note := range notes {
+		assert.Equal(t, 42, note[2])
+	}
+}
+
+// Ranks of a distribution that ignores the limit
+type unbounded struct {
+	Fixed
+}
+
+func (p unbounded) Sample(random *rand.Rand, limit int) int {
+	return p.Value
+}
+
+func TestNotesRankOutOfRange(t *testing.T) {
+	config := DefaultConfig()
+	config.NotesPerUser = Fixed{Value: 1}
+	config.TagPopularity = unbounded{Fixed{Value: 3}}
+	g := &generator{config: config, random: rand.New(rand.NewSource(1))}
+
+	_, err := g.notes([]int{1}, []int{42})
+
+	assert.EqualError(t, err, "tag rank 3 out of [0, 1) from fixed:3")
+}
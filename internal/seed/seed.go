@@ -0,0 +1,243 @@
+package seed
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+const (
+	DEFAULT_STATE         string = "NEW"
+	DEFAULT_DELETED_STATE string = "DELETED"
+	DEFAULT_USER_ROLE     string = "USER"
+	DEFAULT_USER_PASSWORD string = "Seed password1"
+	DEFAULT_PREFIX        string = "seed"
+)
+
+type Config struct {
+	Users int
+	Tags  int
+	Tasks int
+	// How many notes every user has
+	NotesPerUser Distribution
+	// Which tag a note gets, sampled as a rank where 0 is the most popular tag
+	TagPopularity Distribution
+	// Share of rows of every table that are soft deleted
+	DeletedRatio float64
+
+	State        string
+	DeletedState string
+	UserRole     string
+	UserPassword string
+
+	Method    string
+	BatchSize int
+	Seed      int64
+	// Generated names start with the prefix, so several runs can share a DB
+	Prefix string
+	// Refresh the planner statistics after seeding
+	Analyze bool
+}
+
+func DefaultConfig() Config {
+	return Config{
+		NotesPerUser:  Uniform{Min: 0, Max: 20},
+		TagPopularity: &Zipf{S: 1.1},
+		State:         DEFAULT_STATE,
+		DeletedState:  DEFAULT_DELETED_STATE,
+		UserRole:      DEFAULT_USER_ROLE,
+		UserPassword:  DEFAULT_USER_PASSWORD,
+		Method:        METHOD_COPY,
+		Seed:          1,
+		Prefix:        DEFAULT_PREFIX,
+		Analyze:       true,
+	}
+}
+
+type Result struct {
+	UserIds []int
+	TagIds  []int
+	TaskIds []int
+	Notes   int
+	Elapsed time.Duration
+}
+
+func (p Result) String() string {
+	return fmt.Sprintf("seeded %d users, %d tags, %d tasks, %d notes in %v", len(p.UserIds), len(p.TagIds), len(p.TaskIds), p.Notes, p.Elapsed.Round(time.Millisecond))
+}
+
+func (p Config) Validate() error {
+	if p.NotesPerUser == nil || p.TagPopularity == nil {
+		return fmt.Errorf("notes per user and tag popularity distributions are required")
+	}
+	if err := p.NotesPerUser.Validate(true); err != nil {
+		return fmt.Errorf("unable to seed notes per user: %v", err)
+	}
+	if err := p.TagPopularity.Validate(false); err != nil {
+		return fmt.Errorf("unable to seed tag popularity: %v", err)
+	}
+	if p.Users < 0 || p.Tags < 0 || p.Tasks < 0 {
+		return fmt.Errorf("counts of users, tags and tasks must not be negative")
+	}
+	return nil
+}
+
+// Inserts users, tags and tasks, then notes that reference them, within a single transaction.
+// The same config and seed always produce the same data.
+func Run(ctx context.Context, db *sql.DB, config Config) (Result, error) {
+	var result Result
+	if err := config.Validate(); err != nil {
+		return result, err
+	}
+	writer, err := newRowWriter(config.Method, config.BatchSize)
+	if err != nil {
+		return result, err
+	}
+
+	start := time.Now()
+	g := &generator{config: config, random: rand.New(rand.NewSource(config.Seed))}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return result, fmt.Errorf("error at starting seeding transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if result.UserIds, err = g.insert(ctx, tx, writer, "users", []string{"login", "email", "password", "role", "state"}, "login", config.Users, g.user); err != nil {
+		return result, err
+	}
+	if result.TagIds, err = g.insert(ctx, tx, writer, "tags", []string{"name", "state"}, "name", config.Tags, g.tag); err != nil {
+		return result, err
+	}
+	if result.TaskIds, err = g.insert(ctx, tx, writer, "tasks", []string{"name", "state"}, "name", config.Tasks, g.task); err != nil {
+		return result, err
+	}
+
+	notes, err := g.notes(result.UserIds, result.TagIds)
+	if err != nil {
+		return result, err
+	}
+	if len(notes) > 0 {
+		if err := writer.Write(ctx, tx, "notes", []string{"text", "topic", "tag_id", "user_id", "state"}, notes); err != nil {
+			return result, err
+		}
+	}
+	result.Notes = len(notes)
+
+	if err := tx.Commit(); err != nil {
+		return result, fmt.Errorf("error at committing seeding transaction: %v", err)
+	}
+
+	if config.Analyze {
+		for _, table := range []string{"users", "tags", "tasks", "notes"} {
+			if _, err := db.ExecContext(ctx, "ANALYZE "+table); err != nil {
+				return result, fmt.Errorf("error at analyzing '%s': %v", table, err)
+			}
+		}
+	}
+	result.Elapsed = time.Since(start)
+	return result, nil
+}
+
+type generator struct {
+	config Config
+	random *rand.Rand
+}
+
+func (p *generator) name(kind string, i int) string {
+	return p.config.Prefix + " " + kind + " " + strconv.Itoa(i)
+}
+
+func (p *generator) state() string {
+	if p.config.DeletedRatio > 0 && p.random.Float64() < p.config.DeletedRatio {
+		return p.config.DeletedState
+	}
+	return p.config.State
+}
+
+func (p *generator) user(i int) []any {
+	return []any{
+		p.name("user", i),
+		p.config.Prefix + ".user" + strconv.Itoa(i) + "@seed.local",
+		p.config.UserPassword,
+		p.config.UserRole,
+		p.state(),
+	}
+}
+
+func (p *generator) tag(i int) []any {
+	return []any{p.name("tag", i), p.state()}
+}
+
+func (p *generator) task(i int) []any {
+	return []any{p.name("task", i), p.state()}
+}
+
+func (p *generator) notes(userIds []int, tagIds []int) ([][]any, error) {
+	if len(userIds) == 0 || len(tagIds) == 0 {
+		return nil, nil
+	}
+	// popular ranks are spread over the tags randomly, otherwise the first tags would always win
+	ranks := p.random.Perm(len(tagIds))
+
+	var result [][]any
+	for _, userId := range userIds {
+		count := p.config.NotesPerUser.Sample(p.random, NO_LIMIT)
+		for i := 0; i < count; i++ {
+			n := len(result) + 1
+			rank := p.config.TagPopularity.Sample(p.random, len(tagIds))
+			if rank < 0 || rank >= len(tagIds) {
+				return nil, fmt.Errorf("tag rank %d out of [0, %d) from %s", rank, len(tagIds), p.config.TagPopularity)
+			}
+			tagId := tagIds[ranks[rank]]
+			result = append(result, []any{p.name("note text", n), p.name("note topic", n), tagId, userId, p.state()})
+		}
+	}
+	return result, nil
+}
+
+// Writes count generated rows and reads back their ids in insertion order, COPY does not support RETURNING
+func (p *generator) insert(ctx context.Context, tx *sql.Tx, writer rowWriter, table string, columns []string, key string, count int, row func(i int) []any) ([]int, error) {
+	if count == 0 {
+		return nil, nil
+	}
+	rows := make([][]any, 0, count)
+	for i := 1; i <= count; i++ {
+		rows = append(rows, row(i))
+	}
+	if err := writer.Write(ctx, tx, table, columns, rows); err != nil {
+		return nil, err
+	}
+
+	prefix := p.config.Prefix + " "
+	query := "SELECT id FROM " + table + " WHERE left(" + key + ", length($1)) = $1 ORDER BY id"
+	result, err := queryIds(ctx, tx, query, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("error at reading ids of seeded '%s': %v", table, err)
+	}
+	if len(result) != count {
+		return nil, fmt.Errorf("expected %d seeded rows in '%s', found %d. Use a unique prefix for every run", count, table, len(result))
+	}
+	return result, nil
+}
+
+func queryIds(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]int, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		result = append(result, id)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,101 @@
+package seed
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+const (
+	METHOD_COPY   string = "copy"
+	METHOD_INSERT string = "insert"
+
+	// PostgreSQL limits the number of bind parameters of a statement
+	MAX_PARAMS_PER_STATEMENT int = 65535
+)
+
+func GetPossibleMethods() []string {
+	return []string{METHOD_COPY, METHOD_INSERT}
+}
+
+type rowWriter interface {
+	Write(ctx context.Context, tx *sql.Tx, table string, columns []string, rows [][]any) error
+}
+
+func newRowWriter(method string, batchSize int) (rowWriter, error) {
+	switch method {
+	case METHOD_COPY:
+		return copyWriter{}, nil
+	case METHOD_INSERT:
+		return batchInsertWriter{batchSize: batchSize}, nil
+	}
+	return nil, fmt.Errorf("unknown seeding method '%s'. Possible values: %v", method, GetPossibleMethods())
+}
+
+type copyWriter struct{}
+
+func (p copyWriter) Write(ctx context.Context, tx *sql.Tx, table string, columns []string, rows [][]any) error {
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(table, columns...))
+	if err != nil {
+		return fmt.Errorf("error at preparing COPY into '%s': %v", table, err)
+	}
+	defer stmt.Close()
+
+	for _, row := range rows {
+		if _, err := stmt.ExecContext(ctx, row...); err != nil {
+			return fmt.Errorf("error at copying row into '%s': %v", table, err)
+		}
+	}
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return fmt.Errorf("error at flushing COPY into '%s': %v", table, err)
+	}
+	return nil
+}
+
+type batchInsertWriter struct {
+	batchSize int
+}
+
+func (p batchInsertWriter) Write(ctx context.Context, tx *sql.Tx, table string, columns []string, rows [][]any) error {
+	batchSize := p.batchSize
+	if maxRows := MAX_PARAMS_PER_STATEMENT / len(columns); batchSize <= 0 || batchSize > maxRows {
+		batchSize = maxRows
+	}
+
+	for start := 0; start < len(rows); start += batchSize {
+		end := start + batchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		query, args := multiRowInsert(table, columns, rows[start:end])
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("error at inserting batch of %d rows into '%s': %v", end-start, table, err)
+		}
+	}
+	return nil
+}
+
+func multiRowInsert(table string, columns []string, rows [][]any) (string, []any) {
+	var sb strings.Builder
+	args := make([]any, 0, len(rows)*len(columns))
+	sb.WriteString("INSERT INTO " + table + "(" + strings.Join(columns, ", ") + ") VALUES ")
+	for i, row := range rows {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("(")
+		for j, value := range row {
+			if j > 0 {
+				sb.WriteString(", ")
+			}
+			args = append(args, value)
+			sb.WriteString("$" + strconv.Itoa(len(args)))
+		}
+		sb.WriteString(")")
+	}
+	return sb.String(), args
+}
@@ -0,0 +1,205 @@
+package sqltrace
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	KIND_QUERY    string = "query"
+	KIND_EXEC     string = "exec"
+	KIND_BEGIN    string = "begin"
+	KIND_COMMIT   string = "commit"
+	KIND_ROLLBACK string = "rollback"
+)
+
+type Statement struct {
+	Kind     string
+	Query    string
+	Args     []any
+	Duration time.Duration
+	Err      error
+}
+
+type Statements []Statement
+
+// Collects statements executed through the tracing driver with the context returned by Start.
+// Captures may be nested, a statement is recorded by the capture of its context and by all its parents.
+type Capture struct {
+	mutex      sync.Mutex
+	statements Statements
+	stopped    bool
+	parent     *Capture
+}
+
+type captureKey struct{}
+
+type untracedKey struct{}
+
+var (
+	disabled int32
+
+	// Captures of the statements executed with contexts that carry no capture, see CollectShared
+	sharedCount int32
+	sharedMutex sync.Mutex
+	shared      = make(map[*Capture]struct{})
+)
+
+func Start(ctx context.Context) (context.Context, *Capture) {
+	capture := &Capture{}
+	capture.parent, _ = ctx.Value(captureKey{}).(*Capture)
+	return context.WithValue(ctx, captureKey{}, capture), capture
+}
+
+func (c *Capture) Stop() Statements {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.stopped = true
+	return c.statements
+}
+
+func (c *Capture) add(statement Statement) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if !c.stopped {
+		c.statements = append(c.statements, statement)
+	}
+}
+
+func Collect(ctx context.Context, f func(ctx context.Context)) Statements {
+	ctx, capture := Start(ctx)
+	f(ctx)
+	return capture.Stop()
+}
+
+// Collects the statements executed with contexts that carry no capture, e.g. the ones of an API that opens
+// its transactions with context.Background(). Such statements can't be told apart,
+// so it is only for tests that do nothing else in the meantime.
+func CollectShared(f func()) Statements {
+	capture := &Capture{}
+	sharedMutex.Lock()
+	shared[capture] = struct{}{}
+	atomic.AddInt32(&sharedCount, 1)
+	sharedMutex.Unlock()
+
+	f()
+
+	sharedMutex.Lock()
+	delete(shared, capture)
+	atomic.AddInt32(&sharedCount, -1)
+	sharedMutex.Unlock()
+	return capture.Stop()
+}
+
+// Turns recording off for the whole process, e.g. while measuring latencies, and returns a function that turns it back on
+func Disable() func() {
+	atomic.AddInt32(&disabled, 1)
+	return func() {
+		atomic.AddInt32(&disabled, -1)
+	}
+}
+
+// Statements executed with the returned context are not recorded,
+// so helpers that observe the DB do not show up in the captures of the code under test
+func Untraced(ctx context.Context) context.Context {
+	return context.WithValue(ctx, untracedKey{}, true)
+}
+
+func isUntraced(ctx context.Context) bool {
+	untraced, _ := ctx.Value(untracedKey{}).(bool)
+	return untraced
+}
+
+func record(ctx context.Context, kind string, query string, args []driver.NamedValue, start time.Time, err error) {
+	if atomic.LoadInt32(&disabled) > 0 || isUntraced(ctx) {
+		return
+	}
+	capture, _ := ctx.Value(captureKey{}).(*Capture)
+	if capture == nil && atomic.LoadInt32(&sharedCount) == 0 {
+		return
+	}
+
+	statement := Statement{Kind: kind, Query: query, Duration: time.Since(start), Err: err}
+	for _, arg := range args {
+		statement.Args = append(statement.Args, arg.Value)
+	}
+
+	if capture == nil {
+		sharedMutex.Lock()
+		defer sharedMutex.Unlock()
+		for capture := range shared {
+			capture.add(statement)
+		}
+		return
+	}
+	for ; capture != nil; capture = capture.parent {
+		capture.add(statement)
+	}
+}
+
+// Statements that touch data, transaction control statements are skipped
+func (p Statements) Queries() Statements {
+	var result Statements
+	for _, s := range p {
+		if s.Kind == KIND_QUERY || s.Kind == KIND_EXEC {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+var whitespaces = regexp.MustCompile(`\s+`)
+
+func Normalize(query string) string {
+	return strings.TrimSpace(whitespaces.ReplaceAllString(query, " "))
+}
+
+type Repetition struct {
+	Query string
+	Count int
+}
+
+// Statements executed more than once within the capture, the usual sign of an N+1 pattern
+func (p Statements) Repeated() []Repetition {
+	counts := make(map[string]int)
+	for _, s := range p.Queries() {
+		counts[Normalize(s.Query)]++
+	}
+	var result []Repetition
+	for query, count := range counts {
+		if count > 1 {
+			result = append(result, Repetition{Query: query, Count: count})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Query < result[j].Query
+	})
+	return result
+}
+
+func (p Statements) Report() string {
+	var sb strings.Builder
+	queries := p.Queries()
+	fmt.Fprintf(&sb, "%d statements executed:\n", len(queries))
+	for i, s := range queries {
+		fmt.Fprintf(&sb, "  %d. [%s %v] %s %v", i+1, s.Kind, s.Duration.Round(time.Microsecond), Normalize(s.Query), s.Args)
+		if s.Err != nil {
+			fmt.Fprintf(&sb, " error: %v", s.Err)
+		}
+		sb.WriteString("\n")
+	}
+	for _, r := range p.Repeated() {
+		fmt.Fprintf(&sb, "likely N+1: statement executed %d times: %s\n", r.Count, r.Query)
+	}
+	return sb.String()
+}
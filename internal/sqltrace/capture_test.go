@@ -0,0 +1,137 @@
+package sqltrace
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	return fakeConn{}, nil
+}
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func (fakeConn) Close() error {
+	return nil
+}
+
+func (fakeConn) Begin() (driver.Tx, error) {
+	return fakeTx{}, nil
+}
+
+func (fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error {
+	return nil
+}
+
+func (fakeTx) Rollback() error {
+	return nil
+}
+
+func openFake(t *testing.T) *sql.DB {
+	Register("sqltrace-fake", fakeDriver{})
+	db, err := sql.Open("sqltrace-fake", "")
+	assert.Nil(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func queries(statements Statements) []string {
+	var result []string
+	for _, s := range statements {
+		result = append(result, s.Kind+" "+s.Query)
+	}
+	return result
+}
+
+func TestCollectIsScopedByContext(t *testing.T) {
+	db := openFake(t)
+
+	var wg sync.WaitGroup
+	results := make([]Statements, 4)
+	for i := range results {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = Collect(context.Background(), func(ctx context.Context) {
+				for j := 0; j < 50; j++ {
+					db.ExecContext(ctx, fmt.Sprintf("SELECT %d", i))
+				}
+			})
+		}()
+	}
+	wg.Wait()
+
+	for i, statements := range results {
+		assert.Equal(t, 50, len(statements))
+		for _, s := range statements {
+			assert.Equal(t, fmt.Sprintf("SELECT %d", i), s.Query)
+		}
+	}
+}
+
+func TestCollectNested(t *testing.T) {
+	db := openFake(t)
+
+	var inner Statements
+	outer := Collect(context.Background(), func(ctx context.Context) {
+		db.ExecContext(ctx, "SELECT 1")
+		inner = Collect(ctx, func(ctx context.Context) {
+			tx, err := db.BeginTx(ctx, nil)
+			assert.Nil(t, err)
+			tx.ExecContext(ctx, "SELECT 2")
+			assert.Nil(t, tx.Commit())
+		})
+		db.ExecContext(Untraced(ctx), "SELECT 3")
+	})
+
+	assert.Equal(t, []string{"begin BEGIN", "exec SELECT 2", "commit COMMIT"}, queries(inner))
+	assert.Equal(t, []string{"exec SELECT 1", "begin BEGIN", "exec SELECT 2", "commit COMMIT"}, queries(outer))
+}
+
+func TestCollectShared(t *testing.T) {
+	db := openFake(t)
+
+	var scoped Statements
+	shared := CollectShared(func() {
+		db.ExecContext(context.Background(), "SELECT 1")
+		scoped = Collect(context.Background(), func(ctx context.Context) {
+			db.ExecContext(ctx, "SELECT 2")
+		})
+	})
+	db.ExecContext(context.Background(), "SELECT 3")
+
+	assert.Equal(t, []string{"exec SELECT 1"}, queries(shared))
+	assert.Equal(t, []string{"exec SELECT 2"}, queries(scoped))
+}
+
+func TestDisable(t *testing.T) {
+	db := openFake(t)
+
+	statements := Collect(context.Background(), func(ctx context.Context) {
+		enable := Disable()
+		db.ExecContext(ctx, "SELECT 1")
+		enable()
+		db.ExecContext(ctx, "SELECT 2")
+	})
+
+	assert.Equal(t, []string{"exec SELECT 2"}, queries(statements))
+}
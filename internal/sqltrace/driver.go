@@ -0,0 +1,245 @@
+package sqltrace
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"time"
+)
+
+// Registers the tracing wrapper of the given driver. It's a no-op if the name is already registered.
+func Register(name string, d driver.Driver) {
+	for _, registered := range sql.Drivers() {
+		if registered == name {
+			return
+		}
+	}
+	sql.Register(name, Wrap(d))
+}
+
+func Wrap(d driver.Driver) driver.Driver {
+	return &tracingDriver{parent: d}
+}
+
+type tracingDriver struct {
+	parent driver.Driver
+}
+
+func (d *tracingDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.parent.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &tracingConn{parent: conn}, nil
+}
+
+type tracingConn struct {
+	parent driver.Conn
+}
+
+func (c *tracingConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *tracingConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var stmt driver.Stmt
+	var err error
+	if preparer, ok := c.parent.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.parent.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tracingStmt{parent: stmt, query: query}, nil
+}
+
+func (c *tracingConn) Close() error {
+	return c.parent.Close()
+}
+
+func (c *tracingConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *tracingConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	start := time.Now()
+	var tx driver.Tx
+	var err error
+	if beginner, ok := c.parent.(driver.ConnBeginTx); ok {
+		tx, err = beginner.BeginTx(ctx, opts)
+	} else {
+		tx, err = c.parent.Begin()
+	}
+	record(ctx, KIND_BEGIN, "BEGIN", nil, start, err)
+	if err != nil {
+		return nil, err
+	}
+	return &tracingTx{parent: tx, ctx: ctx}, nil
+}
+
+func (c *tracingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.parent.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	result, err := execer.ExecContext(ctx, query, args)
+	if err != driver.ErrSkip {
+		record(ctx, KIND_EXEC, query, args, start, err)
+	}
+	return result, err
+}
+
+func (c *tracingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.parent.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	rows, err := queryer.QueryContext(ctx, query, args)
+	if err != driver.ErrSkip {
+		record(ctx, KIND_QUERY, query, args, start, err)
+	}
+	return rows, err
+}
+
+func (c *tracingConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.parent.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *tracingConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.parent.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *tracingConn) IsValid() bool {
+	if validator, ok := c.parent.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+func (c *tracingConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.parent.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+type tracingTx struct {
+	parent driver.Tx
+	// Context of BeginTx, commit and rollback go to its captures
+	ctx context.Context
+}
+
+func (t *tracingTx) Commit() error {
+	start := time.Now()
+	err := t.parent.Commit()
+	record(t.ctx, KIND_COMMIT, "COMMIT", nil, start, err)
+	return err
+}
+
+func (t *tracingTx) Rollback() error {
+	start := time.Now()
+	err := t.parent.Rollback()
+	record(t.ctx, KIND_ROLLBACK, "ROLLBACK", nil, start, err)
+	return err
+}
+
+type tracingStmt struct {
+	parent driver.Stmt
+	query  string
+}
+
+func (s *tracingStmt) Close() error {
+	return s.parent.Close()
+}
+
+func (s *tracingStmt) NumInput() int {
+	return s.parent.NumInput()
+}
+
+func (s *tracingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.execWith(context.Background(), args)
+}
+
+func (s *tracingStmt) execWith(ctx context.Context, args []driver.Value) (driver.Result, error) {
+	start := time.Now()
+	result, err := s.parent.Exec(args)
+	record(ctx, KIND_EXEC, s.query, toNamedValues(args), start, err)
+	return result, err
+}
+
+func (s *tracingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.queryWith(context.Background(), args)
+}
+
+func (s *tracingStmt) queryWith(ctx context.Context, args []driver.Value) (driver.Rows, error) {
+	start := time.Now()
+	rows, err := s.parent.Query(args)
+	record(ctx, KIND_QUERY, s.query, toNamedValues(args), start, err)
+	return rows, err
+}
+
+func (s *tracingStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := s.parent.(driver.StmtExecContext)
+	if !ok {
+		values, err := toValues(args)
+		if err != nil {
+			return nil, err
+		}
+		return s.execWith(ctx, values)
+	}
+	start := time.Now()
+	result, err := execer.ExecContext(ctx, args)
+	record(ctx, KIND_EXEC, s.query, args, start, err)
+	return result, err
+}
+
+func (s *tracingStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := s.parent.(driver.StmtQueryContext)
+	if !ok {
+		values, err := toValues(args)
+		if err != nil {
+			return nil, err
+		}
+		return s.queryWith(ctx, values)
+	}
+	start := time.Now()
+	rows, err := queryer.QueryContext(ctx, args)
+	record(ctx, KIND_QUERY, s.query, args, start, err)
+	return rows, err
+}
+
+func (s *tracingStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := s.parent.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func toNamedValues(args []driver.Value) []driver.NamedValue {
+	result := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		result[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return result
+}
+
+func toValues(args []driver.NamedValue) ([]driver.Value, error) {
+	result := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, driver.ErrSkip
+		}
+		result[i] = arg.Value
+	}
+	return result, nil
+}
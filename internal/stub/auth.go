@@ -0,0 +1,141 @@
+package stub
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	TOKEN_TYPE_ACCESS  string = "access"
+	TOKEN_TYPE_REFRESH string = "refresh"
+)
+
+var (
+	errTokenIsExpired = errors.New("token is expired")
+	errTokenIsInvalid = errors.New("token is invalid")
+)
+
+type claims struct {
+	UserId int    `json:"id"`
+	Type   string `json:"type"`
+	Exp    int64  `json:"exp"`
+	// Makes tokens issued in the same second differ
+	Nonce string `json:"jti"`
+}
+
+type refreshToken struct {
+	Token    string
+	ExpireAt time.Time
+}
+
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// HS256 JWT, the stub does not need a library for the only algorithm it issues
+func (p *Server) sign(c claims) string {
+	payload, _ := json.Marshal(c)
+	unsigned := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, p.config.Secret)
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (p *Server) verify(token string, tokenType string) (claims, error) {
+	var result claims
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return result, errTokenIsInvalid
+	}
+	mac := hmac.New(sha256.New, p.config.Secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, mac.Sum(nil)) {
+		return result, errTokenIsInvalid
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || json.Unmarshal(payload, &result) != nil || result.Type != tokenType {
+		return result, errTokenIsInvalid
+	}
+	if time.Now().Unix() >= result.Exp {
+		return result, errTokenIsExpired
+	}
+	return result, nil
+}
+
+func nonce() string {
+	buffer := make([]byte, 8)
+	rand.Read(buffer)
+	return hex.EncodeToString(buffer)
+}
+
+// Issues a new pair, the previous refresh token of the user stops working
+func (p *Server) issueTokens(userId int) AuthenicationResultDTO {
+	now := time.Now()
+	result := AuthenicationResultDTO{
+		AccessTokenExpiredAt:  now.Add(p.config.AccessTokenTTL),
+		RefreshTokenExpiredAt: now.Add(p.config.RefreshTokenTTL),
+	}
+	result.AccessToken = p.sign(claims{UserId: userId, Type: TOKEN_TYPE_ACCESS, Exp: result.AccessTokenExpiredAt.Unix(), Nonce: nonce()})
+	result.RefreshToken = p.sign(claims{UserId: userId, Type: TOKEN_TYPE_REFRESH, Exp: result.RefreshTokenExpiredAt.Unix(), Nonce: nonce()})
+	p.refreshTokens[userId] = refreshToken{Token: result.RefreshToken, ExpireAt: result.RefreshTokenExpiredAt}
+	return result
+}
+
+func (p *Server) authenicate(c *gin.Context) {
+	var dto AuthenicationDTO
+	if !p.bind(c, &dto) {
+		return
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for id := 1; id <= p.users.lastId; id++ {
+		user, ok := p.users.find(id)
+		if ok && user.Email == dto.Email && user.Password == dto.Password {
+			c.JSON(http.StatusOK, p.issueTokens(id))
+			return
+		}
+	}
+	c.JSON(http.StatusBadRequest, p.config.Messages.WrongPasswordOrEmail)
+}
+
+func (p *Server) refreshToken(c *gin.Context) {
+	var dto RefreshTokenDTO
+	if !p.bind(c, &dto) {
+		return
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	tokenClaims, err := p.verify(dto.RefreshToken, TOKEN_TYPE_REFRESH)
+	stored, ok := p.refreshTokens[tokenClaims.UserId]
+	switch {
+	case err == errTokenIsExpired && ok && stored.Token == dto.RefreshToken:
+		delete(p.refreshTokens, tokenClaims.UserId)
+		c.JSON(http.StatusBadRequest, p.config.Messages.TokenIsExpired)
+	case err != nil || !ok || stored.Token != dto.RefreshToken:
+		c.JSON(http.StatusBadRequest, p.config.Messages.TokenIsInvalid)
+	default:
+		c.JSON(http.StatusOK, p.issueTokens(tokenClaims.UserId))
+	}
+}
+
+func (p *Server) authRequired(c *gin.Context) {
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	_, err := p.verify(token, TOKEN_TYPE_ACCESS)
+	switch err {
+	case nil:
+		c.Next()
+	case errTokenIsExpired:
+		c.AbortWithStatusJSON(http.StatusUnauthorized, p.config.Messages.TokenIsExpired)
+	default:
+		c.AbortWithStatusJSON(http.StatusUnauthorized, p.config.Messages.TokenIsInvalid)
+	}
+}
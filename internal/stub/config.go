@@ -0,0 +1,89 @@
+package stub
+
+import "time"
+
+// Response texts of the API. The defaults mirror indefinite-studies-api,
+// the integration suite passes the constants of the API itself when it runs against the stub.
+type Messages struct {
+	// Body of unknown routes, sent without quotes like the gin default
+	PageNotFound           string
+	IdWrongFormat          string
+	ParsingBodyJson        string
+	QueryParamWrongFormat  string
+	Done                   string
+	DuplicateFound         string
+	DeleteViaPostForbidden string
+	DeleteViaPutForbidden  string
+	WrongPasswordOrEmail   string
+	TokenIsExpired         string
+	TokenIsInvalid         string
+	FieldIsRequired        string
+	WrongEmailFormat       string
+	Pong                   string
+}
+
+func DefaultMessages() Messages {
+	return Messages{
+		PageNotFound:           "404 page not found",
+		IdWrongFormat:          "Wrong ID format",
+		ParsingBodyJson:        "Error during parsing of HTTP request body. Please check it format correctness: missed brackets, double quotes, commas, matching of names and data types and etc",
+		QueryParamWrongFormat:  "Wrong query parameter format",
+		Done:                   "Done",
+		DuplicateFound:         "Duplicate found",
+		DeleteViaPostForbidden: "Delete via POST request is forbidden",
+		DeleteViaPutForbidden:  "Delete via PUT request is forbidden",
+		WrongPasswordOrEmail:   "Wrong password or email",
+		TokenIsExpired:         "Token is expired",
+		TokenIsInvalid:         "Token is invalid",
+		FieldIsRequired:        "This field is required",
+		WrongEmailFormat:       "Wrong email format",
+		Pong:                   "Pong!",
+	}
+}
+
+// Possible values of the enum columns, the deleted state of each entity is one of its states
+type Enums struct {
+	TaskStates       []string
+	TagStates        []string
+	UserStates       []string
+	NoteStates       []string
+	UserRoles        []string
+	TaskDeletedState string
+	TagDeletedState  string
+	UserDeletedState string
+	NoteDeletedState string
+}
+
+func DefaultEnums() Enums {
+	return Enums{
+		TaskStates:       []string{"NEW", "DONE", "DELETED"},
+		TagStates:        []string{"NEW", "BLOCKED", "DELETED"},
+		UserStates:       []string{"NEW", "BLOCKED", "DELETED"},
+		NoteStates:       []string{"NEW", "BLOCKED", "DELETED"},
+		UserRoles:        []string{"OWNER", "RESIDENT", "GIFTED", "MODERATOR"},
+		TaskDeletedState: "DELETED",
+		TagDeletedState:  "DELETED",
+		UserDeletedState: "DELETED",
+		NoteDeletedState: "DELETED",
+	}
+}
+
+type Config struct {
+	Messages        Messages
+	Enums           Enums
+	DefaultLimit    int
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// Key of the token signatures, a random one is generated if empty
+	Secret []byte `json:"-"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Messages:        DefaultMessages(),
+		Enums:           DefaultEnums(),
+		DefaultLimit:    50,
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: 24 * time.Hour,
+	}
+}
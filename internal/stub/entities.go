@@ -0,0 +1,123 @@
+package stub
+
+import "time"
+
+// Field order of the structs is the field order of the API responses and of its validation errors
+
+type Task struct {
+	Id    int
+	Name  string
+	State string
+}
+
+type TaskDTO struct {
+	Name  string `json:"Name" binding:"required"`
+	State string `json:"State" binding:"required"`
+}
+
+func (p Task) rowState() string { return p.State }
+func (p Task) withState(state string) Task {
+	p.State = state
+	return p
+}
+func (p Task) uniqueKey() string { return p.Name }
+
+func (p TaskDTO) row(id int) Task { return Task{Id: id, Name: p.Name, State: p.State} }
+func (p TaskDTO) state() string   { return p.State }
+
+type Tag struct {
+	Id    int
+	Name  string
+	State string
+}
+
+type TagDTO struct {
+	Name  string `json:"Name" binding:"required"`
+	State string `json:"State" binding:"required"`
+}
+
+func (p Tag) rowState() string { return p.State }
+func (p Tag) withState(state string) Tag {
+	p.State = state
+	return p
+}
+func (p Tag) uniqueKey() string { return p.Name }
+
+func (p TagDTO) row(id int) Tag { return Tag{Id: id, Name: p.Name, State: p.State} }
+func (p TagDTO) state() string  { return p.State }
+
+type User struct {
+	Id       int
+	Login    string
+	Email    string
+	Password string `json:"-"`
+	Role     string
+	State    string
+}
+
+type UserDTO struct {
+	Login    string `json:"Login" binding:"required"`
+	Email    string `json:"Email" binding:"required,email"`
+	Password string `json:"Password" binding:"required"`
+	Role     string `json:"Role" binding:"required"`
+	State    string `json:"State" binding:"required"`
+}
+
+func (p User) rowState() string { return p.State }
+func (p User) withState(state string) User {
+	p.State = state
+	return p
+}
+func (p User) uniqueKey() string { return p.Email }
+
+func (p UserDTO) row(id int) User {
+	return User{Id: id, Login: p.Login, Email: p.Email, Password: p.Password, Role: p.Role, State: p.State}
+}
+func (p UserDTO) state() string { return p.State }
+func (p UserDTO) role() string  { return p.Role }
+
+// Like the API the stub does not check that the tag and the user exist
+type Note struct {
+	Id     int
+	Text   string
+	Topic  string
+	TagId  int
+	UserId int
+	State  string
+}
+
+type NoteDTO struct {
+	Text   string `json:"Text" binding:"required"`
+	Topic  string `json:"Topic" binding:"required"`
+	TagId  int    `json:"TagId" binding:"required"`
+	UserId int    `json:"UserId" binding:"required"`
+	State  string `json:"State" binding:"required"`
+}
+
+func (p Note) rowState() string { return p.State }
+func (p Note) withState(state string) Note {
+	p.State = state
+	return p
+}
+func (p Note) uniqueKey() string { return "" }
+
+func (p NoteDTO) row(id int) Note {
+	return Note{Id: id, Text: p.Text, Topic: p.Topic, TagId: p.TagId, UserId: p.UserId, State: p.State}
+}
+func (p NoteDTO) state() string { return p.State }
+
+type AuthenicationDTO struct {
+	Email    string `json:"Email" binding:"required,email"`
+	Password string `json:"Password" binding:"required"`
+}
+
+type RefreshTokenDTO struct {
+	RefreshToken string `json:"RefreshToken" binding:"required"`
+}
+
+type AuthenicationResultDTO struct {
+	AccessToken           string
+	RefreshToken          string
+	AccessTokenExpiredAt  time.Time
+	RefreshTokenExpiredAt time.Time
+}
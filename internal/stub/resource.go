@@ -0,0 +1,250 @@
+package stub
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type row[T any] interface {
+	rowState() string
+	withState(state string) T
+	// Value of the unique column, empty if the table has none
+	uniqueKey() string
+}
+
+type rowDTO[T any] interface {
+	row(id int) T
+	state() string
+}
+
+type page[T any] struct {
+	Count  int
+	Offset int
+	Limit  int
+	Data   []T
+}
+
+// In-memory table with the handlers of one REST resource. Deleted rows are kept with the deleted state,
+// and ids are taken before the duplicate check, so a failed insert consumes an id like a DB sequence does.
+type resource[T row[T], D rowDTO[T]] struct {
+	server  *Server
+	name    string
+	states  []string
+	deleted string
+	rows    map[int]T
+	lastId  int
+}
+
+func newResource[T row[T], D rowDTO[T]](server *Server, name string, states []string, deleted string) *resource[T, D] {
+	return &resource[T, D]{server: server, name: name, states: states, deleted: deleted, rows: make(map[int]T)}
+}
+
+func (p *resource[T, D]) reset() {
+	p.rows = make(map[int]T)
+	p.lastId = 0
+}
+
+func (p *resource[T, D]) register(r *gin.Engine, path string) {
+	r.GET(path, p.list)
+	r.GET(path+"/:id", p.get)
+	r.POST(path, p.create)
+	r.PUT(path+"/:id", p.update)
+	r.DELETE(path+"/:id", p.delete)
+}
+
+func (p *resource[T, D]) find(id int) (T, bool) {
+	result, ok := p.rows[id]
+	if !ok || result.rowState() == p.deleted {
+		return result, false
+	}
+	return result, true
+}
+
+func (p *resource[T, D]) isDuplicate(id int, value T) bool {
+	key := value.uniqueKey()
+	if key == "" {
+		return false
+	}
+	for otherId, other := range p.rows {
+		if otherId != id && other.uniqueKey() == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *resource[T, D]) list(c *gin.Context) {
+	limit, okLimit := parseQueryParam(c, "limit", p.server.config.DefaultLimit)
+	offset, okOffset := parseQueryParam(c, "offset", 0)
+	if !okLimit || !okOffset || limit < 0 || offset < 0 {
+		c.JSON(http.StatusBadRequest, p.server.config.Messages.QueryParamWrongFormat)
+		return
+	}
+
+	p.server.mutex.Lock()
+	defer p.server.mutex.Unlock()
+	result := page[T]{Offset: offset, Limit: limit, Data: []T{}}
+	skipped := 0
+	for id := 1; id <= p.lastId && len(result.Data) < limit; id++ {
+		value, ok := p.find(id)
+		if !ok {
+			continue
+		}
+		if skipped < offset {
+			skipped++
+			continue
+		}
+		result.Data = append(result.Data, value)
+	}
+	result.Count = len(result.Data)
+	c.JSON(http.StatusOK, result)
+}
+
+func (p *resource[T, D]) get(c *gin.Context) {
+	id, ok := p.server.parseId(c)
+	if !ok {
+		return
+	}
+	p.server.mutex.Lock()
+	defer p.server.mutex.Unlock()
+	value, ok := p.find(id)
+	if !ok {
+		c.JSON(http.StatusNotFound, p.server.config.Messages.PageNotFound)
+		return
+	}
+	c.JSON(http.StatusOK, value)
+}
+
+func (p *resource[T, D]) create(c *gin.Context) {
+	var dto D
+	if !p.server.bind(c, &dto) || !p.checkEnums(c, "create", dto, p.server.config.Messages.DeleteViaPostForbidden) {
+		return
+	}
+	p.server.mutex.Lock()
+	defer p.server.mutex.Unlock()
+	p.lastId++
+	value := dto.row(p.lastId)
+	if p.isDuplicate(p.lastId, value) {
+		c.JSON(http.StatusBadRequest, p.server.config.Messages.DuplicateFound)
+		return
+	}
+	p.rows[p.lastId] = value
+	c.JSON(http.StatusCreated, p.lastId)
+}
+
+func (p *resource[T, D]) update(c *gin.Context) {
+	id, ok := p.server.parseId(c)
+	if !ok {
+		return
+	}
+	var dto D
+	if !p.server.bind(c, &dto) || !p.checkEnums(c, "update", dto, p.server.config.Messages.DeleteViaPutForbidden) {
+		return
+	}
+	p.server.mutex.Lock()
+	defer p.server.mutex.Unlock()
+	if _, ok := p.find(id); !ok {
+		c.JSON(http.StatusNotFound, p.server.config.Messages.PageNotFound)
+		return
+	}
+	value := dto.row(id)
+	if p.isDuplicate(id, value) {
+		c.JSON(http.StatusBadRequest, p.server.config.Messages.DuplicateFound)
+		return
+	}
+	p.rows[id] = value
+	c.JSON(http.StatusOK, p.server.config.Messages.Done)
+}
+
+func (p *resource[T, D]) delete(c *gin.Context) {
+	id, ok := p.server.parseId(c)
+	if !ok {
+		return
+	}
+	p.server.mutex.Lock()
+	defer p.server.mutex.Unlock()
+	value, ok := p.find(id)
+	if !ok {
+		c.JSON(http.StatusNotFound, p.server.config.Messages.PageNotFound)
+		return
+	}
+	p.rows[id] = value.withState(p.deleted)
+	c.JSON(http.StatusOK, p.server.config.Messages.Done)
+}
+
+// Deleting through POST or PUT is forbidden, the other states must be from the enums
+func (p *resource[T, D]) checkEnums(c *gin.Context, action string, dto D, forbidden string) bool {
+	if dto.state() == p.deleted {
+		c.JSON(http.StatusBadRequest, forbidden)
+		return false
+	}
+	if withRole, ok := any(dto).(interface{ role() string }); ok && !contains(p.server.config.Enums.UserRoles, withRole.role()) {
+		c.JSON(http.StatusBadRequest, fmt.Sprintf("Unable to %s %s. Wrong 'Role' value. Possible values: %v", action, p.name, p.server.config.Enums.UserRoles))
+		return false
+	}
+	if !contains(p.states, dto.state()) {
+		c.JSON(http.StatusBadRequest, fmt.Sprintf("Unable to %s %s. Wrong 'State' value. Possible values: %v", action, p.name, p.states))
+		return false
+	}
+	return true
+}
+
+func (p *Server) parseId(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, p.config.Messages.IdWrongFormat)
+		return 0, false
+	}
+	return id, true
+}
+
+func parseQueryParam(c *gin.Context, name string, def int) (int, bool) {
+	value, ok := c.GetQuery(name)
+	if !ok {
+		return def, true
+	}
+	result, err := strconv.Atoi(value)
+	return result, err == nil
+}
+
+type validationError struct {
+	Field string
+	Msg   string
+}
+
+// Same envelopes as the API: a quoted message for malformed JSON, {"errors":[...]} for failed validation
+func (p *Server) bind(c *gin.Context, dto any) bool {
+	err := c.ShouldBindJSON(dto)
+	if err == nil {
+		return true
+	}
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		c.JSON(http.StatusBadRequest, p.config.Messages.ParsingBodyJson)
+		return false
+	}
+	result := make([]validationError, 0, len(validationErrors))
+	for _, fieldError := range validationErrors {
+		msg := p.config.Messages.FieldIsRequired
+		if fieldError.Tag() == "email" {
+			msg = p.config.Messages.WrongEmailFormat
+		}
+		result = append(result, validationError{Field: fieldError.Field(), Msg: msg})
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"errors": result})
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package stub
+
+import (
+	"crypto/rand"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// In-memory stateful fake of indefinite-studies-api with the same routes, validation and error envelopes
+type Server struct {
+	config Config
+	mutex  sync.Mutex
+	tasks  *resource[Task, TaskDTO]
+	tags   *resource[Tag, TagDTO]
+	users  *resource[User, UserDTO]
+	notes  *resource[Note, NoteDTO]
+	// User id -> the only valid refresh token of the user
+	refreshTokens map[int]refreshToken
+	router        *gin.Engine
+}
+
+func New(config Config) *Server {
+	if len(config.Secret) == 0 {
+		config.Secret = make([]byte, 32)
+		rand.Read(config.Secret)
+	}
+	enums := config.Enums
+	result := &Server{config: config, refreshTokens: make(map[int]refreshToken)}
+	result.tasks = newResource[Task, TaskDTO](result, "task", enums.TaskStates, enums.TaskDeletedState)
+	result.tags = newResource[Tag, TagDTO](result, "tag", enums.TagStates, enums.TagDeletedState)
+	result.users = newResource[User, UserDTO](result, "user", enums.UserStates, enums.UserDeletedState)
+	result.notes = newResource[Note, NoteDTO](result, "note", enums.NoteStates, enums.NoteDeletedState)
+	result.router = result.setupRouter()
+	return result
+}
+
+func (p *Server) setupRouter() *gin.Engine {
+	r := gin.New()
+	r.Use(gin.Recovery())
+	r.NoRoute(func(c *gin.Context) {
+		c.String(http.StatusNotFound, p.config.Messages.PageNotFound)
+	})
+
+	authorized := r.Group("/")
+	authorized.Use(p.authRequired)
+	{
+		authorized.GET("/safe-ping", p.ping)
+	}
+
+	r.GET("/ping", p.ping)
+	r.POST("/auth/login", p.authenicate)
+	r.POST("/auth/refresh-token", p.refreshToken)
+
+	p.tasks.register(r, "/tasks")
+	p.tags.register(r, "/tags")
+	p.users.register(r, "/users")
+	p.notes.register(r, "/notes")
+	return r
+}
+
+func (p *Server) Router() *gin.Engine {
+	return p.router
+}
+
+// Drops all the data, the next created rows get ids from 1 again
+func (p *Server) Reset() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.tasks.reset()
+	p.tags.reset()
+	p.users.reset()
+	p.notes.reset()
+	p.refreshTokens = make(map[int]refreshToken)
+}
+
+func (p *Server) ping(c *gin.Context) {
+	c.JSON(http.StatusOK, p.config.Messages.Pong)
+}
+
+func (p *Server) Run(addr string) error {
+	return http.ListenAndServe(addr, p.router)
+}
@@ -1,4 +1,11 @@
 package main
 
+import (
+	"os"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/commands"
+)
+
 func main() {
+	os.Exit(commands.Run(os.Args[1:]))
 }
@@ -48,27 +48,33 @@ func TestApiAuthLogin(t *testing.T) {
 		assert.NotEqual(t, "", result.RefreshToken)
 		assert.NotEqual(t, "", result.AccessTokenExpiredAt)
 		assert.NotEqual(t, "", result.RefreshTokenExpiredAt)
-		assert.Equal(t, 236, len(result.AccessToken))
-		assert.Equal(t, 238, len(result.RefreshToken))
+		if !IsStubTarget() && !IsReplaying() { // the stub signs tokens of its own, cassettes keep placeholders of them
+			assert.Equal(t, 236, len(result.AccessToken))
+			assert.Equal(t, 238, len(result.RefreshToken))
+		}
 		assert.NotEqual(t, result.AccessToken, result.RefreshTokenExpiredAt)
 
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			record, err := queries.GetRefreshTokenByToken(tx, ctx, result.RefreshToken)
+		t.Run("RefreshTokensInDB", func(t *testing.T) {
+			SkipWithoutDB(t)
 
-			assert.NotNil(t, record)
-			assert.Equal(t, record.Token, result.RefreshToken)
-			assert.Equal(t, record.UserId, user.Id)
+			db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+				record, err := queries.GetRefreshTokenByToken(tx, ctx, result.RefreshToken)
 
-			return err
-		})()
+				assert.NotNil(t, record)
+				assert.Equal(t, record.Token, result.RefreshToken)
+				assert.Equal(t, record.UserId, user.Id)
 
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			_, err := queries.GetRefreshTokenByToken(tx, ctx, result.AccessToken)
+				return err
+			})()
 
-			assert.Equal(t, sql.ErrNoRows, err)
+			db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+				_, err := queries.GetRefreshTokenByToken(tx, ctx, result.AccessToken)
 
-			return err
-		})()
+				assert.Equal(t, sql.ErrNoRows, err)
+
+				return err
+			})()
+		})
 	})))
 	t.Run("RepeatAuthenication", RunWithRecreateDB((func(t *testing.T) {
 		user := utils.entityGenerators.GenerateUser(1)
@@ -104,39 +110,43 @@ func TestApiAuthLogin(t *testing.T) {
 		assert.NotEqual(t, authenication1.AccessToken, authenication2.AccessToken)
 		assert.NotEqual(t, authenication1.RefreshToken, authenication2.RefreshToken)
 
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			_, err := queries.GetRefreshTokenByToken(tx, ctx, authenication1.RefreshToken)
+		t.Run("RefreshTokensInDB", func(t *testing.T) {
+			SkipWithoutDB(t)
+
+			db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+				_, err := queries.GetRefreshTokenByToken(tx, ctx, authenication1.RefreshToken)
 
-			assert.Equal(t, sql.ErrNoRows, err)
+				assert.Equal(t, sql.ErrNoRows, err)
 
-			return err
-		})()
+				return err
+			})()
 
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			record, err := queries.GetRefreshTokenByToken(tx, ctx, authenication2.RefreshToken)
+			db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+				record, err := queries.GetRefreshTokenByToken(tx, ctx, authenication2.RefreshToken)
 
-			assert.NotNil(t, record)
-			assert.Equal(t, record.Token, authenication2.RefreshToken)
-			assert.Equal(t, record.UserId, user.Id)
+				assert.NotNil(t, record)
+				assert.Equal(t, record.Token, authenication2.RefreshToken)
+				assert.Equal(t, record.UserId, user.Id)
 
-			return err
-		})()
+				return err
+			})()
 
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			_, err := queries.GetRefreshTokenByToken(tx, ctx, authenication1.AccessToken)
+			db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+				_, err := queries.GetRefreshTokenByToken(tx, ctx, authenication1.AccessToken)
 
-			assert.Equal(t, sql.ErrNoRows, err)
+				assert.Equal(t, sql.ErrNoRows, err)
 
-			return err
-		})()
+				return err
+			})()
 
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			_, err := queries.GetRefreshTokenByToken(tx, ctx, authenication2.AccessToken)
+			db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+				_, err := queries.GetRefreshTokenByToken(tx, ctx, authenication2.AccessToken)
 
-			assert.Equal(t, sql.ErrNoRows, err)
+				assert.Equal(t, sql.ErrNoRows, err)
 
-			return err
-		})()
+				return err
+			})()
+		})
 	})))
 	t.Run("WrongEmail", RunWithRecreateDB((func(t *testing.T) {
 		user := utils.entityGenerators.GenerateUser(1)
@@ -153,13 +163,17 @@ func TestApiAuthLogin(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, httpStatusCode)
 		assert.Equal(t, "\""+api.ERROR_WRONG_PASSWORD_OR_EMAIL+"\"", body)
 
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			_, err := queries.GetRefreshTokenByUserId(tx, ctx, user.Id)
+		t.Run("RefreshTokensInDB", func(t *testing.T) {
+			SkipWithoutDB(t)
 
-			assert.Equal(t, sql.ErrNoRows, err)
+			db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+				_, err := queries.GetRefreshTokenByUserId(tx, ctx, user.Id)
 
-			return err
-		})()
+				assert.Equal(t, sql.ErrNoRows, err)
+
+				return err
+			})()
+		})
 	})))
 	t.Run("WrongPassword", RunWithRecreateDB((func(t *testing.T) {
 		user := utils.entityGenerators.GenerateUser(1)
@@ -176,13 +190,17 @@ func TestApiAuthLogin(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, httpStatusCode)
 		assert.Equal(t, "\""+api.ERROR_WRONG_PASSWORD_OR_EMAIL+"\"", body)
 
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			_, err := queries.GetRefreshTokenByUserId(tx, ctx, user.Id)
+		t.Run("RefreshTokensInDB", func(t *testing.T) {
+			SkipWithoutDB(t)
+
+			db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+				_, err := queries.GetRefreshTokenByUserId(tx, ctx, user.Id)
 
-			assert.Equal(t, sql.ErrNoRows, err)
+				assert.Equal(t, sql.ErrNoRows, err)
 
-			return err
-		})()
+				return err
+			})()
+		})
 	})))
 }
 
@@ -221,40 +239,43 @@ func TestApiAuthRefresh(t *testing.T) {
 		assert.NotEqual(t, authenication1.AccessToken, authenication2.AccessToken)
 		assert.NotEqual(t, authenication1.RefreshToken, authenication2.RefreshToken)
 
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			_, err := queries.GetRefreshTokenByToken(tx, ctx, authenication1.RefreshToken)
+		t.Run("RefreshTokensInDB", func(t *testing.T) {
+			SkipWithoutDB(t)
 
-			assert.Equal(t, sql.ErrNoRows, err)
+			db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+				_, err := queries.GetRefreshTokenByToken(tx, ctx, authenication1.RefreshToken)
 
-			return err
-		})()
+				assert.Equal(t, sql.ErrNoRows, err)
 
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			record, err := queries.GetRefreshTokenByToken(tx, ctx, authenication2.RefreshToken)
+				return err
+			})()
 
-			assert.NotNil(t, record)
-			assert.Equal(t, record.Token, authenication2.RefreshToken)
-			assert.Equal(t, record.UserId, user.Id)
+			db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+				record, err := queries.GetRefreshTokenByToken(tx, ctx, authenication2.RefreshToken)
 
-			return err
-		})()
+				assert.NotNil(t, record)
+				assert.Equal(t, record.Token, authenication2.RefreshToken)
+				assert.Equal(t, record.UserId, user.Id)
 
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			_, err := queries.GetRefreshTokenByToken(tx, ctx, authenication1.AccessToken)
+				return err
+			})()
 
-			assert.Equal(t, sql.ErrNoRows, err)
+			db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+				_, err := queries.GetRefreshTokenByToken(tx, ctx, authenication1.AccessToken)
 
-			return err
-		})()
+				assert.Equal(t, sql.ErrNoRows, err)
 
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			_, err := queries.GetRefreshTokenByToken(tx, ctx, authenication2.AccessToken)
+				return err
+			})()
 
-			assert.Equal(t, sql.ErrNoRows, err)
+			db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+				_, err := queries.GetRefreshTokenByToken(tx, ctx, authenication2.AccessToken)
 
-			return err
-		})()
+				assert.Equal(t, sql.ErrNoRows, err)
 
+				return err
+			})()
+		})
 	})))
 	t.Run("ExpiredRefreshToken", RunWithRecreateDB((func(t *testing.T) {
 		user := utils.entityGenerators.GenerateUser(1)
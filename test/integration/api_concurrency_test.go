@@ -0,0 +1,81 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/ArtemVoronov/indefinite-studies-api/internal/api"
+	"github.com/ArtemVoronov/indefinite-studies-api/internal/db/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func AssertSingleCreated(t *testing.T, calls []ConcurrentCall) {
+	for _, call := range calls {
+		assert.Nil(t, call.Err)
+	}
+	assert.Equal(t, 1, CountCallsWithStatus(calls, http.StatusCreated), "exactly one request is expected to be created, actual results: %v", calls)
+	assert.Equal(t, len(calls)-1, CountCalls(calls, http.StatusBadRequest, "\""+api.DUPLICATE_FOUND+"\""), "all other requests are expected to be duplicates, actual results: %v", calls)
+}
+
+func TestApiConcurrentCreate(t *testing.T) {
+	SkipWithoutDB(t)
+
+	t.Run("DuplicateCase: tags", RunWithRecreateDB((func(t *testing.T) {
+		RunWithSeeds(t, func(t *testing.T, seed int64) {
+			name := utils.entityGenerators.GenerateTagName(TEST_TAG_NAME_TEMPLATE, int(seed))
+			scheduler := NewSeededScheduler(seed, CONCURRENCY_WORKERS_COUNT)
+
+			calls := scheduler.Run(func(worker int) (int, string, error) {
+				return testHttpClient.CreateTag(name, entities.TAG_STATE_NEW)
+			})
+
+			AssertSingleCreated(t, calls)
+			assert.Equal(t, 1, CountRowsInDB(t, "tags", "name", name))
+		})
+	})))
+	t.Run("DuplicateCase: tasks", RunWithRecreateDB((func(t *testing.T) {
+		RunWithSeeds(t, func(t *testing.T, seed int64) {
+			name := utils.entityGenerators.GenerateTaskName(TEST_TASK_NAME_TEMPLATE, int(seed))
+			scheduler := NewSeededScheduler(seed, CONCURRENCY_WORKERS_COUNT)
+
+			calls := scheduler.Run(func(worker int) (int, string, error) {
+				return testHttpClient.CreateTask(name, entities.TASK_STATE_NEW)
+			})
+
+			AssertSingleCreated(t, calls)
+			assert.Equal(t, 1, CountRowsInDB(t, "tasks", "name", name))
+		})
+	})))
+	t.Run("DuplicateCase: user emails", RunWithRecreateDB((func(t *testing.T) {
+		RunWithSeeds(t, func(t *testing.T, seed int64) {
+			email := utils.entityGenerators.GenerateUserEmail(TEST_USER_EMAIL_TEMPLATE, int(seed))
+			scheduler := NewSeededScheduler(seed, CONCURRENCY_WORKERS_COUNT)
+
+			calls := scheduler.Run(func(worker int) (int, string, error) {
+				login := utils.entityGenerators.GenerateUserLogin(TEST_USER_LOGIN_TEMPLATE, int(seed)*CONCURRENCY_WORKERS_COUNT+worker)
+				return testHttpClient.CreateUser(login, email, TEST_USER_PASSWORD_1, TEST_USER_ROLE_1, TEST_USER_STATE_1)
+			})
+
+			AssertSingleCreated(t, calls)
+			assert.Equal(t, 1, CountRowsInDB(t, "users", "email", email))
+		})
+	})))
+	t.Run("DifferentPayloads: tags", RunWithRecreateDB((func(t *testing.T) {
+		RunWithSeeds(t, func(t *testing.T, seed int64) {
+			scheduler := NewSeededScheduler(seed, CONCURRENCY_WORKERS_COUNT)
+
+			calls := scheduler.Run(func(worker int) (int, string, error) {
+				name := utils.entityGenerators.GenerateTagName(TEST_TAG_NAME_TEMPLATE+strconv.FormatInt(seed, 10)+"-", worker)
+				return testHttpClient.CreateTag(name, entities.TAG_STATE_NEW)
+			})
+
+			for _, call := range calls {
+				assert.Equal(t, http.StatusCreated, call.HttpStatusCode, "worker %d: %s", call.Worker, call.Body)
+			}
+		})
+	})))
+}
@@ -0,0 +1,80 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ArtemVoronov/indefinite-studies-api/internal/db/entities"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/cassette"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/contract"
+	"github.com/stretchr/testify/assert"
+)
+
+func GetContractDir() string {
+	if dir, ok := os.LookupEnv("QA_CONTRACT_DIR"); ok {
+		return dir
+	}
+	return GetRootPath() + "/test/contracts"
+}
+
+func CreateContractFixtures() contract.Fixtures {
+	return contract.Fixtures{
+		UserRole:  entities.USER_ROLE_OWNER,
+		UserState: entities.USER_STATE_NEW,
+		TagState:  entities.TAG_STATE_NEW,
+		NoteState: entities.NOTE_STATE_NEW,
+	}
+}
+
+// Provider that sends the requests through the test client
+func CreateContractProvider() contract.Provider {
+	return contract.ProviderFunc(func(ctx context.Context, req cassette.Request) (cassette.Response, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, req.Method, req.Url, bytes.NewBufferString(req.Body))
+		if err != nil {
+			return cassette.Response{}, err
+		}
+		for name, values := range req.Header {
+			httpReq.Header[name] = values
+		}
+		httpStatusCode, body, err := testHttpClient.Serve(httpReq)
+		return cassette.Response{Status: httpStatusCode, Body: body}, err
+	})
+}
+
+// Verifies the contracts of test/contracts, a subtest per interaction on a fresh DB
+func TestApiContracts(t *testing.T) {
+	contracts, err := contract.LoadAll(GetContractDir())
+	assert.Nil(t, err)
+	if len(contracts) == 0 {
+		t.Skipf("no contracts in '%s'", GetContractDir())
+	}
+
+	verifier := contract.NewVerifier(CreateContractProvider(), CreateContractFixtures())
+	var report contract.Report
+	for _, c := range contracts {
+		c := c
+		for _, interaction := range c.Interactions {
+			interaction := interaction
+			t.Run(c.Consumer+"/"+interaction.Description, RunWithRecreateDB((func(t *testing.T) {
+				result := verifier.VerifyInteraction(context.Background(), c, interaction)
+				report.Results = append(report.Results, result)
+				for _, mismatch := range result.Mismatches {
+					t.Error(mismatch)
+				}
+			})))
+		}
+	}
+
+	if report.Failed() {
+		var text strings.Builder
+		report.WriteText(&text)
+		t.Log("\n" + text.String())
+	}
+}
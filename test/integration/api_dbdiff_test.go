@@ -0,0 +1,87 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ArtemVoronov/indefinite-studies-api/internal/db/entities"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/dbsnapshot"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApiDBSideEffects(t *testing.T) {
+	SkipWithoutDB(t)
+
+	t.Run("CreateTag", RunWithRecreateDB((func(t *testing.T) {
+		defer EnableDBDiff()()
+
+		httpStatusCode, _, _ := testHttpClient.CreateTag(TEST_TAG_NAME_1, TEST_TAG_STATE_1)
+
+		assert.Equal(t, http.StatusCreated, httpStatusCode)
+		AssertDBDiff(t, "inserted tags#1")
+	})))
+	t.Run("CreateTag: duplicate", RunWithRecreateDB((func(t *testing.T) {
+		defer EnableDBDiff()()
+		testHttpClient.CreateTag(TEST_TAG_NAME_1, TEST_TAG_STATE_1)
+
+		httpStatusCode, _, _ := testHttpClient.CreateTag(TEST_TAG_NAME_1, TEST_TAG_STATE_1)
+
+		assert.Equal(t, http.StatusBadRequest, httpStatusCode)
+		AssertNoDBChanges(t)
+	})))
+	t.Run("UpdateTag", RunWithRecreateDB((func(t *testing.T) {
+		defer EnableDBDiff()()
+		testHttpClient.CreateTag(TEST_TAG_NAME_1, TEST_TAG_STATE_1)
+
+		httpStatusCode, _, _ := testHttpClient.UpdateTag(1, TEST_TAG_NAME_2, TEST_TAG_STATE_1)
+
+		assert.Equal(t, http.StatusOK, httpStatusCode)
+		AssertDBDiff(t, "updated tags#1")
+		assert.Equal(t, []string{"name"}, LastDBDiff(t).Changes[0].Changed())
+	})))
+	t.Run("DeleteNote: soft delete", RunWithRecreateDB((func(t *testing.T) {
+		defer EnableDBDiff()()
+		testHttpClient.CreateNote(TEST_NOTE_TEXT_1, TEST_NOTE_TOPIC_1, TEST_NOTE_TAG_ID_1, TEST_NOTE_USER_ID_1, TEST_NOTE_STATE_1)
+
+		httpStatusCode, _, _ := testHttpClient.DeleteNote(1)
+
+		assert.Equal(t, http.StatusOK, httpStatusCode)
+		AssertDBDiff(t, "updated notes#1")
+		change := LastDBDiff(t).Changes[0]
+		assert.Equal(t, entities.NOTE_STATE_DELETED, change.After["state"])
+	})))
+	t.Run("GetNotes: read only", RunWithRecreateDB((func(t *testing.T) {
+		defer EnableDBDiff()()
+		testHttpClient.CreateNote(TEST_NOTE_TEXT_1, TEST_NOTE_TOPIC_1, TEST_NOTE_TAG_ID_1, TEST_NOTE_USER_ID_1, TEST_NOTE_STATE_1)
+
+		httpStatusCode, _, _ := testHttpClient.GetNotes(nil, nil)
+
+		assert.Equal(t, http.StatusOK, httpStatusCode)
+		AssertNoDBChanges(t)
+	})))
+	t.Run("Login: wrong password writes nothing", RunWithRecreateDB((func(t *testing.T) {
+		defer EnableDBDiff()()
+		user := utils.entityGenerators.GenerateUser(1)
+		testHttpClient.CreateUser(user.Login, user.Email, user.Password, user.Role, user.State)
+
+		httpStatusCode, _, _ := testHttpClient.Authenicate(user.Email, user.Password+"wrong")
+
+		assert.NotEqual(t, http.StatusOK, httpStatusCode)
+		AssertNoDBChanges(t)
+	})))
+	t.Run("Login: stores refresh token", RunWithRecreateDB((func(t *testing.T) {
+		defer EnableDBDiff()()
+		user := utils.entityGenerators.GenerateUser(1)
+		testHttpClient.CreateUser(user.Login, user.Email, user.Password, user.Role, user.State)
+
+		httpStatusCode, _, _ := testHttpClient.Authenicate(user.Email, user.Password)
+
+		assert.Equal(t, http.StatusOK, httpStatusCode)
+		diff := LastDBDiff(t)
+		assert.Equal(t, 1, len(diff.Changes), diff.String())
+		assert.Equal(t, 1, diff.Count(dbsnapshot.INSERTED, "refresh_tokens")+diff.Count(dbsnapshot.UPDATED, "refresh_tokens"), diff.String())
+	})))
+}
@@ -0,0 +1,61 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/cassette"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/difftest"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/sqltrace"
+	"github.com/stretchr/testify/assert"
+)
+
+// Replays the recorded requests against the in-process router and the build at QA_DIFF_TARGET_URL.
+// Both DBs are reset before every test, otherwise ids and side effects of the build would drift:
+// with QA_DIFF_TARGET_DSN its tables are truncated and DB side effects are compared,
+// with QA_DIFF_TARGET_RESET_URL that endpoint is called.
+func TestApiDifferential(t *testing.T) {
+	SkipWithoutDB(t)
+	targetUrl, ok := os.LookupEnv("QA_DIFF_TARGET_URL")
+	if !ok {
+		t.Skip("QA_DIFF_TARGET_URL is not set")
+	}
+
+	cassettes, err := cassette.LoadAll(GetCassetteDir(), GetCassetteVersion())
+	assert.Nil(t, err)
+	steps := difftest.FromCassettes(cassettes...)
+	if len(steps) == 0 {
+		t.Skipf("no cassettes for version '%s', record them with QA_CASSETTE_MODE=record", GetCassetteVersion())
+	}
+
+	local := difftest.NewHandlerTarget("in-process", TestRouter)
+	local.Reset = func() error {
+		RecreateTestDB()
+		return nil
+	}
+	remote := difftest.NewUrlTarget(targetUrl, targetUrl)
+	if dsn, ok := os.LookupEnv("QA_DIFF_TARGET_DSN"); ok {
+		remoteDB, err := sql.Open("postgres", dsn)
+		assert.Nil(t, err)
+		defer remoteDB.Close()
+		local.DB, remote.DB = HarnessDB(), remoteDB
+		remote.Reset = difftest.TruncateReset(remoteDB)
+	} else if resetUrl, ok := os.LookupEnv("QA_DIFF_TARGET_RESET_URL"); ok {
+		remote.Reset = difftest.UrlReset(remote.Client, resetUrl)
+	} else {
+		t.Skip("QA_DIFF_TARGET_DSN or QA_DIFF_TARGET_RESET_URL is required to reset the target between tests")
+	}
+
+	report, err := difftest.NewRunner(local, remote).Run(sqltrace.Untraced(context.Background()), steps)
+	assert.Nil(t, err)
+
+	var text strings.Builder
+	report.WriteText(&text)
+	assert.False(t, report.Failed(), text.String())
+}
@@ -0,0 +1,158 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ArtemVoronov/indefinite-studies-api/internal/api"
+	"github.com/ArtemVoronov/indefinite-studies-api/internal/db/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	LINEARIZABILITY_WORKERS_COUNT        int = 4
+	LINEARIZABILITY_OPS_PER_WORKER_COUNT int = 5
+)
+
+type NoteAndTagState struct {
+	NoteExists bool
+	NoteText   string
+	TagExists  bool
+	TagName    string
+}
+
+var NoteAndTagModel = SequentialModel{
+	Init: func() any {
+		return NoteAndTagState{NoteExists: true, NoteText: TEST_NOTE_TEXT_1, TagExists: true, TagName: TEST_TAG_NAME_1}
+	},
+	Step: func(state any, op Operation) (any, bool) {
+		s := state.(NoteAndTagState)
+		if op.Err != nil {
+			return s, false
+		}
+		switch op.Kind {
+		case OPERATION_UPDATE_NOTE:
+			if !s.NoteExists {
+				return s, isNotFound(op)
+			}
+			s.NoteText = TEST_NOTE_TEXT_TEMPLATE + op.Input
+			return s, isDone(op)
+		case OPERATION_DELETE_NOTE:
+			if !s.NoteExists {
+				return s, isNotFound(op)
+			}
+			s.NoteExists = false
+			return s, isDone(op)
+		case OPERATION_UPDATE_TAG:
+			if !s.TagExists {
+				return s, isNotFound(op)
+			}
+			s.TagName = TEST_TAG_NAME_TEMPLATE + op.Input
+			return s, isDone(op)
+		case OPERATION_GET_NOTE:
+			if !s.NoteExists {
+				return s, isNotFound(op)
+			}
+			var note entities.Note
+			if op.HttpStatusCode != http.StatusOK || json.Unmarshal([]byte(op.Body), &note) != nil {
+				return s, false
+			}
+			return s, note.Text == s.NoteText
+		}
+		return s, false
+	},
+	Key: func(state any) string {
+		return fmt.Sprintf("%v", state)
+	},
+}
+
+func isDone(op Operation) bool {
+	return op.HttpStatusCode == http.StatusOK && op.Body == "\""+api.DONE+"\""
+}
+
+func isNotFound(op Operation) bool {
+	return op.HttpStatusCode == http.StatusNotFound && op.Body == "\""+api.PAGE_NOT_FOUND+"\""
+}
+
+// Reports operations that succeeded on the note although they were called after its deletion had been acknowledged
+func DetectResurrections(history []Operation) []Anomaly {
+	var result []Anomaly
+	for _, deletion := range history {
+		if deletion.Kind != OPERATION_DELETE_NOTE || !isDone(deletion) {
+			continue
+		}
+		for _, op := range history {
+			if op.Kind != OPERATION_UPDATE_NOTE && op.Kind != OPERATION_GET_NOTE {
+				continue
+			}
+			if op.Call.After(deletion.Return) && op.HttpStatusCode == http.StatusOK {
+				result = append(result, Anomaly{
+					Description: "note was resurrected after the acknowledged deletion",
+					Operations:  []Operation{deletion, op},
+				})
+			}
+		}
+	}
+	return result
+}
+
+func ExecuteNoteAndTagOperation(worker int, kind string, input string) (int, string, error) {
+	switch kind {
+	case OPERATION_UPDATE_NOTE:
+		return testHttpClient.UpdateNote("1", TEST_NOTE_TEXT_TEMPLATE+input, TEST_NOTE_TOPIC_1, TEST_NOTE_TAG_ID_1, TEST_NOTE_USER_ID_1, TEST_NOTE_STATE_1)
+	case OPERATION_DELETE_NOTE:
+		return testHttpClient.DeleteNote("1")
+	case OPERATION_UPDATE_TAG:
+		return testHttpClient.UpdateTag("1", TEST_TAG_NAME_TEMPLATE+input, TEST_TAG_STATE_1)
+	case OPERATION_GET_NOTE:
+		httpStatusCode, body := testHttpClient.GetNote("1")
+		return httpStatusCode, body, nil
+	}
+	return -1, "", fmt.Errorf("unknown operation: %s", kind)
+}
+
+func RunNoteAndTagScenario(t *testing.T, seed int64, kinds []string) {
+	httpStatusCode, body, _ := testHttpClient.CreateTag(TEST_TAG_NAME_1, TEST_TAG_STATE_1)
+	assert.Equal(t, http.StatusCreated, httpStatusCode)
+	assert.Equal(t, "1", body)
+
+	httpStatusCode, body, _ = testHttpClient.CreateNote(TEST_NOTE_TEXT_1, TEST_NOTE_TOPIC_1, TEST_NOTE_TAG_ID_1, TEST_NOTE_USER_ID_1, TEST_NOTE_STATE_1)
+	assert.Equal(t, http.StatusCreated, httpStatusCode)
+	assert.Equal(t, "1", body)
+
+	scenario := ConcurrencyScenario{
+		Seed:         seed,
+		Workers:      LINEARIZABILITY_WORKERS_COUNT,
+		OpsPerWorker: LINEARIZABILITY_OPS_PER_WORKER_COUNT,
+		Kinds:        kinds,
+		Execute:      ExecuteNoteAndTagOperation,
+	}
+	history := scenario.Run()
+	history.Record(-1, OPERATION_GET_NOTE, "", func() (int, string, error) {
+		return ExecuteNoteAndTagOperation(-1, OPERATION_GET_NOTE, "")
+	})
+
+	AssertLinearizable(t, NoteAndTagModel, history.Operations(), DetectResurrections)
+}
+
+func TestApiLinearizability(t *testing.T) {
+	t.Run("ConcurrentUpdates", func(t *testing.T) {
+		RunWithSeeds(t, func(t *testing.T, seed int64) {
+			RunWithRecreateDB(func(t *testing.T) {
+				RunNoteAndTagScenario(t, seed, []string{OPERATION_UPDATE_NOTE, OPERATION_UPDATE_TAG, OPERATION_GET_NOTE})
+			})(t)
+		})
+	})
+	t.Run("ConcurrentUpdatesAndDeletes", func(t *testing.T) {
+		RunWithSeeds(t, func(t *testing.T, seed int64) {
+			RunWithRecreateDB(func(t *testing.T) {
+				RunNoteAndTagScenario(t, seed, []string{OPERATION_UPDATE_NOTE, OPERATION_UPDATE_TAG, OPERATION_DELETE_NOTE, OPERATION_GET_NOTE})
+			})(t)
+		})
+	})
+}
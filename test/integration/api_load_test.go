@@ -0,0 +1,90 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/ArtemVoronov/indefinite-studies-api/internal/db/entities"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/load"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/perf"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/sqltrace"
+	"github.com/stretchr/testify/assert"
+)
+
+func AssertNoPerformanceRegression(t *testing.T, baselines string, scenario string, report load.Report) {
+	current := perf.FromReport(scenario, report)
+	if os.Getenv("QA_PERF_UPDATE") != "" {
+		assert.Nil(t, perf.WriteBaselines(baselines, current))
+		return
+	}
+
+	tolerances, err := perf.ParseTolerances(GetEnvString("QA_PERF_TOLERANCES", perf.DEFAULT_TOLERANCES))
+	assert.Nil(t, err)
+	result, err := perf.Compare(baselines, current, tolerances)
+	assert.Nil(t, err)
+
+	var table bytes.Buffer
+	result.WriteTable(&table)
+	if result.Failed() {
+		t.Errorf("performance regression detected:\n%s", table.String())
+	} else {
+		t.Logf("performance gate passed:\n%s", table.String())
+	}
+}
+
+// Load mode is not a part of the regular run, enable it with QA_LOAD=1.
+// Mix, RPS, concurrency, duration and report path are configured via QA_LOAD_* variables (see GetLoadConfig).
+// If QA_PERF_BASELINES is set the results are checked against the stored baselines (QA_PERF_UPDATE=1 rewrites them).
+func TestApiLoad(t *testing.T) {
+	if os.Getenv("QA_LOAD") == "" {
+		t.Skip("load mode is disabled, set QA_LOAD=1 to enable it")
+	}
+
+	t.Run("Mix", RunWithRecreateDB((func(t *testing.T) {
+		config := GetLoadConfig(t)
+
+		for i := 1; i <= config.NotesCount; i++ {
+			httpStatusCode, _, err := testHttpClient.CreateNote(
+				utils.entityGenerators.GenerateNoteText(TEST_NOTE_TEXT_TEMPLATE, i),
+				utils.entityGenerators.GenerateNoteTopic(TEST_NOTE_TOPIC_TEMPLATE, i),
+				TEST_NOTE_TAG_ID_1, TEST_NOTE_USER_ID_1, entities.NOTE_STATE_NEW)
+
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusCreated, httpStatusCode)
+		}
+
+		weights, err := load.ParseMix(config.Mix)
+		assert.Nil(t, err)
+		mix, err := load.BuildMix(CreateLoadRegistry(&testHttpClient, config.NotesCount, config.Options.Seed), weights)
+		assert.Nil(t, err)
+
+		// the checker queries the DB before every call and the tracing records every statement, both would distort the latencies
+		enable := softDeleteChecker.Disable()
+		enableTracing := sqltrace.Disable()
+		report, err := load.Run(context.Background(), config.Options, mix)
+		enableTracing()
+		enable()
+		assert.Nil(t, err)
+
+		var text bytes.Buffer
+		report.WriteText(&text)
+		t.Logf("scenario '%s':\n%s", config.Scenario, text.String())
+
+		if config.ReportPath != "" {
+			file, err := os.Create(config.ReportPath)
+			assert.Nil(t, err)
+			defer file.Close()
+			assert.Nil(t, report.WriteJSON(file))
+		}
+
+		if baselines := os.Getenv("QA_PERF_BASELINES"); baselines != "" {
+			AssertNoPerformanceRegression(t, baselines, config.Scenario, report)
+		}
+	})))
+}
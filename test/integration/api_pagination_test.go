@@ -0,0 +1,251 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/seed"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	// Not a multiple of any walked limit, so the last page is always short
+	PAGINATION_ROWS_COUNT  int = 137
+	PAGINATION_WALKS_COUNT int = 3
+)
+
+var PAGINATION_WALK_LIMITS = []int{1, 7, PAGINATION_DEFAULT_LIMIT, PAGINATION_ROWS_COUNT - 1, PAGINATION_ROWS_COUNT, PAGINATION_ROWS_COUNT + 1, 1000}
+
+type BoundaryCase struct {
+	Name   string
+	Limit  any
+	Offset any
+	// A 200 response must be a consistent page even where a 400 is acceptable too
+	ExpectedStatus []int
+	// -1 means any count
+	ExpectedCount int
+}
+
+func CreateBoundaryCases(total int) []BoundaryCase {
+	okOrBadRequest := []int{http.StatusOK, http.StatusBadRequest}
+	return []BoundaryCase{
+		{Name: "OffsetAtEnd", Limit: 10, Offset: total, ExpectedStatus: []int{http.StatusOK}, ExpectedCount: 0},
+		{Name: "OffsetBeyondEnd", Limit: 10, Offset: total + 1000, ExpectedStatus: []int{http.StatusOK}, ExpectedCount: 0},
+		{Name: "LastPartialPage", Limit: 10, Offset: total - 3, ExpectedStatus: []int{http.StatusOK}, ExpectedCount: 3},
+		{Name: "HugeOffset", Limit: 10, Offset: 1<<31 - 1, ExpectedStatus: []int{http.StatusOK}, ExpectedCount: 0},
+		{Name: "ZeroLimit", Limit: 0, Offset: 0, ExpectedStatus: okOrBadRequest, ExpectedCount: -1},
+		{Name: "NegativeLimit", Limit: -1, Offset: 0, ExpectedStatus: okOrBadRequest, ExpectedCount: -1},
+		{Name: "NegativeOffset", Limit: 10, Offset: -1, ExpectedStatus: okOrBadRequest, ExpectedCount: -1},
+		{Name: "HugeLimit", Limit: 1<<31 - 1, Offset: 0, ExpectedStatus: okOrBadRequest, ExpectedCount: -1},
+		{Name: "OverflowLimit", Limit: "99999999999999999999", Offset: 0, ExpectedStatus: okOrBadRequest, ExpectedCount: -1},
+		{Name: "OverflowOffset", Limit: 10, Offset: "99999999999999999999", ExpectedStatus: okOrBadRequest, ExpectedCount: -1},
+		{Name: "NonNumericLimit", Limit: "ten", Offset: 0, ExpectedStatus: okOrBadRequest, ExpectedCount: -1},
+		{Name: "NonNumericOffset", Limit: 10, Offset: "ten", ExpectedStatus: okOrBadRequest, ExpectedCount: -1},
+	}
+}
+
+func SeedPaginationData(t *testing.T) seed.Result {
+	config := seed.DefaultConfig()
+	config.Users = PAGINATION_ROWS_COUNT
+	config.Tags = PAGINATION_ROWS_COUNT
+	config.Tasks = PAGINATION_ROWS_COUNT
+	config.NotesPerUser = seed.Fixed{Value: 1}
+	return SeedInDB(t, config)
+}
+
+func TestApiPagination(t *testing.T) {
+	SkipWithoutDB(t)
+
+	t.Run("PageWalks", RunWithRecreateDB((func(t *testing.T) {
+		SeedPaginationData(t)
+
+		for _, endpoint := range CreateListEndpoints() {
+			expected := GetIdsInDB(t, endpoint.Table)
+			assert.Equal(t, PAGINATION_ROWS_COUNT, len(expected), endpoint.Name)
+
+			var reference []int
+			for _, limit := range PAGINATION_WALK_LIMITS {
+				for i := 0; i < PAGINATION_WALKS_COUNT; i++ {
+					name := fmt.Sprintf("%s(limit=%d) walk #%d", endpoint.Name, limit, i+1)
+					actual := WalkPages(t, endpoint, limit)
+					AssertNoDuplicatesOrGaps(t, name, expected, actual)
+					if reference == nil {
+						reference = actual
+						continue
+					}
+					assert.Equal(t, reference, actual, "%s: order differs from the first walk", name)
+				}
+			}
+		}
+	})))
+
+	t.Run("Boundaries", RunWithRecreateDB((func(t *testing.T) {
+		SeedPaginationData(t)
+
+		for _, endpoint := range CreateListEndpoints() {
+			expected := GetIdsInDB(t, endpoint.Table)
+			for _, c := range CreateBoundaryCases(len(expected)) {
+				name := fmt.Sprintf("%s %s (limit=%v, offset=%v)", endpoint.Name, c.Name, c.Limit, c.Offset)
+				httpStatusCode, body, err := endpoint.List(c.Limit, c.Offset)
+
+				assert.Nil(t, err, name)
+				if !assert.Contains(t, c.ExpectedStatus, httpStatusCode, "%s: %s", name, body) || httpStatusCode != http.StatusOK {
+					continue
+				}
+				page, err := ParsePage(body)
+				if !assert.Nil(t, err, name) {
+					continue
+				}
+				assert.Equal(t, len(page.Data), page.Count, "%s: Count must be equal to the length of Data", name)
+				assert.Empty(t, FindDuplicates(page.Ids()), "%s: duplicates", name)
+				assert.Empty(t, FindGaps(page.Ids(), expected), "%s: unexpected ids", name)
+				if c.ExpectedCount >= 0 {
+					assert.Equal(t, c.ExpectedCount, page.Count, name)
+				}
+			}
+		}
+	})))
+
+	t.Run("ConcurrentInsertsAndDeletes", func(t *testing.T) {
+		RunWithSeeds(t, func(t *testing.T, seed int64) {
+			RunWithRecreateDB(func(t *testing.T) {
+				seeded := SeedPaginationData(t)
+				tagId := seeded.TagIds[0]
+				userId := seeded.UserIds[0]
+
+				endpoints := []MutableListEndpoint{
+					{
+						ListEndpoint: ListEndpoint{Name: "GetTags", Table: "tags", List: testHttpClient.GetTags},
+						Create: func(i int) (int, string, error) {
+							return testHttpClient.CreateTag("Concurrent Tag "+strconv.Itoa(i), TEST_TAG_STATE_1)
+						},
+						Delete: testHttpClient.DeleteTag,
+						// the first tag is referenced by the notes created concurrently
+						Protected: tagId,
+					},
+					{
+						ListEndpoint: ListEndpoint{Name: "GetNotes", Table: "notes", List: testHttpClient.GetNotes},
+						Create: func(i int) (int, string, error) {
+							return testHttpClient.CreateNote("Concurrent Note "+strconv.Itoa(i), TEST_NOTE_TOPIC_1, tagId, userId, TEST_NOTE_STATE_1)
+						},
+						Delete: testHttpClient.DeleteNote,
+					},
+				}
+				for _, endpoint := range endpoints {
+					RunPaginationUnderMutations(t, endpoint, seed)
+				}
+			})(t)
+		})
+	})
+}
+
+type MutableListEndpoint struct {
+	ListEndpoint
+	Create func(i int) (int, string, error)
+	Delete func(id any) (int, string, error)
+	// Id that must not be deleted
+	Protected int
+}
+
+// Walks pages while another goroutine creates and deletes rows.
+// Offset pagination cannot hide the mutations, but it must stay bounded by them:
+// every delete before the cursor shifts the following rows by one, so it can cause at most one gap.
+func RunPaginationUnderMutations(t *testing.T, endpoint MutableListEndpoint, seed int64) {
+	initial := GetIdsInDB(t, endpoint.Table)
+	random := rand.New(rand.NewSource(seed))
+	candidates := make([]int, 0, len(initial))
+	for _, id := range random.Perm(len(initial)) {
+		if initial[id] != endpoint.Protected {
+			candidates = append(candidates, initial[id])
+		}
+	}
+
+	var deletesCount int64
+	var mu sync.Mutex
+	deleted := make(map[int]bool)
+	created := make(map[int]bool)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if i%2 == 0 {
+				httpStatusCode, body, _ := endpoint.Create(i)
+				assert.Equal(t, http.StatusCreated, httpStatusCode, "%s create: %s", endpoint.Name, body)
+				if id, err := strconv.Atoi(body); err == nil {
+					mu.Lock()
+					created[id] = true
+					mu.Unlock()
+				}
+				continue
+			}
+			if len(candidates) == 0 {
+				continue
+			}
+			id := candidates[0]
+			candidates = candidates[1:]
+			mu.Lock()
+			deleted[id] = true
+			mu.Unlock()
+			httpStatusCode, body, _ := endpoint.Delete(id)
+			assert.Equal(t, http.StatusOK, httpStatusCode, "%s delete %d: %s", endpoint.Name, id, body)
+			atomic.AddInt64(&deletesCount, 1)
+		}
+	}()
+
+	type walk struct {
+		ids     []int
+		deletes int64
+	}
+	var walks []walk
+	for i := 0; i < PAGINATION_WALKS_COUNT; i++ {
+		before := atomic.LoadInt64(&deletesCount)
+		ids := WalkPages(t, endpoint.ListEndpoint, 10)
+		// a delete that is in flight when the walk ends may still have affected it
+		walks = append(walks, walk{ids: ids, deletes: atomic.LoadInt64(&deletesCount) - before + 1})
+	}
+	close(stop)
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	var stable []int
+	for _, id := range initial {
+		if !deleted[id] {
+			stable = append(stable, id)
+		}
+	}
+	known := append(append([]int{}, initial...), keys(created)...)
+	for i, w := range walks {
+		name := fmt.Sprintf("%s walk #%d under mutations", endpoint.Name, i+1)
+		assert.Empty(t, FindDuplicates(w.ids), "%s: duplicates", name)
+		assert.True(t, IsStrictlyAscending(w.ids), "%s: order is not stable: %v", name, w.ids)
+
+		gaps := FindGaps(stable, w.ids)
+		assert.LessOrEqual(t, int64(len(gaps)), w.deletes, "%s: more rows skipped than deleted concurrently: %v", name, gaps)
+
+		assert.Empty(t, FindGaps(w.ids, known), "%s: unexpected ids", name)
+	}
+}
+
+func keys(m map[int]bool) []int {
+	result := make([]int, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	return result
+}
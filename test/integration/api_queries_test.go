@@ -0,0 +1,117 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+
+	"github.com/ArtemVoronov/indefinite-studies-api/internal/db"
+	"github.com/ArtemVoronov/indefinite-studies-api/internal/db/entities"
+	"github.com/ArtemVoronov/indefinite-studies-api/internal/db/queries"
+	"github.com/stretchr/testify/assert"
+)
+
+const QUERIES_PAGE_SIZE int = 50
+
+func TestApiQueriesCount(t *testing.T) {
+	SkipWithoutDB(t)
+
+	t.Run("GetNotes", RunWithRecreateDB((func(t *testing.T) {
+		for i := 1; i <= QUERIES_PAGE_SIZE; i++ {
+			testHttpClient.CreateNote(
+				utils.entityGenerators.GenerateNoteText(TEST_NOTE_TEXT_TEMPLATE, i),
+				utils.entityGenerators.GenerateNoteTopic(TEST_NOTE_TOPIC_TEMPLATE, i),
+				TEST_NOTE_TAG_ID_1, TEST_NOTE_USER_ID_1, entities.NOTE_STATE_NEW)
+		}
+
+		statements := CaptureQueries(func() {
+			httpStatusCode, _, _ := testHttpClient.GetNotes(QUERIES_PAGE_SIZE, 0)
+			assert.Equal(t, http.StatusOK, httpStatusCode)
+		})
+
+		AssertMaxQueries(t, statements, 2)
+		AssertNoRepeatedQueries(t, statements)
+	})))
+	t.Run("GetTags", RunWithRecreateDB((func(t *testing.T) {
+		for i := 1; i <= QUERIES_PAGE_SIZE; i++ {
+			testHttpClient.CreateTag(utils.entityGenerators.GenerateTagName(TEST_TAG_NAME_TEMPLATE, i), entities.TAG_STATE_NEW)
+		}
+
+		statements := CaptureQueries(func() {
+			httpStatusCode, _, _ := testHttpClient.GetTags(QUERIES_PAGE_SIZE, 0)
+			assert.Equal(t, http.StatusOK, httpStatusCode)
+		})
+
+		AssertMaxQueries(t, statements, 2)
+		AssertNoRepeatedQueries(t, statements)
+	})))
+	t.Run("GetTasks", RunWithRecreateDB((func(t *testing.T) {
+		for i := 1; i <= QUERIES_PAGE_SIZE; i++ {
+			testHttpClient.CreateTask(utils.entityGenerators.GenerateTaskName(TEST_TASK_NAME_TEMPLATE, i), entities.TASK_STATE_NEW)
+		}
+
+		statements := CaptureQueries(func() {
+			httpStatusCode, _, _ := testHttpClient.GetTasks(QUERIES_PAGE_SIZE, 0)
+			assert.Equal(t, http.StatusOK, httpStatusCode)
+		})
+
+		AssertMaxQueries(t, statements, 2)
+		AssertNoRepeatedQueries(t, statements)
+	})))
+	t.Run("GetUsers", RunWithRecreateDB((func(t *testing.T) {
+		for i := 1; i <= QUERIES_PAGE_SIZE; i++ {
+			user := utils.entityGenerators.GenerateUser(i)
+			testHttpClient.CreateUser(user.Login, user.Email, user.Password, user.Role, user.State)
+		}
+
+		statements := CaptureQueries(func() {
+			httpStatusCode, _, _ := testHttpClient.GetUsers(QUERIES_PAGE_SIZE, 0)
+			assert.Equal(t, http.StatusOK, httpStatusCode)
+		})
+
+		AssertMaxQueries(t, statements, 2)
+		AssertNoRepeatedQueries(t, statements)
+	})))
+	t.Run("GetNote", RunWithRecreateDB((func(t *testing.T) {
+		testHttpClient.CreateNote(TEST_NOTE_TEXT_1, TEST_NOTE_TOPIC_1, TEST_NOTE_TAG_ID_1, TEST_NOTE_USER_ID_1, TEST_NOTE_STATE_1)
+
+		statements := CaptureQueries(func() {
+			httpStatusCode, _ := testHttpClient.GetNote("1")
+			assert.Equal(t, http.StatusOK, httpStatusCode)
+		})
+
+		AssertMaxQueries(t, statements, 1)
+	})))
+	t.Run("CreateNote", RunWithRecreateDB((func(t *testing.T) {
+		statements := CaptureQueries(func() {
+			httpStatusCode, _, _ := testHttpClient.CreateNote(TEST_NOTE_TEXT_1, TEST_NOTE_TOPIC_1, TEST_NOTE_TAG_ID_1, TEST_NOTE_USER_ID_1, TEST_NOTE_STATE_1)
+			assert.Equal(t, http.StatusCreated, httpStatusCode)
+		})
+
+		AssertMaxQueries(t, statements, 1)
+	})))
+}
+
+func TestDBQueriesCount(t *testing.T) {
+	SkipWithoutDB(t)
+
+	t.Run("GetNotes", RunWithRecreateDB((func(t *testing.T) {
+		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+			return CreateNotesInDB(t, tx, ctx, QUERIES_PAGE_SIZE, TEST_NOTE_TEXT_TEMPLATE, TEST_NOTE_TOPIC_TEMPLATE, TEST_NOTE_TAG_ID_1, TEST_NOTE_USER_ID_1, TEST_NOTE_STATE_1)
+		})()
+
+		err, statements := TxVoidWithQueries(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+			notes, err := queries.GetNotes(tx, ctx, QUERIES_PAGE_SIZE, 0)
+
+			assert.Equal(t, QUERIES_PAGE_SIZE, len(notes))
+			return err
+		})
+
+		assert.Nil(t, err)
+		AssertMaxQueries(t, statements, 1)
+	})))
+}
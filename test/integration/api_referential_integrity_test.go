@@ -0,0 +1,350 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ArtemVoronov/indefinite-studies-api/internal/db"
+	"github.com/ArtemVoronov/indefinite-studies-api/internal/db/entities"
+	"github.com/ArtemVoronov/indefinite-studies-api/internal/db/queries"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	REFERENCE_EXISTING string = "existing"
+	REFERENCE_MISSING  string = "missing"
+	REFERENCE_DELETED  string = "deleted"
+	REFERENCE_BLOCKED  string = "blocked"
+
+	// Id that is never created by the cases
+	REFERENCE_MISSING_ID int = 1000
+
+	// Notes are not validated against tags and users, and the schema has no foreign keys
+	KNOWN_GAP_DANGLING_REFERENCE string = "a note may reference a tag or a user that does not exist or is not active"
+	// Deleting a tag or a user leaves its notes untouched
+	KNOWN_GAP_NO_CASCADE string = "notes of a deleted tag or user stay visible and keep the reference"
+)
+
+type NoteReferenceCase struct {
+	Name string
+	Tag  string
+	User string
+
+	ExpectedHttpStatusCode int
+	// The schema is expected to reject the note, e.g. by a foreign key
+	ExpectedQueryFailure bool
+	// Non empty when the API does not behave as expected yet, see KnownGapT
+	KnownGap string
+}
+
+func CreateNoteReferenceCases() []NoteReferenceCase {
+	return []NoteReferenceCase{
+		{Name: "ExistingTagAndUser", Tag: REFERENCE_EXISTING, User: REFERENCE_EXISTING, ExpectedHttpStatusCode: http.StatusCreated},
+		{Name: "MissingTag", Tag: REFERENCE_MISSING, User: REFERENCE_EXISTING, ExpectedHttpStatusCode: http.StatusBadRequest, ExpectedQueryFailure: true, KnownGap: KNOWN_GAP_DANGLING_REFERENCE},
+		{Name: "MissingUser", Tag: REFERENCE_EXISTING, User: REFERENCE_MISSING, ExpectedHttpStatusCode: http.StatusBadRequest, ExpectedQueryFailure: true, KnownGap: KNOWN_GAP_DANGLING_REFERENCE},
+		{Name: "MissingTagAndUser", Tag: REFERENCE_MISSING, User: REFERENCE_MISSING, ExpectedHttpStatusCode: http.StatusBadRequest, ExpectedQueryFailure: true, KnownGap: KNOWN_GAP_DANGLING_REFERENCE},
+		{Name: "DeletedTag", Tag: REFERENCE_DELETED, User: REFERENCE_EXISTING, ExpectedHttpStatusCode: http.StatusBadRequest, ExpectedQueryFailure: true, KnownGap: KNOWN_GAP_DANGLING_REFERENCE},
+		{Name: "DeletedUser", Tag: REFERENCE_EXISTING, User: REFERENCE_DELETED, ExpectedHttpStatusCode: http.StatusBadRequest, ExpectedQueryFailure: true, KnownGap: KNOWN_GAP_DANGLING_REFERENCE},
+		{Name: "BlockedTag", Tag: REFERENCE_BLOCKED, User: REFERENCE_EXISTING, ExpectedHttpStatusCode: http.StatusBadRequest, ExpectedQueryFailure: true, KnownGap: KNOWN_GAP_DANGLING_REFERENCE},
+		{Name: "BlockedUser", Tag: REFERENCE_EXISTING, User: REFERENCE_BLOCKED, ExpectedHttpStatusCode: http.StatusBadRequest, ExpectedQueryFailure: true, KnownGap: KNOWN_GAP_DANGLING_REFERENCE},
+	}
+}
+
+type ParentDeleteCase struct {
+	Name string
+	// "tag" or "user"
+	Parent string
+	// Notes of the parent, the ones with true are deleted before the parent
+	Notes []bool
+
+	ExpectedHttpStatusCode int
+	// States of the notes after the parent is deleted
+	ExpectedNoteStates []string
+	KnownGap           string
+}
+
+func CreateParentDeleteCases() []ParentDeleteCase {
+	return []ParentDeleteCase{
+		{
+			Name: "TagWithNotes", Parent: "tag", Notes: []bool{false, false},
+			ExpectedHttpStatusCode: http.StatusOK,
+			ExpectedNoteStates:     []string{entities.NOTE_STATE_DELETED, entities.NOTE_STATE_DELETED},
+			KnownGap:               KNOWN_GAP_NO_CASCADE,
+		},
+		{
+			Name: "UserWithNotes", Parent: "user", Notes: []bool{false, false},
+			ExpectedHttpStatusCode: http.StatusOK,
+			ExpectedNoteStates:     []string{entities.NOTE_STATE_DELETED, entities.NOTE_STATE_DELETED},
+			KnownGap:               KNOWN_GAP_NO_CASCADE,
+		},
+		{
+			Name: "TagWithDeletedNote", Parent: "tag", Notes: []bool{true, false},
+			ExpectedHttpStatusCode: http.StatusOK,
+			ExpectedNoteStates:     []string{entities.NOTE_STATE_DELETED, entities.NOTE_STATE_DELETED},
+			KnownGap:               KNOWN_GAP_NO_CASCADE,
+		},
+		{
+			Name: "UserWithDeletedNote", Parent: "user", Notes: []bool{true, false},
+			ExpectedHttpStatusCode: http.StatusOK,
+			ExpectedNoteStates:     []string{entities.NOTE_STATE_DELETED, entities.NOTE_STATE_DELETED},
+			KnownGap:               KNOWN_GAP_NO_CASCADE,
+		},
+	}
+}
+
+func PrepareTagReference(t *testing.T, kind string) int {
+	if kind == REFERENCE_MISSING {
+		return REFERENCE_MISSING_ID
+	}
+	state := entities.TAG_STATE_NEW
+	if kind == REFERENCE_BLOCKED {
+		state = entities.TAG_STATE_BLOCKED
+	}
+	result, err := db.Tx(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+		return CreateTagInDB(t, tx, ctx, TEST_TAG_NAME_1, state)
+	})()
+	assert.Nil(t, err)
+	tagId, _ := result.(int)
+
+	if kind == REFERENCE_DELETED {
+		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+			err := queries.DeleteTag(tx, ctx, tagId)
+
+			assert.Nil(t, err)
+			return err
+		})()
+	}
+	return tagId
+}
+
+func PrepareUserReference(t *testing.T, kind string) int {
+	if kind == REFERENCE_MISSING {
+		return REFERENCE_MISSING_ID
+	}
+	state := entities.USER_STATE_NEW
+	if kind == REFERENCE_BLOCKED {
+		state = entities.USER_STATE_BLOCKED
+	}
+	result, err := db.Tx(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+		return CreateUserInDB(t, tx, ctx, TEST_USER_LOGIN_1, TEST_USER_EMAIL_1, TEST_USER_PASSWORD_1, TEST_USER_ROLE_1, state)
+	})()
+	assert.Nil(t, err)
+	userId, _ := result.(int)
+
+	if kind == REFERENCE_DELETED {
+		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+			err := queries.DeleteUser(tx, ctx, userId)
+
+			assert.Nil(t, err)
+			return err
+		})()
+	}
+	return userId
+}
+
+func GetStateInDB(t *testing.T, table string, id int) string {
+	var result string
+	db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+		err := tx.QueryRowContext(ctx, "SELECT state FROM "+table+" WHERE id = $1", id).Scan(&result)
+
+		assert.Nil(t, err)
+		return err
+	})()
+	return result
+}
+
+// Expected failure marker: the assertions of a case with a known gap are collected instead of failing the test.
+// The test is skipped while the gap is reproduced and fails once it is not, so the marker can be removed.
+// Assertions about the setup keep using *testing.T, they are never part of the gap.
+type KnownGapT struct {
+	t        *testing.T
+	gap      string
+	failures []string
+}
+
+func ExpectKnownGap(t *testing.T, gap string) *KnownGapT {
+	return &KnownGapT{t: t, gap: gap}
+}
+
+func (p *KnownGapT) Errorf(format string, args ...interface{}) {
+	if p.gap == "" {
+		p.t.Errorf(format, args...)
+		return
+	}
+	p.failures = append(p.failures, fmt.Sprintf(format, args...))
+}
+
+func (p *KnownGapT) Done() {
+	if p.gap == "" || p.t.Failed() {
+		return
+	}
+	if len(p.failures) == 0 {
+		p.t.Errorf("known gap is not reproduced anymore, remove the marker: %s", p.gap)
+		return
+	}
+	p.t.Skipf("known gap: %s\n%s", p.gap, strings.Join(p.failures, "\n"))
+}
+
+func TestApiNoteReferences(t *testing.T) {
+	SkipWithoutDB(t)
+
+	for _, c := range CreateNoteReferenceCases() {
+		c := c
+		t.Run(c.Name+": HTTP", RunWithRecreateDB((func(t *testing.T) {
+			tagId := PrepareTagReference(t, c.Tag)
+			userId := PrepareUserReference(t, c.User)
+			gap := ExpectKnownGap(t, c.KnownGap)
+			defer gap.Done()
+
+			httpStatusCode, body, _ := testHttpClient.CreateNote(TEST_NOTE_TEXT_1, TEST_NOTE_TOPIC_1, tagId, userId, TEST_NOTE_STATE_1)
+
+			assert.Equal(gap, c.ExpectedHttpStatusCode, httpStatusCode, body)
+			if c.ExpectedHttpStatusCode != http.StatusCreated {
+				assert.Equal(gap, 0, CountRowsInDB(t, "notes", "text", TEST_NOTE_TEXT_1))
+				return
+			}
+			noteId, err := strconv.Atoi(body)
+			assert.Nil(t, err)
+
+			httpStatusCode, body = testHttpClient.GetNote(body)
+
+			assert.Equal(t, http.StatusOK, httpStatusCode)
+			assert.Equal(t, TEST_NOTE_STATE_1, GetStateInDB(t, "notes", noteId))
+			assert.Contains(t, body, "\"TagId\":"+strconv.Itoa(tagId)+",")
+			assert.Contains(t, body, "\"UserId\":"+strconv.Itoa(userId)+",")
+		})))
+		t.Run(c.Name+": Queries", RunWithRecreateDB((func(t *testing.T) {
+			tagId := PrepareTagReference(t, c.Tag)
+			userId := PrepareUserReference(t, c.User)
+			gap := ExpectKnownGap(t, c.KnownGap)
+			defer gap.Done()
+
+			var noteId int
+			db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+				var err error
+				noteId, err = queries.CreateNote(tx, ctx, TEST_NOTE_TEXT_1, TEST_NOTE_TOPIC_1, tagId, userId, TEST_NOTE_STATE_1)
+
+				if c.ExpectedQueryFailure {
+					assert.NotNil(gap, err)
+				} else {
+					assert.Nil(t, err)
+				}
+				return err
+			})()
+			if c.ExpectedQueryFailure {
+				assert.Equal(gap, 0, CountRowsInDB(t, "notes", "text", TEST_NOTE_TEXT_1))
+				return
+			}
+
+			db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+				note, err := queries.GetNote(tx, ctx, noteId)
+
+				assert.Nil(t, err)
+				assert.Equal(t, tagId, note.TagId)
+				assert.Equal(t, userId, note.UserId)
+				return err
+			})()
+		})))
+	}
+}
+
+// Creates the tag, the user and the notes, returns the id of the parent and the ids of the notes
+func PrepareParentWithNotes(t *testing.T, c ParentDeleteCase) (int, []int) {
+	tagId := PrepareTagReference(t, REFERENCE_EXISTING)
+	userId := PrepareUserReference(t, REFERENCE_EXISTING)
+
+	var noteIds []int
+	for i, deleted := range c.Notes {
+		var noteId int
+		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+			var err error
+			text := utils.entityGenerators.GenerateNoteText(TEST_NOTE_TEXT_TEMPLATE, i+1)
+			topic := utils.entityGenerators.GenerateNoteTopic(TEST_NOTE_TOPIC_TEMPLATE, i+1)
+			noteId, err = queries.CreateNote(tx, ctx, text, topic, tagId, userId, TEST_NOTE_STATE_1)
+
+			assert.Nil(t, err)
+			return err
+		})()
+		if deleted {
+			db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+				err := queries.DeleteNote(tx, ctx, noteId)
+
+				assert.Nil(t, err)
+				return err
+			})()
+		}
+		noteIds = append(noteIds, noteId)
+	}
+
+	if c.Parent == "user" {
+		return userId, noteIds
+	}
+	return tagId, noteIds
+}
+
+func AssertNoteStatesInDB(t *testing.T, a assert.TestingT, noteIds []int, expected []string) {
+	var actual []string
+	for _, noteId := range noteIds {
+		actual = append(actual, GetStateInDB(t, "notes", noteId))
+	}
+	assert.Equal(a, expected, actual)
+}
+
+func TestApiParentDelete(t *testing.T) {
+	SkipWithoutDB(t)
+
+	for _, c := range CreateParentDeleteCases() {
+		c := c
+		t.Run(c.Name+": HTTP", RunWithRecreateDB((func(t *testing.T) {
+			parentId, noteIds := PrepareParentWithNotes(t, c)
+			gap := ExpectKnownGap(t, c.KnownGap)
+			defer gap.Done()
+
+			var httpStatusCode int
+			var body string
+			if c.Parent == "user" {
+				httpStatusCode, body, _ = testHttpClient.DeleteUser(parentId)
+			} else {
+				httpStatusCode, body, _ = testHttpClient.DeleteTag(parentId)
+			}
+
+			assert.Equal(t, c.ExpectedHttpStatusCode, httpStatusCode, body)
+			AssertNoteStatesInDB(t, gap, noteIds, c.ExpectedNoteStates)
+			for i, noteId := range noteIds {
+				httpStatusCode, _ = testHttpClient.GetNote(strconv.Itoa(noteId))
+
+				if c.ExpectedNoteStates[i] == entities.NOTE_STATE_DELETED {
+					assert.Equal(gap, http.StatusNotFound, httpStatusCode)
+				} else {
+					assert.Equal(gap, http.StatusOK, httpStatusCode)
+				}
+			}
+		})))
+		t.Run(c.Name+": Queries", RunWithRecreateDB((func(t *testing.T) {
+			parentId, noteIds := PrepareParentWithNotes(t, c)
+			gap := ExpectKnownGap(t, c.KnownGap)
+			defer gap.Done()
+
+			db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+				var err error
+				if c.Parent == "user" {
+					err = queries.DeleteUser(tx, ctx, parentId)
+				} else {
+					err = queries.DeleteTag(tx, ctx, parentId)
+				}
+
+				assert.Nil(t, err)
+				return err
+			})()
+			AssertNoteStatesInDB(t, gap, noteIds, c.ExpectedNoteStates)
+		})))
+	}
+}
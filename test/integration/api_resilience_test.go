@@ -0,0 +1,74 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/faultproxy"
+	"github.com/stretchr/testify/assert"
+)
+
+type ResilienceCase struct {
+	Name  string
+	Fault faultproxy.Fault
+	// Latency alone must not break the requests
+	ExpectSuccess bool
+}
+
+func CreateResilienceCases() []ResilienceCase {
+	return []ResilienceCase{
+		{Name: "Latency", Fault: faultproxy.Fault{Latency: 20 * time.Millisecond}, ExpectSuccess: true},
+		{Name: "RefusedConnections", Fault: faultproxy.Fault{Refuse: true}},
+		{Name: "DroppedMidQuery", Fault: faultproxy.Fault{DropOnQuery: true}},
+		{Name: "CorruptedPackets", Fault: faultproxy.Fault{CorruptRate: 1}},
+	}
+}
+
+// Creates a note while the fault is active, then checks the response, that nothing was written, and that the API recovers
+func TestApiResilience(t *testing.T) {
+	SkipWithoutFaultProxy(t)
+
+	for _, c := range CreateResilienceCases() {
+		c := c
+		t.Run(c.Name, RunWithRecreateDB((func(t *testing.T) {
+			defer ClearDBFault()
+
+			InjectDBFault(c.Fault)
+			httpStatusCode, body, _ := testHttpClient.CreateNote(TEST_NOTE_TEXT_1, TEST_NOTE_TOPIC_1, TEST_NOTE_TAG_ID_1, TEST_NOTE_USER_ID_1, TEST_NOTE_STATE_1)
+			ClearDBFault()
+
+			if c.ExpectSuccess {
+				assert.Equal(t, http.StatusCreated, httpStatusCode, body)
+				assert.Equal(t, 1, CountRowsInDB(t, "notes", "text", TEST_NOTE_TEXT_1))
+				return
+			}
+			AssertServerError(t, httpStatusCode, body)
+			assert.Equal(t, 0, CountRowsInDB(t, "notes", "text", TEST_NOTE_TEXT_1), "partial write during %s", c.Name)
+
+			httpStatusCode, body, _ = testHttpClient.CreateNote(TEST_NOTE_TEXT_2, TEST_NOTE_TOPIC_2, TEST_NOTE_TAG_ID_1, TEST_NOTE_USER_ID_1, TEST_NOTE_STATE_1)
+
+			assert.Equal(t, http.StatusCreated, httpStatusCode, "no recovery after %s: %s", c.Name, body)
+			assert.Equal(t, 1, CountRowsInDB(t, "notes", "text", TEST_NOTE_TEXT_2))
+		})))
+	}
+
+	t.Run("ReadsDuringOutage", RunWithRecreateDB((func(t *testing.T) {
+		defer ClearDBFault()
+		httpStatusCode, body, _ := testHttpClient.CreateTag(TEST_TAG_NAME_1, TEST_TAG_STATE_1)
+		assert.Equal(t, http.StatusCreated, httpStatusCode, body)
+
+		InjectDBFault(faultproxy.Fault{Refuse: true})
+		httpStatusCode, body = testHttpClient.GetTag(body)
+		AssertServerError(t, httpStatusCode, body)
+		httpStatusCode, body, _ = testHttpClient.GetTags(nil, nil)
+		AssertServerError(t, httpStatusCode, body)
+		ClearDBFault()
+
+		httpStatusCode, body, _ = testHttpClient.GetTags(nil, nil)
+		assert.Equal(t, http.StatusOK, httpStatusCode, body)
+	})))
+}
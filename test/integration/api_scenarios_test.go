@@ -0,0 +1,85 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/ArtemVoronov/indefinite-studies-api/internal/api"
+	"github.com/ArtemVoronov/indefinite-studies-api/internal/db/entities"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/dbsnapshot"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/scenario"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/sqltrace"
+	"github.com/stretchr/testify/assert"
+)
+
+func GetScenarioDir() string {
+	if dir, ok := os.LookupEnv("QA_SCENARIO_DIR"); ok {
+		return dir
+	}
+	return GetRootPath() + "/test/scenarios"
+}
+
+// Constants of the API the scenario files refer to as ${NAME}
+func CreateScenarioVars() map[string]any {
+	return map[string]any{
+		"PAGE_NOT_FOUND":        api.PAGE_NOT_FOUND,
+		"ERROR_ID_WRONG_FORMAT": api.ERROR_ID_WRONG_FORMAT,
+		"DONE":                  api.DONE,
+		"DUPLICATE_FOUND":       api.DUPLICATE_FOUND,
+		"TASK_STATE_NEW":        entities.TASK_STATE_NEW,
+		"TASK_STATE_DONE":       entities.TASK_STATE_DONE,
+		"TAG_STATE_NEW":         entities.TAG_STATE_NEW,
+		"TAG_STATE_BLOCKED":     entities.TAG_STATE_BLOCKED,
+		"USER_STATE_NEW":        entities.USER_STATE_NEW,
+		"USER_STATE_BLOCKED":    entities.USER_STATE_BLOCKED,
+		"USER_ROLE_OWNER":       entities.USER_ROLE_OWNER,
+		"USER_ROLE_RESIDENT":    entities.USER_ROLE_RESIDENT,
+		"NOTE_STATE_NEW":        entities.NOTE_STATE_NEW,
+		"NOTE_STATE_BLOCKED":    entities.NOTE_STATE_BLOCKED,
+	}
+}
+
+// Runs every scenario of test/scenarios as a subtest with a subtest per step,
+// the steps after the first failed one are skipped
+func TestApiScenarios(t *testing.T) {
+	scenarios, err := scenario.LoadAll(GetScenarioDir())
+	assert.Nil(t, err)
+	if len(scenarios) == 0 {
+		t.Skipf("no scenarios in '%s'", GetScenarioDir())
+	}
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.Name, RunWithRecreateDB((func(t *testing.T) {
+			var queryer dbsnapshot.Queryer
+			if HasDB() {
+				queryer = HarnessDB()
+			} else if s.UsesDB() {
+				t.Skip("the scenario checks rows, the DB is not available while replaying cassettes or testing the stub")
+			}
+			run := scenario.NewRun(s, &testHttpClient, queryer)
+			for name, value := range CreateScenarioVars() {
+				run.Set(name, value)
+			}
+
+			ctx := sqltrace.Untraced(context.Background())
+			failed := false
+			for _, step := range s.Steps {
+				step := step
+				t.Run(step.Title(), func(t *testing.T) {
+					if failed {
+						t.Skip("a previous step failed")
+					}
+					for _, failure := range run.Step(ctx, step) {
+						t.Error(failure)
+					}
+					failed = t.Failed()
+				})
+			}
+		})))
+	}
+}
@@ -0,0 +1,20 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/stub"
+	"github.com/stretchr/testify/assert"
+)
+
+// `qa stub` has no access to the API and starts with the hard-coded defaults,
+// they must stay the same as the constants the suite passes to the stub
+func TestStubDefaults(t *testing.T) {
+	expected := CreateStubConfig()
+
+	assert.Equal(t, expected.Messages, stub.DefaultMessages())
+	assert.Equal(t, expected.Enums, stub.DefaultEnums())
+}
@@ -0,0 +1,96 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"strconv"
+	"testing"
+
+	"github.com/ArtemVoronov/indefinite-studies-api/internal/db/queries"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/explain"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/seed"
+)
+
+type ExplainCase struct {
+	Name       string
+	Thresholds explain.Thresholds
+	Query      func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error
+}
+
+func CreateExplainCases() []ExplainCase {
+	firstPage := explain.Thresholds{MaxTotalCost: 1000}
+	middlePage := explain.Thresholds{MaxTotalCost: 5000}
+	return []ExplainCase{
+		{Name: "GetNotes: first page", Thresholds: firstPage, Query: func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+			_, err := queries.GetNotes(tx, ctx, 50, 0)
+			return err
+		}},
+		{Name: "GetNotes: page at offset 1000", Thresholds: middlePage, Query: func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+			_, err := queries.GetNotes(tx, ctx, 50, 1000)
+			return err
+		}},
+		{Name: "GetTags: first page", Thresholds: firstPage, Query: func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+			_, err := queries.GetTags(tx, ctx, 50, 0)
+			return err
+		}},
+		{Name: "GetTags: page at offset 1000", Thresholds: middlePage, Query: func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+			_, err := queries.GetTags(tx, ctx, 50, 1000)
+			return err
+		}},
+		{Name: "GetTasks: first page", Thresholds: firstPage, Query: func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+			_, err := queries.GetTasks(tx, ctx, 50, 0)
+			return err
+		}},
+		{Name: "GetTasks: page at offset 1000", Thresholds: middlePage, Query: func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+			_, err := queries.GetTasks(tx, ctx, 50, 1000)
+			return err
+		}},
+		{Name: "GetUsers: first page", Thresholds: firstPage, Query: func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+			_, err := queries.GetUsers(tx, ctx, 50, 0)
+			return err
+		}},
+		{Name: "GetUsers: page at offset 1000", Thresholds: middlePage, Query: func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+			_, err := queries.GetUsers(tx, ctx, 50, 1000)
+			return err
+		}},
+	}
+}
+
+func TestDBExplainListQueries(t *testing.T) {
+	SkipWithoutDB(t)
+
+	t.Run("LargeVolume", RunWithRecreateDB((func(t *testing.T) {
+		rowsCount := GetEnvInt(t, "QA_EXPLAIN_ROWS_COUNT", EXPLAIN_DEFAULT_ROWS_COUNT)
+		config := seed.DefaultConfig()
+		config.Users = rowsCount
+		config.Tags = rowsCount
+		config.Tasks = rowsCount
+		config.NotesPerUser = seed.Fixed{Value: 1}
+		SeedInDB(t, config)
+
+		var results []explain.Result
+		for _, c := range CreateExplainCases() {
+			for i, plan := range ExplainCaptured(t, c.Query) {
+				name := c.Name
+				if i > 0 {
+					name += " #" + strconv.Itoa(i+1)
+				}
+				results = append(results, explain.Result{Name: name, Plan: plan, Violations: plan.Check(c.Thresholds)})
+			}
+		}
+
+		var report bytes.Buffer
+		explain.WriteReport(&report, results)
+		for _, r := range results {
+			if len(r.Violations) > 0 {
+				t.Errorf("query plans violate the thresholds:\n%s", report.String())
+				return
+			}
+		}
+		t.Logf("query plans:\n%s", report.String())
+	})))
+}
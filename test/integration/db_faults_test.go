@@ -0,0 +1,211 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/ArtemVoronov/indefinite-studies-api/internal/db/queries"
+)
+
+// Every query function with its arguments and the error it wraps the cause with
+func CreateQueryFaultCases() []QueryFaultCase {
+	return []QueryFaultCase{
+		{
+			Name:                "GetTask",
+			ExpectedErrorPrefix: fmt.Sprintf("error at loading task by id '%d' from db, case after QueryRow.Scan", 1),
+			Call: func(tx *sql.Tx, ctx context.Context) error {
+				_, err := queries.GetTask(tx, ctx, 1)
+				return err
+			},
+		},
+		{
+			Name:                "GetTasks",
+			ExpectedErrorPrefix: "error at loading tasks from db, case after Query",
+			Call: func(tx *sql.Tx, ctx context.Context) error {
+				_, err := queries.GetTasks(tx, ctx, 50, 0)
+				return err
+			},
+		},
+		{
+			Name:                "CreateTask",
+			ExpectedErrorPrefix: fmt.Sprintf("error at inserting task (Name: '%s', State: '%s') into db, case after QueryRow.Scan", TEST_TASK_NAME_1, TEST_TASK_STATE_1),
+			Call: func(tx *sql.Tx, ctx context.Context) error {
+				_, err := queries.CreateTask(tx, ctx, TEST_TASK_NAME_1, TEST_TASK_STATE_1)
+				return err
+			},
+		},
+		{
+			Name:                "UpdateTask",
+			ExpectedErrorPrefix: "error at updating task, case after preparing statement",
+			Call: func(tx *sql.Tx, ctx context.Context) error {
+				return queries.UpdateTask(tx, ctx, 1, TEST_TASK_NAME_1, TEST_TASK_STATE_1)
+			},
+		},
+		{
+			Name:                "DeleteTask",
+			ExpectedErrorPrefix: "error at deleting task, case after preparing statement",
+			Call: func(tx *sql.Tx, ctx context.Context) error {
+				return queries.DeleteTask(tx, ctx, 1)
+			},
+		},
+		{
+			Name:                "GetTag",
+			ExpectedErrorPrefix: fmt.Sprintf("error at loading tag by id '%d' from db, case after QueryRow.Scan", 1),
+			Call: func(tx *sql.Tx, ctx context.Context) error {
+				_, err := queries.GetTag(tx, ctx, 1)
+				return err
+			},
+		},
+		{
+			Name:                "GetTags",
+			ExpectedErrorPrefix: "error at loading tags from db, case after Query",
+			Call: func(tx *sql.Tx, ctx context.Context) error {
+				_, err := queries.GetTags(tx, ctx, 50, 0)
+				return err
+			},
+		},
+		{
+			Name:                "CreateTag",
+			ExpectedErrorPrefix: fmt.Sprintf("error at inserting tag (Name: '%s', State: '%s') into db, case after QueryRow.Scan", TEST_TAG_NAME_1, TEST_TAG_STATE_1),
+			Call: func(tx *sql.Tx, ctx context.Context) error {
+				_, err := queries.CreateTag(tx, ctx, TEST_TAG_NAME_1, TEST_TAG_STATE_1)
+				return err
+			},
+		},
+		{
+			Name:                "UpdateTag",
+			ExpectedErrorPrefix: "error at updating tag, case after preparing statement",
+			Call: func(tx *sql.Tx, ctx context.Context) error {
+				return queries.UpdateTag(tx, ctx, 1, TEST_TAG_NAME_1, TEST_TAG_STATE_1)
+			},
+		},
+		{
+			Name:                "DeleteTag",
+			ExpectedErrorPrefix: "error at deleting tag, case after preparing statement",
+			Call: func(tx *sql.Tx, ctx context.Context) error {
+				return queries.DeleteTag(tx, ctx, 1)
+			},
+		},
+		{
+			Name:                "GetUser",
+			ExpectedErrorPrefix: fmt.Sprintf("error at loading user by id '%d' from db, case after QueryRow.Scan", 1),
+			Call: func(tx *sql.Tx, ctx context.Context) error {
+				_, err := queries.GetUser(tx, ctx, 1)
+				return err
+			},
+		},
+		{
+			Name:                "GetUsers",
+			ExpectedErrorPrefix: "error at loading users from db, case after Query",
+			Call: func(tx *sql.Tx, ctx context.Context) error {
+				_, err := queries.GetUsers(tx, ctx, 50, 0)
+				return err
+			},
+		},
+		{
+			Name:                "CreateUser",
+			ExpectedErrorPrefix: fmt.Sprintf("error at inserting user (Login: '%s', Email: '%s') into db, case after QueryRow.Scan", TEST_USER_LOGIN_1, TEST_USER_EMAIL_1),
+			Call: func(tx *sql.Tx, ctx context.Context) error {
+				_, err := queries.CreateUser(tx, ctx, TEST_USER_LOGIN_1, TEST_USER_EMAIL_1, TEST_USER_PASSWORD_1, TEST_USER_ROLE_1, TEST_USER_STATE_1)
+				return err
+			},
+		},
+		{
+			Name:                "UpdateUser",
+			ExpectedErrorPrefix: "error at updating user, case after preparing statement",
+			Call: func(tx *sql.Tx, ctx context.Context) error {
+				return queries.UpdateUser(tx, ctx, 1, TEST_USER_LOGIN_1, TEST_USER_EMAIL_1, TEST_USER_PASSWORD_1, TEST_USER_ROLE_1, TEST_USER_STATE_1)
+			},
+		},
+		{
+			Name:                "DeleteUser",
+			ExpectedErrorPrefix: "error at deleting user, case after preparing statement",
+			Call: func(tx *sql.Tx, ctx context.Context) error {
+				return queries.DeleteUser(tx, ctx, 1)
+			},
+		},
+		{
+			Name:                "GetNote",
+			ExpectedErrorPrefix: fmt.Sprintf("error at loading note by id '%d' from db, case after QueryRow.Scan", 1),
+			Call: func(tx *sql.Tx, ctx context.Context) error {
+				_, err := queries.GetNote(tx, ctx, 1)
+				return err
+			},
+		},
+		{
+			Name:                "GetNotes",
+			ExpectedErrorPrefix: "error at loading note from db, case after Query",
+			Call: func(tx *sql.Tx, ctx context.Context) error {
+				_, err := queries.GetNotes(tx, ctx, 50, 0)
+				return err
+			},
+		},
+		{
+			Name:                "CreateNote",
+			ExpectedErrorPrefix: fmt.Sprintf("error at inserting note (Topic: '%s', UserId: '%d') into db, case after QueryRow.Scan", TEST_NOTE_TOPIC_1, 1),
+			Call: func(tx *sql.Tx, ctx context.Context) error {
+				_, err := queries.CreateNote(tx, ctx, TEST_NOTE_TEXT_1, TEST_NOTE_TOPIC_1, 1, 1, TEST_NOTE_STATE_1)
+				return err
+			},
+		},
+		{
+			Name:                "UpdateNote",
+			ExpectedErrorPrefix: "error at updating note, case after preparing statement",
+			Call: func(tx *sql.Tx, ctx context.Context) error {
+				return queries.UpdateNote(tx, ctx, 1, TEST_NOTE_TEXT_1, TEST_NOTE_TOPIC_1, 1, 1, TEST_NOTE_STATE_1)
+			},
+		},
+		{
+			Name:                "DeleteNote",
+			ExpectedErrorPrefix: "error at deleting note, case after preparing statement",
+			Call: func(tx *sql.Tx, ctx context.Context) error {
+				return queries.DeleteNote(tx, ctx, 1)
+			},
+		},
+		{
+			Name:                "GetRefreshTokenByToken",
+			ExpectedErrorPrefix: fmt.Sprintf("error at loading refresh token '%s' from db, case after QueryRow.Scan", TEST_REFRESH_TOKEN_1),
+			Call: func(tx *sql.Tx, ctx context.Context) error {
+				_, err := queries.GetRefreshTokenByToken(tx, ctx, TEST_REFRESH_TOKEN_1)
+				return err
+			},
+		},
+		{
+			Name:                "CreateRefreshToken",
+			ExpectedErrorPrefix: "error at creating refresh token, case after preparing statement",
+			Call: func(tx *sql.Tx, ctx context.Context) error {
+				return queries.CreateRefreshToken(tx, ctx, TEST_REFRESH_TOKEN_USER_ID_1, TEST_REFRESH_TOKEN_1, TEST_REFRESH_TOKEN_EXPIRE_AT_1)
+			},
+		},
+		{
+			Name:                "UpdateRefreshToken",
+			ExpectedErrorPrefix: "error at updating refresh token, case after preparing statement",
+			Call: func(tx *sql.Tx, ctx context.Context) error {
+				return queries.UpdateRefreshToken(tx, ctx, TEST_REFRESH_TOKEN_USER_ID_1, TEST_REFRESH_TOKEN_2, TEST_REFRESH_TOKEN_EXPIRE_AT_2)
+			},
+		},
+		{
+			Name:                "DeleteRefreshToken",
+			ExpectedErrorPrefix: "error at deleting refresh token, case after preparing statement",
+			Call: func(tx *sql.Tx, ctx context.Context) error {
+				return queries.DeleteRefreshToken(tx, ctx, TEST_REFRESH_TOKEN_USER_ID_1)
+			},
+		},
+	}
+}
+
+func TestDBQueryFaults(t *testing.T) {
+	SkipWithoutDB(t)
+
+	for _, c := range CreateQueryFaultCases() {
+		c := c
+		t.Run(c.Name, RunWithRecreateDB((func(t *testing.T) {
+			RunQueryFaults(t, c)
+		})))
+	}
+}
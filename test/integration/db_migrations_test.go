@@ -0,0 +1,135 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/ArtemVoronov/indefinite-studies-api/internal/db/entities"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDBMigrations(t *testing.T) {
+	SkipWithoutDB(t)
+
+	runner := CreateLiquibaseRunner()
+
+	t.Run("UpAndDown", func(t *testing.T) {
+		initial, steps := ApplyMigrationsStepByStep(t, runner)
+		if len(steps) == 0 {
+			t.Fatalf("no changesets were applied")
+		}
+		t.Logf("applied %d changesets", len(steps))
+
+		for i := len(steps) - 1; i >= 0; i-- {
+			previous := initial
+			if i > 0 {
+				previous = steps[i-1].Schema
+			}
+			if !assert.Nil(t, runner.RollbackCount(1)) {
+				break
+			}
+
+			assert.Equal(t, i, len(GetAppliedChangesets(t)), "rollback of %s", steps[i].Changeset)
+			assert.Empty(t, schema.Compare(previous, TakeSchemaSnapshot(t)), "rollback of %s does not restore the schema", steps[i].Changeset)
+		}
+
+		// the schema at the latest version must not depend on whether it was built in one go or step by step
+		assert.Nil(t, runner.Update())
+		AssertSchemaEqual(t, steps[len(steps)-1].Schema, TakeSchemaSnapshot(t), "schema after a full update differs from the step by step one")
+	})
+
+	t.Run("SmokeAtLatestVersion", RunWithRecreateDB((func(t *testing.T) {
+		user := utils.entityGenerators.GenerateUser(1)
+		httpStatusCode, body, _ := testHttpClient.CreateUser(user.Login, user.Email, user.Password, user.Role, user.State)
+		assert.Equal(t, http.StatusCreated, httpStatusCode, body)
+		userId, _ := strconv.Atoi(body)
+
+		httpStatusCode, body, _ = testHttpClient.CreateTag(TEST_TAG_NAME_1, TEST_TAG_STATE_1)
+		assert.Equal(t, http.StatusCreated, httpStatusCode, body)
+		tagId, _ := strconv.Atoi(body)
+
+		for _, c := range CreateSmokeCases(tagId, userId) {
+			httpStatusCode, body, _ := c.Create()
+			if !assert.Equal(t, http.StatusCreated, httpStatusCode, "%s create: %s", c.Name, body) {
+				continue
+			}
+			id := body
+
+			httpStatusCode, body = c.Get(id)
+			assert.Equal(t, http.StatusOK, httpStatusCode, "%s get: %s", c.Name, body)
+
+			httpStatusCode, body, _ = c.Update(id)
+			assert.Equal(t, http.StatusOK, httpStatusCode, "%s update: %s", c.Name, body)
+
+			httpStatusCode, body, _ = c.Delete(id)
+			assert.Equal(t, http.StatusOK, httpStatusCode, "%s delete: %s", c.Name, body)
+
+			httpStatusCode, body = c.Get(id)
+			assert.Equal(t, http.StatusNotFound, httpStatusCode, "%s get deleted: %s", c.Name, body)
+		}
+
+		httpStatusCode, body, _ = testHttpClient.Authenicate(user.Email, user.Password)
+		assert.Equal(t, http.StatusOK, httpStatusCode, body)
+	})))
+}
+
+type SmokeCase struct {
+	Name   string
+	Create func() (int, string, error)
+	Get    func(id string) (int, string)
+	Update func(id string) (int, string, error)
+	Delete func(id any) (int, string, error)
+}
+
+// Create, get, update and delete of every resource, the tag and the user are used by the notes
+func CreateSmokeCases(tagId int, userId int) []SmokeCase {
+	return []SmokeCase{
+		{
+			Name:   "tag",
+			Create: func() (int, string, error) { return testHttpClient.CreateTag(TEST_TAG_NAME_2, TEST_TAG_STATE_1) },
+			Get:    testHttpClient.GetTag,
+			Update: func(id string) (int, string, error) {
+				return testHttpClient.UpdateTag(id, TEST_TAG_NAME_2, entities.TAG_STATE_BLOCKED)
+			},
+			Delete: testHttpClient.DeleteTag,
+		},
+		{
+			Name:   "task",
+			Create: func() (int, string, error) { return testHttpClient.CreateTask(TEST_TASK_NAME_1, TEST_TASK_STATE_1) },
+			Get:    testHttpClient.GetTask,
+			Update: func(id string) (int, string, error) {
+				return testHttpClient.UpdateTask(id, TEST_TASK_NAME_2, entities.TASK_STATE_DONE)
+			},
+			Delete: testHttpClient.DeleteTask,
+		},
+		{
+			Name: "user",
+			Create: func() (int, string, error) {
+				user := utils.entityGenerators.GenerateUser(2)
+				return testHttpClient.CreateUser(user.Login, user.Email, user.Password, user.Role, user.State)
+			},
+			Get: testHttpClient.GetUser,
+			Update: func(id string) (int, string, error) {
+				user := utils.entityGenerators.GenerateUser(3)
+				return testHttpClient.UpdateUser(id, user.Login, user.Email, user.Password, user.Role, entities.USER_STATE_BLOCKED)
+			},
+			Delete: testHttpClient.DeleteUser,
+		},
+		{
+			Name: "note",
+			Create: func() (int, string, error) {
+				return testHttpClient.CreateNote(TEST_NOTE_TEXT_1, TEST_NOTE_TOPIC_1, tagId, userId, TEST_NOTE_STATE_1)
+			},
+			Get: testHttpClient.GetNote,
+			Update: func(id string) (int, string, error) {
+				return testHttpClient.UpdateNote(id, TEST_NOTE_TEXT_2, TEST_NOTE_TOPIC_2, tagId, userId, entities.NOTE_STATE_BLOCKED)
+			},
+			Delete: testHttpClient.DeleteNote,
+		},
+	}
+}
@@ -6,7 +6,6 @@ package integration
 import (
 	"context"
 	"database/sql"
-	"fmt"
 	"testing"
 
 	"github.com/ArtemVoronov/indefinite-studies-api/internal/db"
@@ -16,6 +15,8 @@ import (
 )
 
 func TestDBNoteGet(t *testing.T) {
+	SkipWithoutDB(t)
+
 	t.Run("NotFoundCase", RunWithRecreateDB((func(t *testing.T) {
 		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 			_, err := queries.GetNote(tx, ctx, 1)
@@ -56,29 +57,11 @@ func TestDBNoteGet(t *testing.T) {
 			return err
 		})()
 	})))
-	t.Run("TimeoutError", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at loading note by id '%d' from db, case after QueryRow.Scan: %s", 1, "context deadline exceeded")
-			_, err := tx.ExecContext(ctx, "SELECT pg_sleep(10)")
-			_, err = queries.GetNote(tx, ctx, 1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
-	t.Run("ContextCancelled", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at loading note by id '%d' from db, case after QueryRow.Scan: %s", 1, "context canceled")
-			cancel()
-			_, err := queries.GetNote(tx, ctx, 1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
 }
 
 func TestDBNoteCreate(t *testing.T) {
+	SkipWithoutDB(t)
+
 	t.Run("BasicCase", RunWithRecreateDB((func(t *testing.T) {
 		result, _ := db.Tx(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
 			userId, err := CreateUserInDB(t, tx, ctx, TEST_USER_LOGIN_1, TEST_USER_EMAIL_1, TEST_USER_PASSWORD_1, TEST_USER_ROLE_1, TEST_USER_STATE_1)
@@ -104,29 +87,11 @@ func TestDBNoteCreate(t *testing.T) {
 			return err
 		})()
 	})))
-	t.Run("TimeoutError", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at inserting note (Topic: '%s', UserId: '%d') into db, case after QueryRow.Scan: %s", TEST_NOTE_TOPIC_1, 1, "context deadline exceeded")
-			_, err := tx.ExecContext(ctx, "SELECT pg_sleep(10)")
-			_, err = queries.CreateNote(tx, ctx, TEST_NOTE_TEXT_1, TEST_NOTE_TOPIC_1, 1, 1, TEST_NOTE_STATE_1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
-	t.Run("ContextCancelled", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at inserting note (Topic: '%s', UserId: '%d') into db, case after QueryRow.Scan: %s", TEST_NOTE_TOPIC_1, 1, "context canceled")
-			cancel()
-			_, err := queries.CreateNote(tx, ctx, TEST_NOTE_TEXT_1, TEST_NOTE_TOPIC_1, 1, 1, TEST_NOTE_STATE_1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
 }
 
 func TestDBNoteGetAll(t *testing.T) {
+	SkipWithoutDB(t)
+
 	t.Run("ExpectedEmpty", RunWithRecreateDB((func(t *testing.T) {
 		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 			notes, err := queries.GetNotes(tx, ctx, 50, 0)
@@ -235,29 +200,11 @@ func TestDBNoteGetAll(t *testing.T) {
 			return err
 		})()
 	})))
-	t.Run("TimeoutError", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at loading note from db, case after Query: %s", "context deadline exceeded")
-			_, err := tx.ExecContext(ctx, "SELECT pg_sleep(10)")
-			_, err = queries.GetNotes(tx, ctx, 50, 0)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
-	t.Run("ContextCancelled", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at loading note from db, case after Query: %s", "context canceled")
-			cancel()
-			_, err := queries.GetNotes(tx, ctx, 50, 0)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
 }
 
 func TestDBNoteUpdate(t *testing.T) {
+	SkipWithoutDB(t)
+
 	t.Run("NotFoundCase", RunWithRecreateDB((func(t *testing.T) {
 		result, err := db.Tx(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
 			userId, err := CreateUserInDB(t, tx, ctx, TEST_USER_LOGIN_1, TEST_USER_EMAIL_1, TEST_USER_PASSWORD_1, TEST_USER_ROLE_1, TEST_USER_STATE_1)
@@ -357,28 +304,11 @@ func TestDBNoteUpdate(t *testing.T) {
 			return err
 		})()
 	})))
-	t.Run("TimeoutError", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at updating note, case after preparing statement: %s", "context deadline exceeded")
-			_, err := tx.ExecContext(ctx, "SELECT pg_sleep(10)")
-			err = queries.UpdateNote(tx, ctx, 1, TEST_NOTE_TEXT_1, TEST_NOTE_TOPIC_1, 1, 1, TEST_NOTE_STATE_1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
-	t.Run("ContextCancelled", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at updating note, case after preparing statement: %s", "context canceled")
-			cancel()
-			err := queries.UpdateNote(tx, ctx, 1, TEST_NOTE_TEXT_1, TEST_NOTE_TOPIC_1, 1, 1, TEST_NOTE_STATE_1)
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
 }
 
 func TestDBNoteDelete(t *testing.T) {
+	SkipWithoutDB(t)
+
 	t.Run("NotFoundCase", RunWithRecreateDB((func(t *testing.T) {
 		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 			err := queries.DeleteNote(tx, ctx, 1)
@@ -474,23 +404,4 @@ func TestDBNoteDelete(t *testing.T) {
 			return err
 		})()
 	})))
-	t.Run("TimeoutError", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at deleting note, case after preparing statement: %s", "context deadline exceeded")
-			_, err := tx.ExecContext(ctx, "SELECT pg_sleep(10)")
-			err = queries.DeleteNote(tx, ctx, 1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
-	t.Run("ContextCancelled", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at deleting note, case after preparing statement: %s", "context canceled")
-			cancel()
-			err := queries.DeleteNote(tx, ctx, 1)
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
 }
@@ -6,7 +6,6 @@ package integration
 import (
 	"context"
 	"database/sql"
-	"fmt"
 	"testing"
 
 	"github.com/ArtemVoronov/indefinite-studies-api/internal/db"
@@ -15,6 +14,8 @@ import (
 )
 
 func TestDBRefreshTokenGet(t *testing.T) {
+	SkipWithoutDB(t)
+
 	t.Run("NotFoundCase", RunWithRecreateDB((func(t *testing.T) {
 		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 			_, err := queries.GetRefreshTokenByToken(tx, ctx, TEST_REFRESH_TOKEN_1)
@@ -40,29 +41,11 @@ func TestDBRefreshTokenGet(t *testing.T) {
 			return err
 		})()
 	})))
-	t.Run("TimeoutError", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at loading refresh token '%s' from db, case after QueryRow.Scan: %s", TEST_REFRESH_TOKEN_1, "context deadline exceeded")
-			_, err := tx.ExecContext(ctx, "SELECT pg_sleep(10)")
-			_, err = queries.GetRefreshTokenByToken(tx, ctx, TEST_REFRESH_TOKEN_1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
-	t.Run("ContextCancelled", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at loading refresh token '%s' from db, case after QueryRow.Scan: %s", TEST_REFRESH_TOKEN_1, "context canceled")
-			cancel()
-			_, err := queries.GetRefreshTokenByToken(tx, ctx, TEST_REFRESH_TOKEN_1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
 }
 
 func TestDBRefreshTokenCreate(t *testing.T) {
+	SkipWithoutDB(t)
+
 	t.Run("BasicCase", RunWithRecreateDB((func(t *testing.T) {
 		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 			err := queries.CreateRefreshToken(tx, ctx, TEST_REFRESH_TOKEN_USER_ID_1, TEST_REFRESH_TOKEN_1, TEST_REFRESH_TOKEN_EXPIRE_AT_1)
@@ -71,29 +54,11 @@ func TestDBRefreshTokenCreate(t *testing.T) {
 			return err
 		})()
 	})))
-	t.Run("TimeoutError", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at creating refresh token, case after preparing statement: %s", "context deadline exceeded")
-			_, err := tx.ExecContext(ctx, "SELECT pg_sleep(10)")
-			err = queries.CreateRefreshToken(tx, ctx, TEST_REFRESH_TOKEN_USER_ID_1, TEST_REFRESH_TOKEN_1, TEST_REFRESH_TOKEN_EXPIRE_AT_1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
-	t.Run("ContextCancelled", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at creating refresh token, case after preparing statement: %s", "context canceled")
-			cancel()
-			err := queries.CreateRefreshToken(tx, ctx, TEST_REFRESH_TOKEN_USER_ID_1, TEST_REFRESH_TOKEN_1, TEST_REFRESH_TOKEN_EXPIRE_AT_1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
 }
 
 func TestDBRefreshTokenUpdate(t *testing.T) {
+	SkipWithoutDB(t)
+
 	t.Run("NotFoundCase", RunWithRecreateDB((func(t *testing.T) {
 		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 			err := queries.UpdateRefreshToken(tx, ctx, TEST_REFRESH_TOKEN_USER_ID_2, TEST_REFRESH_TOKEN_2, TEST_REFRESH_TOKEN_EXPIRE_AT_2)
@@ -147,28 +112,11 @@ func TestDBRefreshTokenUpdate(t *testing.T) {
 			return err
 		})()
 	})))
-	t.Run("TimeoutError", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at updating refresh token, case after preparing statement: %s", "context deadline exceeded")
-			_, err := tx.ExecContext(ctx, "SELECT pg_sleep(10)")
-			err = queries.UpdateRefreshToken(tx, ctx, TEST_REFRESH_TOKEN_USER_ID_1, TEST_REFRESH_TOKEN_2, TEST_REFRESH_TOKEN_EXPIRE_AT_2)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
-	t.Run("ContextCancelled", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at updating refresh token, case after preparing statement: %s", "context canceled")
-			cancel()
-			err := queries.UpdateRefreshToken(tx, ctx, TEST_REFRESH_TOKEN_USER_ID_1, TEST_REFRESH_TOKEN_2, TEST_REFRESH_TOKEN_EXPIRE_AT_2)
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
 }
 
 func TestDBRefreshTokenDelete(t *testing.T) {
+	SkipWithoutDB(t)
+
 	t.Run("NotFoundCase", RunWithRecreateDB((func(t *testing.T) {
 		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 			err := queries.DeleteRefreshToken(tx, ctx, TEST_REFRESH_TOKEN_USER_ID_1)
@@ -219,23 +167,4 @@ func TestDBRefreshTokenDelete(t *testing.T) {
 			return err
 		})()
 	})))
-	t.Run("TimeoutError", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at deleting refresh token, case after preparing statement: %s", "context deadline exceeded")
-			_, err := tx.ExecContext(ctx, "SELECT pg_sleep(10)")
-			err = queries.DeleteRefreshToken(tx, ctx, TEST_REFRESH_TOKEN_USER_ID_1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
-	t.Run("ContextCancelled", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at deleting refresh token, case after preparing statement: %s", "context canceled")
-			cancel()
-			err := queries.DeleteRefreshToken(tx, ctx, TEST_REFRESH_TOKEN_USER_ID_1)
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
 }
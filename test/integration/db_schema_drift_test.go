@@ -0,0 +1,36 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ArtemVoronov/indefinite-studies-api/internal/db/entities"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/schema"
+)
+
+func CreateSchemaExpectations() []schema.TableExpectation {
+	return []schema.TableExpectation{
+		schema.FromStruct("tasks", entities.Task{}),
+		schema.FromStruct("tags", entities.Tag{}, []string{"name"}),
+		schema.FromStruct("users", entities.User{}, []string{"email"}),
+		schema.FromStruct("notes", entities.Note{}),
+		schema.FromStruct("refresh_tokens", entities.RefreshToken{}),
+	}
+}
+
+func TestDBSchemaDrift(t *testing.T) {
+	SkipWithoutDB(t)
+
+	t.Run("EntitiesMatchSchema", RunWithRecreateDB((func(t *testing.T) {
+		drifts := schema.Detect(TakeSchemaSnapshot(t), CreateSchemaExpectations()...)
+
+		var report bytes.Buffer
+		schema.WriteDrifts(&report, drifts)
+		if len(drifts) > 0 {
+			t.Errorf("DB schema drifted from the entities:\n%s", report.String())
+		}
+	})))
+}
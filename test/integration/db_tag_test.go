@@ -6,7 +6,6 @@ package integration
 import (
 	"context"
 	"database/sql"
-	"fmt"
 	"testing"
 
 	"github.com/ArtemVoronov/indefinite-studies-api/internal/db"
@@ -16,6 +15,8 @@ import (
 )
 
 func TestDBTagGet(t *testing.T) {
+	SkipWithoutDB(t)
+
 	t.Run("NotFoundCase", RunWithRecreateDB((func(t *testing.T) {
 		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 			_, err := queries.GetTag(tx, ctx, 1)
@@ -38,29 +39,11 @@ func TestDBTagGet(t *testing.T) {
 			return err
 		})()
 	})))
-	t.Run("TimeoutError", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at loading tag by id '%d' from db, case after QueryRow.Scan: %s", 1, "context deadline exceeded")
-			_, err := tx.ExecContext(ctx, "SELECT pg_sleep(10)")
-			_, err = queries.GetTag(tx, ctx, 1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
-	t.Run("ContextCancelled", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at loading tag by id '%d' from db, case after QueryRow.Scan: %s", 1, "context canceled")
-			cancel()
-			_, err := queries.GetTag(tx, ctx, 1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
 }
 
 func TestDBTagCreate(t *testing.T) {
+	SkipWithoutDB(t)
+
 	t.Run("BasicCase", RunWithRecreateDB((func(t *testing.T) {
 		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 			tagId, err := queries.CreateTag(tx, ctx, TEST_TAG_NAME_1, TEST_TAG_STATE_1)
@@ -86,29 +69,11 @@ func TestDBTagCreate(t *testing.T) {
 			return err
 		})()
 	})))
-	t.Run("TimeoutError", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at inserting tag (Name: '%s', State: '%s') into db, case after QueryRow.Scan: %s", TEST_TAG_NAME_1, TEST_TAG_STATE_1, "context deadline exceeded")
-			_, err := tx.ExecContext(ctx, "SELECT pg_sleep(10)")
-			_, err = queries.CreateTag(tx, ctx, TEST_TAG_NAME_1, TEST_TAG_STATE_1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
-	t.Run("ContextCancelled", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at inserting tag (Name: '%s', State: '%s') into db, case after QueryRow.Scan: %s", TEST_TAG_NAME_1, TEST_TAG_STATE_1, "context canceled")
-			cancel()
-			_, err := queries.CreateTag(tx, ctx, TEST_TAG_NAME_1, TEST_TAG_STATE_1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
 }
 
 func TestDBTagGetAll(t *testing.T) {
+	SkipWithoutDB(t)
+
 	t.Run("ExpectedEmpty", RunWithRecreateDB((func(t *testing.T) {
 		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 			tags, err := queries.GetTags(tx, ctx, 50, 0)
@@ -169,29 +134,11 @@ func TestDBTagGetAll(t *testing.T) {
 			return err
 		})()
 	})))
-	t.Run("TimeoutError", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at loading tags from db, case after Query: %s", "context deadline exceeded")
-			_, err := tx.ExecContext(ctx, "SELECT pg_sleep(10)")
-			_, err = queries.GetTags(tx, ctx, 50, 0)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
-	t.Run("ContextCancelled", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at loading tags from db, case after Query: %s", "context canceled")
-			cancel()
-			_, err := queries.GetTags(tx, ctx, 50, 0)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
 }
 
 func TestDBTagUpdate(t *testing.T) {
+	SkipWithoutDB(t)
+
 	t.Run("NotFoundCase", RunWithRecreateDB((func(t *testing.T) {
 		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 			err := queries.UpdateTag(tx, ctx, 1, TEST_TAG_NAME_1, TEST_TAG_STATE_1)
@@ -272,28 +219,11 @@ func TestDBTagUpdate(t *testing.T) {
 			return err
 		})()
 	})))
-	t.Run("TimeoutError", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at updating tag, case after preparing statement: %s", "context deadline exceeded")
-			_, err := tx.ExecContext(ctx, "SELECT pg_sleep(10)")
-			err = queries.UpdateTag(tx, ctx, 1, TEST_TAG_NAME_1, TEST_TAG_STATE_1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
-	t.Run("ContextCancelled", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at updating tag, case after preparing statement: %s", "context canceled")
-			cancel()
-			err := queries.UpdateTag(tx, ctx, 1, TEST_TAG_NAME_1, TEST_TAG_STATE_1)
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
 }
 
 func TestDBTagDelete(t *testing.T) {
+	SkipWithoutDB(t)
+
 	t.Run("NotFoundCase", RunWithRecreateDB((func(t *testing.T) {
 		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 			err := queries.DeleteTag(tx, ctx, 1)
@@ -358,23 +288,4 @@ func TestDBTagDelete(t *testing.T) {
 			return err
 		})()
 	})))
-	t.Run("TimeoutError", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at deleting tag, case after preparing statement: %s", "context deadline exceeded")
-			_, err := tx.ExecContext(ctx, "SELECT pg_sleep(10)")
-			err = queries.DeleteTag(tx, ctx, 1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
-	t.Run("ContextCancelled", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at deleting tag, case after preparing statement: %s", "context canceled")
-			cancel()
-			err := queries.DeleteTag(tx, ctx, 1)
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
 }
@@ -6,7 +6,6 @@ package integration
 import (
 	"context"
 	"database/sql"
-	"fmt"
 	"testing"
 
 	"github.com/ArtemVoronov/indefinite-studies-api/internal/db"
@@ -16,6 +15,8 @@ import (
 )
 
 func TestDBTaskGet(t *testing.T) {
+	SkipWithoutDB(t)
+
 	t.Run("NotFoundCase", RunWithRecreateDB((func(t *testing.T) {
 		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 			_, err := queries.GetTask(tx, ctx, 1)
@@ -38,29 +39,11 @@ func TestDBTaskGet(t *testing.T) {
 			return err
 		})()
 	})))
-	t.Run("TimeoutError", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at loading task by id '%d' from db, case after QueryRow.Scan: %s", 1, "context deadline exceeded")
-			_, err := tx.ExecContext(ctx, "SELECT pg_sleep(10)")
-			_, err = queries.GetTask(tx, ctx, 1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
-	t.Run("ContextCancelled", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at loading task by id '%d' from db, case after QueryRow.Scan: %s", 1, "context canceled")
-			cancel()
-			_, err := queries.GetTask(tx, ctx, 1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
 }
 
 func TestDBTaskCreate(t *testing.T) {
+	SkipWithoutDB(t)
+
 	t.Run("BasicCase", RunWithRecreateDB((func(t *testing.T) {
 		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 			taskId, err := queries.CreateTask(tx, ctx, TEST_TASK_NAME_1, TEST_TASK_STATE_1)
@@ -86,29 +69,11 @@ func TestDBTaskCreate(t *testing.T) {
 			return err
 		})()
 	})))
-	t.Run("TimeoutError", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at inserting task (Name: '%s', State: '%s') into db, case after QueryRow.Scan: %s", TEST_TASK_NAME_1, TEST_TASK_STATE_1, "context deadline exceeded")
-			_, err := tx.ExecContext(ctx, "SELECT pg_sleep(10)")
-			_, err = queries.CreateTask(tx, ctx, TEST_TASK_NAME_1, TEST_TASK_STATE_1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
-	t.Run("ContextCancelled", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at inserting task (Name: '%s', State: '%s') into db, case after QueryRow.Scan: %s", TEST_TASK_NAME_1, TEST_TASK_STATE_1, "context canceled")
-			cancel()
-			_, err := queries.CreateTask(tx, ctx, TEST_TASK_NAME_1, TEST_TASK_STATE_1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
 }
 
 func TestDBTaskGetAll(t *testing.T) {
+	SkipWithoutDB(t)
+
 	t.Run("ExpectedEmpty", RunWithRecreateDB((func(t *testing.T) {
 		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 			tasks, err := queries.GetTasks(tx, ctx, 50, 0)
@@ -169,29 +134,11 @@ func TestDBTaskGetAll(t *testing.T) {
 			return err
 		})()
 	})))
-	t.Run("TimeoutError", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at loading tasks from db, case after Query: %s", "context deadline exceeded")
-			_, err := tx.ExecContext(ctx, "SELECT pg_sleep(10)")
-			_, err = queries.GetTasks(tx, ctx, 50, 0)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
-	t.Run("ContextCancelled", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at loading tasks from db, case after Query: %s", "context canceled")
-			cancel()
-			_, err := queries.GetTasks(tx, ctx, 50, 0)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
 }
 
 func TestDBTaskUpdate(t *testing.T) {
+	SkipWithoutDB(t)
+
 	t.Run("NotFoundCase", RunWithRecreateDB((func(t *testing.T) {
 		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 			err := queries.UpdateTask(tx, ctx, 1, TEST_TASK_NAME_1, TEST_TASK_STATE_1)
@@ -272,28 +219,11 @@ func TestDBTaskUpdate(t *testing.T) {
 			return err
 		})()
 	})))
-	t.Run("TimeoutError", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at updating task, case after preparing statement: %s", "context deadline exceeded")
-			_, err := tx.ExecContext(ctx, "SELECT pg_sleep(10)")
-			err = queries.UpdateTask(tx, ctx, 1, TEST_TASK_NAME_1, TEST_TASK_STATE_1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
-	t.Run("ContextCancelled", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at updating task, case after preparing statement: %s", "context canceled")
-			cancel()
-			err := queries.UpdateTask(tx, ctx, 1, TEST_TASK_NAME_1, TEST_TASK_STATE_1)
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
 }
 
 func TestDBTaskDelete(t *testing.T) {
+	SkipWithoutDB(t)
+
 	t.Run("NotFoundCase", RunWithRecreateDB((func(t *testing.T) {
 		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 			err := queries.DeleteTask(tx, ctx, 1)
@@ -358,23 +288,4 @@ func TestDBTaskDelete(t *testing.T) {
 			return err
 		})()
 	})))
-	t.Run("TimeoutError", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at deleting task, case after preparing statement: %s", "context deadline exceeded")
-			_, err := tx.ExecContext(ctx, "SELECT pg_sleep(10)")
-			err = queries.DeleteTask(tx, ctx, 1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
-	t.Run("ContextCancelled", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at deleting task, case after preparing statement: %s", "context canceled")
-			cancel()
-			err := queries.DeleteTask(tx, ctx, 1)
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
 }
@@ -6,7 +6,6 @@ package integration
 import (
 	"context"
 	"database/sql"
-	"fmt"
 	"testing"
 
 	"github.com/ArtemVoronov/indefinite-studies-api/internal/db"
@@ -16,6 +15,8 @@ import (
 )
 
 func TestDBUserGet(t *testing.T) {
+	SkipWithoutDB(t)
+
 	t.Run("NotFoundCase", RunWithRecreateDB((func(t *testing.T) {
 		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 			_, err := queries.GetUser(tx, ctx, 1)
@@ -41,29 +42,11 @@ func TestDBUserGet(t *testing.T) {
 			return err
 		})()
 	})))
-	t.Run("TimeoutError", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at loading user by id '%d' from db, case after QueryRow.Scan: %s", 1, "context deadline exceeded")
-			_, err := tx.ExecContext(ctx, "SELECT pg_sleep(10)")
-			_, err = queries.GetUser(tx, ctx, 1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
-	t.Run("ContextCancelled", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at loading user by id '%d' from db, case after QueryRow.Scan: %s", 1, "context canceled")
-			cancel()
-			_, err := queries.GetUser(tx, ctx, 1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
 }
 
 func TestDBUserCreate(t *testing.T) {
+	SkipWithoutDB(t)
+
 	t.Run("BasicCase", RunWithRecreateDB((func(t *testing.T) {
 		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 			userId, err := queries.CreateUser(tx, ctx, TEST_USER_LOGIN_1, TEST_USER_EMAIL_1, TEST_USER_PASSWORD_1, TEST_USER_ROLE_1, TEST_USER_STATE_1)
@@ -88,29 +71,11 @@ func TestDBUserCreate(t *testing.T) {
 			return err
 		})()
 	})))
-	t.Run("TimeoutError", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at inserting user (Login: '%s', Email: '%s') into db, case after QueryRow.Scan: %s", TEST_USER_LOGIN_1, TEST_USER_EMAIL_1, "context deadline exceeded")
-			_, err := tx.ExecContext(ctx, "SELECT pg_sleep(10)")
-			_, err = queries.CreateUser(tx, ctx, TEST_USER_LOGIN_1, TEST_USER_EMAIL_1, TEST_USER_PASSWORD_1, TEST_USER_ROLE_1, TEST_USER_STATE_1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
-	t.Run("ContextCancelled", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at inserting user (Login: '%s', Email: '%s') into db, case after QueryRow.Scan: %s", TEST_USER_LOGIN_1, TEST_USER_EMAIL_1, "context canceled")
-			cancel()
-			_, err := queries.CreateUser(tx, ctx, TEST_USER_LOGIN_1, TEST_USER_EMAIL_1, TEST_USER_PASSWORD_1, TEST_USER_ROLE_1, TEST_USER_STATE_1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
 }
 
 func TestDBUserGetAll(t *testing.T) {
+	SkipWithoutDB(t)
+
 	t.Run("ExpectedEmpty", RunWithRecreateDB((func(t *testing.T) {
 		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 			users, err := queries.GetUsers(tx, ctx, 50, 0)
@@ -174,29 +139,11 @@ func TestDBUserGetAll(t *testing.T) {
 			return err
 		})()
 	})))
-	t.Run("TimeoutError", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at loading users from db, case after Query: %s", "context deadline exceeded")
-			_, err := tx.ExecContext(ctx, "SELECT pg_sleep(10)")
-			_, err = queries.GetUsers(tx, ctx, 50, 0)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
-	t.Run("ContextCancelled", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at loading users from db, case after Query: %s", "context canceled")
-			cancel()
-			_, err := queries.GetUsers(tx, ctx, 50, 0)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
 }
 
 func TestDBUserUpdate(t *testing.T) {
+	SkipWithoutDB(t)
+
 	t.Run("NotFoundCase", RunWithRecreateDB((func(t *testing.T) {
 		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 			err := queries.UpdateUser(tx, ctx, 1, TEST_USER_LOGIN_1, TEST_USER_EMAIL_1, TEST_USER_PASSWORD_1, TEST_USER_ROLE_1, TEST_USER_STATE_1)
@@ -280,28 +227,11 @@ func TestDBUserUpdate(t *testing.T) {
 			return err
 		})()
 	})))
-	t.Run("TimeoutError", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at updating user, case after preparing statement: %s", "context deadline exceeded")
-			_, err := tx.ExecContext(ctx, "SELECT pg_sleep(10)")
-			err = queries.UpdateUser(tx, ctx, 1, TEST_USER_LOGIN_1, TEST_USER_EMAIL_1, TEST_USER_PASSWORD_1, TEST_USER_ROLE_1, TEST_USER_STATE_1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
-	t.Run("ContextCancelled", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at updating user, case after preparing statement: %s", "context canceled")
-			cancel()
-			err := queries.UpdateUser(tx, ctx, 1, TEST_USER_LOGIN_1, TEST_USER_EMAIL_1, TEST_USER_PASSWORD_1, TEST_USER_ROLE_1, TEST_USER_STATE_1)
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
 }
 
 func TestDBUserDelete(t *testing.T) {
+	SkipWithoutDB(t)
+
 	t.Run("NotFoundCase", RunWithRecreateDB((func(t *testing.T) {
 		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 			err := queries.DeleteUser(tx, ctx, 1)
@@ -368,28 +298,11 @@ func TestDBUserDelete(t *testing.T) {
 			return err
 		})()
 	})))
-	t.Run("TimeoutError", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at deleting user, case after preparing statement: %s", "context deadline exceeded")
-			_, err := tx.ExecContext(ctx, "SELECT pg_sleep(10)")
-			err = queries.DeleteUser(tx, ctx, 1)
-
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
-	t.Run("ContextCancelled", RunWithRecreateDB((func(t *testing.T) {
-		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
-			expectedError := fmt.Errorf("error at deleting user, case after preparing statement: %s", "context canceled")
-			cancel()
-			err := queries.DeleteUser(tx, ctx, 1)
-			assert.Equal(t, expectedError, err)
-			return err
-		})()
-	})))
 }
 
 func TestDBUserCredentials(t *testing.T) {
+	SkipWithoutDB(t)
+
 	t.Run("NotFoundCase", RunWithRecreateDB((func(t *testing.T) {
 		db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
 			userId, isValid, err := queries.IsValidCredentials(tx, ctx, TEST_USER_EMAIL_1, TEST_USER_PASSWORD_1)
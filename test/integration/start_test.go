@@ -4,6 +4,7 @@
 package integration
 
 import (
+	"database/sql"
 	"fmt"
 	"os"
 	"os/exec"
@@ -20,8 +21,11 @@ import (
 	"github.com/ArtemVoronov/indefinite-studies-api/internal/app"
 
 	"github.com/ArtemVoronov/indefinite-studies-api/internal/db"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/dbconn"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/sqltrace"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/lib/pq"
 )
 
 type Utils struct {
@@ -35,7 +39,14 @@ var TestRouter *gin.Engine
 
 func TestMain(m *testing.M) {
 	Setup()
-	TestRouter = SetupRouter()
+	if IsStubTarget() {
+		TestRouter = stubServer.Router()
+	} else {
+		TestRouter = SetupRouter()
+	}
+	if baseUrl, ok := os.LookupEnv("QA_TARGET_URL"); ok {
+		testHttpClient = NewRemoteTestHttpClient(baseUrl)
+	}
 	code := m.Run()
 	Shutdown()
 	os.Exit(code)
@@ -94,6 +105,13 @@ func InitTestEnv() {
 }
 
 func RecreateTestDB() {
+	if IsStubTarget() {
+		stubServer.Reset()
+		return
+	}
+	if IsReplaying() {
+		return
+	}
 	// TODO: think about carelessness removing prod database
 	cmd := exec.Command("docker-compose", "--env-file", "./.env.test", "--profile", "integration-tests-only", "up", "liquibase_rollback_all_and_create_db_again")
 	cmd.Dir = GetRootPath()
@@ -113,19 +131,73 @@ func RecreateTestDB() {
 
 type TestFunc func(t *testing.T)
 
+// False while replaying cassettes or testing the stub, there is only the HTTP API then
+func HasDB() bool {
+	return !IsReplaying() && !IsStubTarget()
+}
+
+// For tests and helpers that query the DB directly
+func SkipWithoutDB(t *testing.T) {
+	if !HasDB() {
+		t.Skip("the DB is not available while replaying cassettes or testing the stub")
+	}
+}
+
 func RunWithRecreateDB(f TestFunc) func(t *testing.T) {
 	RecreateTestDB()
 	return func(t *testing.T) {
+		cassetteDeck.Insert(t)
+		softDeleteChecker.Reset()
 		f(t)
+		AssertNoSoftDeleteViolations(t)
+	}
+}
+
+// The API opens its connection pool with the driver named in DATABASE_DRIVER_NAME,
+// so every statement it executes goes through the tracing wrapper (see utils_sqltrace_test.go)
+func SetupSqlTrace() {
+	sqltrace.Register(SQL_TRACE_DRIVER_NAME, &pq.Driver{})
+	os.Setenv("DATABASE_DRIVER_NAME", SQL_TRACE_DRIVER_NAME)
+}
+
+// Direct connection of the harness itself. It is opened before the fault proxy takes over DATABASE_HOST and
+// DATABASE_PORT and is not traced, so helpers that count rows or take snapshots see the DB as it is
+// while the API's pool is faulted or captured.
+var harnessDB *sql.DB
+
+func HarnessDB() *sql.DB {
+	return harnessDB
+}
+
+func SetupHarnessDB() {
+	result, err := dbconn.Open("")
+	if err != nil {
+		fmt.Printf("error during connecting the harness to the test DB: %v\n", err)
+		os.Exit(1)
 	}
+	harnessDB = result
 }
 
 func Setup() {
 	InitTestEnv()
+	SetupCassettes()
+	SetupStub()
+	SetupSqlTrace()
 	auth.Setup()
+	if !HasDB() {
+		return
+	}
+	SetupHarnessDB()
+	SetupFaultProxy()
 	db.GetInstance()
 }
 
 func Shutdown() {
+	SaveCassettes()
+	if !HasDB() {
+		return
+	}
+	defer ShutdownFaultProxy()
+	defer harnessDB.Close()
 	defer db.GetInstance().GetDB().Close()
 }
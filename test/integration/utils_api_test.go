@@ -6,13 +6,21 @@ package integration
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 )
 
+const REMOTE_CLIENT_TIMEOUT = 30 * time.Second
+
 var testHttpClient TestHttpClient = TestHttpClient{}
 
+var _ TestApi = &testHttpClient
+
 type TagsApi interface {
 	CreateTag(name any, state any) (int, string, error)
 	GetTag(id string) (int, string)
@@ -52,7 +60,7 @@ type AuthApi interface {
 
 type PingApi interface {
 	Ping() (int, string, error)
-	SafePing() (int, string, error)
+	SafePing(accessToken string) (int, string, error)
 }
 
 type TestApi interface {
@@ -64,7 +72,56 @@ type TestApi interface {
 	PingApi
 }
 
+// Sends requests to TestRouter in-process or, if BaseUrl is set, to a remote instance of the API
 type TestHttpClient struct {
+	BaseUrl    string
+	HttpClient *http.Client
+}
+
+func NewRemoteTestHttpClient(baseUrl string) TestHttpClient {
+	return TestHttpClient{
+		BaseUrl: strings.TrimSuffix(baseUrl, "/"),
+		HttpClient: &http.Client{
+			Timeout: REMOTE_CLIENT_TIMEOUT,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (p *TestHttpClient) Serve(req *http.Request) (int, string, error) {
+	return cassetteDeck.Observe(req, func() (int, string, error) {
+		return softDeleteChecker.Observe(req, func() (int, string, error) {
+			return dbDiffRecorder.Observe(func() (int, string, error) {
+				return p.serve(req)
+			})
+		})
+	})
+}
+
+func (p *TestHttpClient) serve(req *http.Request) (int, string, error) {
+	if p.BaseUrl == "" {
+		w := httptest.NewRecorder()
+		TestRouter.ServeHTTP(w, req)
+		return w.Code, w.Body.String(), nil
+	}
+
+	remoteReq, err := http.NewRequestWithContext(req.Context(), req.Method, p.BaseUrl+req.URL.RequestURI(), req.Body)
+	if err != nil {
+		return -1, "", err
+	}
+	remoteReq.Header = req.Header
+	resp, err := p.HttpClient.Do(remoteReq)
+	if err != nil {
+		return -1, "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return -1, "", err
+	}
+	return resp.StatusCode, string(body), nil
 }
 
 func (p *TestHttpClient) CreateTask(name any, state any) (int, string, error) {
@@ -73,18 +130,15 @@ func (p *TestHttpClient) CreateTask(name any, state any) (int, string, error) {
 		return -1, "", err
 	}
 
-	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodPost, "/tasks", bytes.NewBuffer([]byte(body)))
 	req.Header.Set("Content-Type", "application/json")
-	TestRouter.ServeHTTP(w, req)
-	return w.Code, w.Body.String(), nil
+	return p.Serve(req)
 }
 
 func (p *TestHttpClient) GetTask(id string) (int, string) {
-	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodGet, "/tasks/"+id, nil)
-	TestRouter.ServeHTTP(w, req)
-	return w.Code, w.Body.String()
+	httpStatusCode, body, _ := p.Serve(req)
+	return httpStatusCode, body
 }
 
 func (p *TestHttpClient) GetTasks(limit any, offset any) (int, string, error) {
@@ -93,10 +147,8 @@ func (p *TestHttpClient) GetTasks(limit any, offset any) (int, string, error) {
 		return -1, "", err
 	}
 
-	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodGet, "/tasks"+queryParams, nil)
-	TestRouter.ServeHTTP(w, req)
-	return w.Code, w.Body.String(), nil
+	return p.Serve(req)
 }
 
 func (p *TestHttpClient) UpdateTask(id any, name any, state any) (int, string, error) {
@@ -109,11 +161,9 @@ func (p *TestHttpClient) UpdateTask(id any, name any, state any) (int, string, e
 		return -1, "", err
 	}
 
-	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodPut, "/tasks"+idParam, bytes.NewBuffer([]byte(body)))
 	req.Header.Set("Content-Type", "application/json")
-	TestRouter.ServeHTTP(w, req)
-	return w.Code, w.Body.String(), nil
+	return p.Serve(req)
 }
 
 func (p *TestHttpClient) DeleteTask(id any) (int, string, error) {
@@ -122,10 +172,8 @@ func (p *TestHttpClient) DeleteTask(id any) (int, string, error) {
 		return -1, "", err
 	}
 
-	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodDelete, "/tasks"+idParam, nil)
-	TestRouter.ServeHTTP(w, req)
-	return w.Code, w.Body.String(), nil
+	return p.Serve(req)
 }
 
 func (p *TestHttpClient) CreateTag(name any, state any) (int, string, error) {
@@ -134,18 +182,15 @@ func (p *TestHttpClient) CreateTag(name any, state any) (int, string, error) {
 		return -1, "", err
 	}
 
-	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodPost, "/tags", bytes.NewBuffer([]byte(body)))
 	req.Header.Set("Content-Type", "application/json")
-	TestRouter.ServeHTTP(w, req)
-	return w.Code, w.Body.String(), nil
+	return p.Serve(req)
 }
 
 func (p *TestHttpClient) GetTag(id string) (int, string) {
-	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodGet, "/tags/"+id, nil)
-	TestRouter.ServeHTTP(w, req)
-	return w.Code, w.Body.String()
+	httpStatusCode, body, _ := p.Serve(req)
+	return httpStatusCode, body
 }
 
 func (p *TestHttpClient) GetTags(limit any, offset any) (int, string, error) {
@@ -154,10 +199,8 @@ func (p *TestHttpClient) GetTags(limit any, offset any) (int, string, error) {
 		return -1, "", err
 	}
 
-	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodGet, "/tags"+queryParams, nil)
-	TestRouter.ServeHTTP(w, req)
-	return w.Code, w.Body.String(), nil
+	return p.Serve(req)
 }
 
 func (p *TestHttpClient) UpdateTag(id any, name any, state any) (int, string, error) {
@@ -170,11 +213,9 @@ func (p *TestHttpClient) UpdateTag(id any, name any, state any) (int, string, er
 		return -1, "", err
 	}
 
-	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodPut, "/tags"+idParam, bytes.NewBuffer([]byte(body)))
 	req.Header.Set("Content-Type", "application/json")
-	TestRouter.ServeHTTP(w, req)
-	return w.Code, w.Body.String(), nil
+	return p.Serve(req)
 }
 
 func (p *TestHttpClient) DeleteTag(id any) (int, string, error) {
@@ -183,10 +224,8 @@ func (p *TestHttpClient) DeleteTag(id any) (int, string, error) {
 		return -1, "", err
 	}
 
-	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodDelete, "/tags"+idParam, nil)
-	TestRouter.ServeHTTP(w, req)
-	return w.Code, w.Body.String(), nil
+	return p.Serve(req)
 }
 
 func (p *TestHttpClient) CreateUser(login any, email any, password any, role any, state any) (int, string, error) {
@@ -195,18 +234,15 @@ func (p *TestHttpClient) CreateUser(login any, email any, password any, role any
 		return -1, "", err
 	}
 
-	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodPost, "/users", bytes.NewBuffer([]byte(body)))
 	req.Header.Set("Content-Type", "application/json")
-	TestRouter.ServeHTTP(w, req)
-	return w.Code, w.Body.String(), nil
+	return p.Serve(req)
 }
 
 func (p *TestHttpClient) GetUser(id string) (int, string) {
-	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodGet, "/users/"+id, nil)
-	TestRouter.ServeHTTP(w, req)
-	return w.Code, w.Body.String()
+	httpStatusCode, body, _ := p.Serve(req)
+	return httpStatusCode, body
 }
 
 func (p *TestHttpClient) GetUsers(limit any, offset any) (int, string, error) {
@@ -215,10 +251,8 @@ func (p *TestHttpClient) GetUsers(limit any, offset any) (int, string, error) {
 		return -1, "", err
 	}
 
-	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodGet, "/users"+queryParams, nil)
-	TestRouter.ServeHTTP(w, req)
-	return w.Code, w.Body.String(), nil
+	return p.Serve(req)
 }
 
 func (p *TestHttpClient) UpdateUser(id any, login any, email any, password any, role any, state any) (int, string, error) {
@@ -231,11 +265,9 @@ func (p *TestHttpClient) UpdateUser(id any, login any, email any, password any,
 		return -1, "", err
 	}
 
-	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodPut, "/users"+idParam, bytes.NewBuffer([]byte(body)))
 	req.Header.Set("Content-Type", "application/json")
-	TestRouter.ServeHTTP(w, req)
-	return w.Code, w.Body.String(), nil
+	return p.Serve(req)
 }
 
 func (p *TestHttpClient) DeleteUser(id any) (int, string, error) {
@@ -244,10 +276,8 @@ func (p *TestHttpClient) DeleteUser(id any) (int, string, error) {
 		return -1, "", err
 	}
 
-	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodDelete, "/users"+idParam, nil)
-	TestRouter.ServeHTTP(w, req)
-	return w.Code, w.Body.String(), nil
+	return p.Serve(req)
 }
 
 func (p *TestHttpClient) CreateNote(text any, topic any, tagId any, userId any, state any) (int, string, error) {
@@ -256,18 +286,15 @@ func (p *TestHttpClient) CreateNote(text any, topic any, tagId any, userId any,
 		return -1, "", err
 	}
 
-	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodPost, "/notes", bytes.NewBuffer([]byte(body)))
 	req.Header.Set("Content-Type", "application/json")
-	TestRouter.ServeHTTP(w, req)
-	return w.Code, w.Body.String(), nil
+	return p.Serve(req)
 }
 
 func (p *TestHttpClient) GetNote(id string) (int, string) {
-	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodGet, "/notes/"+id, nil)
-	TestRouter.ServeHTTP(w, req)
-	return w.Code, w.Body.String()
+	httpStatusCode, body, _ := p.Serve(req)
+	return httpStatusCode, body
 }
 
 func (p *TestHttpClient) GetNotes(limit any, offset any) (int, string, error) {
@@ -276,10 +303,8 @@ func (p *TestHttpClient) GetNotes(limit any, offset any) (int, string, error) {
 		return -1, "", err
 	}
 
-	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodGet, "/notes"+queryParams, nil)
-	TestRouter.ServeHTTP(w, req)
-	return w.Code, w.Body.String(), nil
+	return p.Serve(req)
 }
 
 func (p *TestHttpClient) UpdateNote(id any, text any, topic any, tagId any, userId any, state any) (int, string, error) {
@@ -292,11 +317,9 @@ func (p *TestHttpClient) UpdateNote(id any, text any, topic any, tagId any, user
 		return -1, "", err
 	}
 
-	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodPut, "/notes"+idParam, bytes.NewBuffer([]byte(body)))
 	req.Header.Set("Content-Type", "application/json")
-	TestRouter.ServeHTTP(w, req)
-	return w.Code, w.Body.String(), nil
+	return p.Serve(req)
 }
 
 func (p *TestHttpClient) DeleteNote(id any) (int, string, error) {
@@ -305,10 +328,8 @@ func (p *TestHttpClient) DeleteNote(id any) (int, string, error) {
 		return -1, "", err
 	}
 
-	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodDelete, "/notes"+idParam, nil)
-	TestRouter.ServeHTTP(w, req)
-	return w.Code, w.Body.String(), nil
+	return p.Serve(req)
 }
 
 func (p *TestHttpClient) Authenicate(email any, password any) (int, string, error) {
@@ -317,11 +338,9 @@ func (p *TestHttpClient) Authenicate(email any, password any) (int, string, erro
 		return -1, "", err
 	}
 
-	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodPost, "/auth/login", bytes.NewBuffer([]byte(body)))
 	req.Header.Set("Content-Type", "application/json")
-	TestRouter.ServeHTTP(w, req)
-	return w.Code, w.Body.String(), nil
+	return p.Serve(req)
 }
 
 func (p *TestHttpClient) RefreshToken(token any) (int, string, error) {
@@ -330,28 +349,22 @@ func (p *TestHttpClient) RefreshToken(token any) (int, string, error) {
 		return -1, "", err
 	}
 
-	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodPost, "/auth/refresh-token", bytes.NewBuffer([]byte(body)))
 	req.Header.Set("Content-Type", "application/json")
-	TestRouter.ServeHTTP(w, req)
-	return w.Code, w.Body.String(), nil
+	return p.Serve(req)
 }
 
 func (p *TestHttpClient) Ping() (int, string, error) {
-	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodGet, "/ping", nil)
 	req.Header.Set("Content-Type", "application/json")
-	TestRouter.ServeHTTP(w, req)
-	return w.Code, w.Body.String(), nil
+	return p.Serve(req)
 }
 
 func (p *TestHttpClient) SafePing(accessToken string) (int, string, error) {
-	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodGet, "/safe-ping", nil)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+accessToken)
-	TestRouter.ServeHTTP(w, req)
-	return w.Code, w.Body.String(), nil
+	return p.Serve(req)
 }
 
 func ParseForJsonBody(paramName string, paramValue any) (string, error) {
@@ -389,6 +402,8 @@ func ParseForQueryParam(paramName string, paramValue any) (string, error) {
 	switch paramType := paramValue.(type) {
 	case int:
 		result = paramName + "=" + strconv.Itoa(paramValue.(int))
+	case string:
+		result = paramName + "=" + url.QueryEscape(paramValue.(string))
 	case nil:
 		result = ""
 	default:
@@ -0,0 +1,146 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/cassette"
+)
+
+// QA_CASSETTE_MODE=record saves every exchange of the test client to QA_CASSETTE_DIR/QA_CASSETTE_VERSION/<Test>.json,
+// QA_CASSETTE_MODE=replay serves the saved responses without the DB, see SkipWithoutDB.
+type CassetteDeck struct {
+	mutex   sync.Mutex
+	mode    string
+	dir     string
+	version string
+	// Running tests, each one is a subtest of the previous one. Requests carry no test,
+	// so they are recorded under the last one and tests of a deck can't run in parallel.
+	tests    []string
+	recorder *cassette.Recorder
+	player   *cassette.Player
+}
+
+var cassetteDeck = &CassetteDeck{mode: cassette.MODE_OFF}
+
+func SetupCassettes() {
+	mode, ok := os.LookupEnv("QA_CASSETTE_MODE")
+	if !ok || mode == cassette.MODE_OFF {
+		return
+	}
+	cassetteDeck.dir = GetCassetteDir()
+	cassetteDeck.version = GetCassetteVersion()
+	switch mode {
+	case cassette.MODE_RECORD:
+		cassetteDeck.recorder = cassette.NewRecorder(cassetteDeck.version)
+	case cassette.MODE_REPLAY:
+		player, err := cassette.LoadPlayer(cassetteDeck.dir, cassetteDeck.version)
+		if err != nil {
+			fmt.Printf("error during loading cassettes: %v\n", err)
+			os.Exit(1)
+		}
+		cassetteDeck.player = player
+	default:
+		fmt.Printf("error during loading cassettes: unknown QA_CASSETTE_MODE '%s', expected one of %v\n", mode, cassette.GetPossibleModes())
+		os.Exit(1)
+	}
+	cassetteDeck.mode = mode
+}
+
+func GetCassetteDir() string {
+	if dir, ok := os.LookupEnv("QA_CASSETTE_DIR"); ok {
+		return dir
+	}
+	return GetRootPath() + "/test/cassettes"
+}
+
+func GetCassetteVersion() string {
+	if version, ok := os.LookupEnv("QA_CASSETTE_VERSION"); ok {
+		return version
+	}
+	return cassette.DEFAULT_API_VERSION
+}
+
+func SaveCassettes() {
+	if cassetteDeck.recorder == nil {
+		return
+	}
+	paths, err := cassetteDeck.recorder.Save(cassetteDeck.dir)
+	if err != nil {
+		fmt.Printf("error during saving cassettes: %v\n", err)
+		return
+	}
+	fmt.Printf("recorded %d cassettes to %s\n", len(paths), cassetteDeck.dir)
+}
+
+func IsReplaying() bool {
+	return cassetteDeck.mode == cassette.MODE_REPLAY
+}
+
+// Requests are recorded under the name of the running test, set by RunWithRecreateDB until the test ends
+func (p *CassetteDeck) Insert(t *testing.T) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.mode == cassette.MODE_OFF {
+		return
+	}
+	if n := len(p.tests); n > 0 && !strings.HasPrefix(t.Name(), p.tests[n-1]+"/") {
+		t.Fatalf("cassettes can't tell the requests of '%s' from the ones of '%s' running in parallel", t.Name(), p.tests[n-1])
+	}
+	p.tests = append(p.tests, t.Name())
+	t.Cleanup(func() {
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+		p.tests = p.tests[:len(p.tests)-1]
+	})
+	if p.player != nil && !p.player.Has(t.Name()) {
+		t.Skipf("no cassette recorded for '%s'", t.Name())
+	}
+}
+
+func (p *CassetteDeck) Observe(req *http.Request, serve func() (int, string, error)) (int, string, error) {
+	p.mutex.Lock()
+	mode, test := p.mode, ""
+	if len(p.tests) > 0 {
+		test = p.tests[len(p.tests)-1]
+	}
+	p.mutex.Unlock()
+	if mode == cassette.MODE_OFF {
+		return serve()
+	}
+
+	recorded := cassette.Request{Method: req.Method, Url: req.URL.RequestURI(), Header: req.Header.Clone()}
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return -1, "", err
+		}
+		recorded.Body = string(body)
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if mode == cassette.MODE_REPLAY {
+		interaction, err := p.player.Play(test, recorded)
+		if err != nil {
+			return -1, "", err
+		}
+		return interaction.Response.Status, interaction.Response.Body, nil
+	}
+
+	start := time.Now()
+	httpStatusCode, body, err := serve()
+	if err == nil {
+		p.recorder.Record(test, recorded, cassette.Response{Status: httpStatusCode, Body: body}, time.Since(start))
+	}
+	return httpStatusCode, body, err
+}
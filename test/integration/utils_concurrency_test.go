@@ -0,0 +1,121 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"math/rand"
+	"os"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	CONCURRENCY_WORKERS_COUNT     int   = 10
+	CONCURRENCY_REPEATS_COUNT     int   = 5
+	CONCURRENCY_DEFAULT_SEED      int64 = 1
+	CONCURRENCY_MAX_YIELDS        int   = 20
+	CONCURRENCY_MAX_DELAY_IN_MSEC int   = 5
+)
+
+type ConcurrentCall struct {
+	Worker         int
+	HttpStatusCode int
+	Body           string
+	Err            error
+}
+
+// Seeded scheduler: every worker waits on a common start barrier and then
+// yields and sleeps for a pseudo-random amount derived from the seed,
+// so a failed interleaving can be replayed via QA_CONCURRENCY_SEED
+type SeededScheduler struct {
+	Seed    int64
+	Workers int
+}
+
+func NewSeededScheduler(seed int64, workers int) SeededScheduler {
+	return SeededScheduler{Seed: seed, Workers: workers}
+}
+
+func (p *SeededScheduler) Run(call func(worker int) (int, string, error)) []ConcurrentCall {
+	random := rand.New(rand.NewSource(p.Seed))
+	yields := make([]int, p.Workers)
+	delays := make([]time.Duration, p.Workers)
+	for i := 0; i < p.Workers; i++ {
+		yields[i] = random.Intn(CONCURRENCY_MAX_YIELDS)
+		delays[i] = time.Duration(random.Intn(CONCURRENCY_MAX_DELAY_IN_MSEC*1000)) * time.Microsecond
+	}
+
+	results := make([]ConcurrentCall, p.Workers)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < p.Workers; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			<-start
+			for j := 0; j < yields[worker]; j++ {
+				runtime.Gosched()
+			}
+			time.Sleep(delays[worker])
+			httpStatusCode, body, err := call(worker)
+			results[worker] = ConcurrentCall{Worker: worker, HttpStatusCode: httpStatusCode, Body: body, Err: err}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	return results
+}
+
+// Returns the seeds for repeated concurrent runs. If QA_CONCURRENCY_SEED is set, only that seed is used.
+func GetConcurrencySeeds(t *testing.T) []int64 {
+	value, ok := os.LookupEnv("QA_CONCURRENCY_SEED")
+	if ok {
+		seed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			t.Fatalf("unable to parse QA_CONCURRENCY_SEED '%s': %v", value, err)
+		}
+		return []int64{seed}
+	}
+
+	var result []int64
+	for i := 0; i < CONCURRENCY_REPEATS_COUNT; i++ {
+		result = append(result, CONCURRENCY_DEFAULT_SEED+int64(i))
+	}
+	return result
+}
+
+func RunWithSeeds(t *testing.T, f func(t *testing.T, seed int64)) {
+	for _, seed := range GetConcurrencySeeds(t) {
+		seed := seed
+		t.Run("Seed "+strconv.FormatInt(seed, 10), func(t *testing.T) {
+			f(t, seed)
+			if t.Failed() {
+				t.Logf("reproduce with QA_CONCURRENCY_SEED=%d", seed)
+			}
+		})
+	}
+}
+
+func CountCalls(calls []ConcurrentCall, httpStatusCode int, body string) int {
+	result := 0
+	for _, call := range calls {
+		if call.HttpStatusCode == httpStatusCode && call.Body == body {
+			result++
+		}
+	}
+	return result
+}
+
+func CountCallsWithStatus(calls []ConcurrentCall, httpStatusCode int) int {
+	result := 0
+	for _, call := range calls {
+		if call.HttpStatusCode == httpStatusCode {
+			result++
+		}
+	}
+	return result
+}
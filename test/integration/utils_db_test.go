@@ -339,3 +339,10 @@ func CreateNotesInDB(t *testing.T, tx *sql.Tx, ctx context.Context, count int, t
 	}
 	return lastErr
 }
+
+func CountRowsInDB(t *testing.T, table string, column string, value any) int {
+	result := -1
+	err := HarnessDB().QueryRowContext(context.Background(), "SELECT COUNT(*) FROM "+table+" WHERE "+column+" = $1", value).Scan(&result)
+	assert.Nil(t, err)
+	return result
+}
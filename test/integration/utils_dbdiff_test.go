@@ -0,0 +1,87 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/dbsnapshot"
+	"github.com/stretchr/testify/assert"
+)
+
+// Snapshots the DB before and after every call of the test client while enabled.
+// It is off by default because every call then reads all the tables twice.
+type DBDiffRecorder struct {
+	mutex   sync.Mutex
+	enabled bool
+	last    dbsnapshot.Diff
+	err     error
+}
+
+var dbDiffRecorder = &DBDiffRecorder{}
+
+func (p *DBDiffRecorder) Observe(serve func() (int, string, error)) (int, string, error) {
+	p.mutex.Lock()
+	enabled := p.enabled
+	p.mutex.Unlock()
+	if !enabled {
+		return serve()
+	}
+
+	ctx := context.Background()
+	before, err := dbsnapshot.Take(ctx, HarnessDB())
+	httpStatusCode, body, serveErr := serve()
+	var after dbsnapshot.Snapshot
+	if err == nil {
+		after, err = dbsnapshot.Take(ctx, HarnessDB())
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.err = err
+	p.last = dbsnapshot.Compare(before, after)
+	return httpStatusCode, body, serveErr
+}
+
+// Turns the recorder on and returns a function that turns it off, usually deferred
+func EnableDBDiff() func() {
+	dbDiffRecorder.mutex.Lock()
+	defer dbDiffRecorder.mutex.Unlock()
+	dbDiffRecorder.enabled = true
+	dbDiffRecorder.last = dbsnapshot.Diff{}
+	dbDiffRecorder.err = nil
+	return func() {
+		dbDiffRecorder.mutex.Lock()
+		defer dbDiffRecorder.mutex.Unlock()
+		dbDiffRecorder.enabled = false
+	}
+}
+
+// Changes made by the last call of the test client
+func LastDBDiff(t *testing.T) dbsnapshot.Diff {
+	dbDiffRecorder.mutex.Lock()
+	defer dbDiffRecorder.mutex.Unlock()
+	if !dbDiffRecorder.enabled {
+		t.Fatalf("DB diff recorder is disabled, call EnableDBDiff first")
+	}
+	assert.Nil(t, dbDiffRecorder.err)
+	return dbDiffRecorder.last
+}
+
+// Checks the exact set of changes of the last call, e.g. AssertDBDiff(t, "inserted tags#1")
+func AssertDBDiff(t *testing.T, expected ...string) {
+	diff := LastDBDiff(t)
+	sort.Strings(expected)
+	if expected == nil {
+		expected = []string{}
+	}
+	assert.Equal(t, expected, diff.Ids(), "unexpected DB changes:\n%s", diff.String())
+}
+
+func AssertNoDBChanges(t *testing.T) {
+	AssertDBDiff(t)
+}
@@ -0,0 +1,100 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ArtemVoronov/indefinite-studies-api/internal/db"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	FAULT_TIMEOUT           string = "TimeoutError"
+	FAULT_CANCEL            string = "ContextCancelled"
+	FAULT_CLOSED_CONNECTION string = "ClosedConnection"
+	FAULT_CLOSED_TX         string = "ClosedTransaction"
+)
+
+// Query function registered once, the faults are generated for it by RunQueryFaults
+type QueryFaultCase struct {
+	Name string
+	// Error of the function without the cause, e.g. "error at deleting tag, case after preparing statement"
+	ExpectedErrorPrefix string
+	Call                func(tx *sql.Tx, ctx context.Context) error
+}
+
+type Fault struct {
+	Name string
+	// Breaks the transaction, the context or the connection before the call
+	Inject func(t *testing.T, tx *sql.Tx, ctx context.Context, cancel context.CancelFunc)
+	// Cause appended to the prefix, empty if any cause is acceptable
+	ExpectedCause string
+}
+
+func CreateFaults() []Fault {
+	return []Fault{
+		{
+			Name: FAULT_TIMEOUT,
+			Inject: func(t *testing.T, tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) {
+				tx.ExecContext(ctx, "SELECT pg_sleep(10)")
+			},
+			ExpectedCause: context.DeadlineExceeded.Error(),
+		},
+		{
+			Name: FAULT_CANCEL,
+			Inject: func(t *testing.T, tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) {
+				cancel()
+			},
+			ExpectedCause: context.Canceled.Error(),
+		},
+		{
+			Name: FAULT_CLOSED_CONNECTION,
+			Inject: func(t *testing.T, tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) {
+				var pid int
+				err := tx.QueryRowContext(ctx, "SELECT pg_backend_pid()").Scan(&pid)
+				assert.Nil(t, err)
+				_, err = HarnessDB().ExecContext(context.Background(), "SELECT pg_terminate_backend($1)", pid)
+				assert.Nil(t, err)
+			},
+		},
+		{
+			Name: FAULT_CLOSED_TX,
+			Inject: func(t *testing.T, tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) {
+				assert.Nil(t, tx.Rollback())
+			},
+			ExpectedCause: sql.ErrTxDone.Error(),
+		},
+	}
+}
+
+// Runs the call once per fault, each as a subtest named after the fault
+func RunQueryFaults(t *testing.T, c QueryFaultCase) {
+	for _, fault := range CreateFaults() {
+		fault := fault
+		t.Run(fault.Name, func(t *testing.T) {
+			db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+				fault.Inject(t, tx, ctx, cancel)
+				err := c.Call(tx, ctx)
+
+				AssertFaultError(t, c.ExpectedErrorPrefix, fault.ExpectedCause, err)
+				return err
+			})()
+		})
+	}
+}
+
+func AssertFaultError(t *testing.T, prefix string, cause string, err error) {
+	if cause != "" {
+		assert.Equal(t, fmt.Errorf("%s: %s", prefix, cause), err)
+		return
+	}
+	if assert.NotNil(t, err) {
+		assert.True(t, strings.HasPrefix(err.Error(), prefix+": "), "expected error '%s: ...', actual '%v'", prefix, err)
+	}
+}
@@ -0,0 +1,55 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/ArtemVoronov/indefinite-studies-api/internal/db"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/explain"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/seed"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/sqltrace"
+	"github.com/stretchr/testify/assert"
+)
+
+const EXPLAIN_DEFAULT_ROWS_COUNT int = 100000
+
+// Seeds the DB in bulk with the states and credentials the other helpers use
+func SeedInDB(t *testing.T, config seed.Config) seed.Result {
+	config.State = TEST_NOTE_STATE_1
+	config.UserRole = TEST_USER_ROLE_1
+	config.UserPassword = TEST_USER_PASSWORD_1
+	result, err := seed.Run(context.Background(), HarnessDB(), config)
+	assert.Nil(t, err)
+	return result
+}
+
+// Runs the function, captures the statements it executed and explains every one of them
+func ExplainCaptured(t *testing.T, f func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error) []explain.Plan {
+	err, statements := TxVoidWithQueries(f)
+	assert.Nil(t, err)
+
+	var result []explain.Plan
+	db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+		for _, statement := range statements {
+			if statement.Kind != sqltrace.KIND_QUERY {
+				continue
+			}
+			plan, err := explain.Explain(ctx, tx, statement.Query, statement.Args...)
+			if err != nil {
+				assert.Nil(t, err)
+				return err
+			}
+			result = append(result, plan)
+		}
+		return nil
+	})()
+
+	if len(result) == 0 {
+		t.Errorf("no queries were captured, check that the API uses the '%s' driver", SQL_TRACE_DRIVER_NAME)
+	}
+	return result
+}
@@ -0,0 +1,78 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/faultproxy"
+	"github.com/stretchr/testify/assert"
+)
+
+const FAULT_PROXY_SEED int64 = 1
+
+// Proxy between the API and Postgres, nil unless QA_FAULT_PROXY is set
+var dbProxy *faultproxy.Proxy
+
+// Starts the proxy in front of DATABASE_HOST:DATABASE_PORT and points these variables at it,
+// so the API connects through the proxy. It must run before the DB pool of the API is created
+// and after the harness connected directly (see HarnessDB).
+func SetupFaultProxy() {
+	if os.Getenv("QA_FAULT_PROXY") == "" {
+		return
+	}
+	target := net.JoinHostPort(os.Getenv("DATABASE_HOST"), os.Getenv("DATABASE_PORT"))
+	proxy, err := faultproxy.Start("127.0.0.1:0", target, FAULT_PROXY_SEED)
+	if err != nil {
+		fmt.Printf("error during starting DB fault proxy: %v\n", err)
+		return
+	}
+	host, port, _ := net.SplitHostPort(proxy.Addr())
+	os.Setenv("DATABASE_HOST", host)
+	os.Setenv("DATABASE_PORT", port)
+	dbProxy = proxy
+}
+
+func ShutdownFaultProxy() {
+	if dbProxy != nil {
+		dbProxy.Close()
+	}
+}
+
+func SkipWithoutFaultProxy(t *testing.T) {
+	if dbProxy == nil {
+		t.Skip("DB fault proxy is disabled, set QA_FAULT_PROXY=1 to enable it")
+	}
+}
+
+// Turns the soft delete checker back on, set while a fault is injected
+var enableSoftDeleteChecker func()
+
+// Applies the fault to the existing and the new connections of the API to the DB. The soft delete checker
+// is suspended meanwhile, the failed responses of the API are what the fault is expected to cause.
+func InjectDBFault(fault faultproxy.Fault) {
+	if enableSoftDeleteChecker == nil {
+		enableSoftDeleteChecker = softDeleteChecker.Disable()
+	}
+	dbProxy.Set(fault)
+	dbProxy.DropAll()
+}
+
+// Removes the fault and the connections that may have been broken by it
+func ClearDBFault() {
+	dbProxy.Clear()
+	dbProxy.DropAll()
+	if enableSoftDeleteChecker != nil {
+		enableSoftDeleteChecker()
+		enableSoftDeleteChecker = nil
+	}
+}
+
+func AssertServerError(t *testing.T, httpStatusCode int, body string) {
+	assert.True(t, httpStatusCode >= http.StatusInternalServerError && httpStatusCode <= 599, "expected 5xx, actual %d: %s", httpStatusCode, body)
+}
@@ -0,0 +1,195 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	OPERATION_UPDATE_NOTE string = "UpdateNote"
+	OPERATION_DELETE_NOTE string = "DeleteNote"
+	OPERATION_UPDATE_TAG  string = "UpdateTag"
+	OPERATION_GET_NOTE    string = "GetNote"
+)
+
+type Operation struct {
+	Worker         int
+	Kind           string
+	Input          string
+	HttpStatusCode int
+	Body           string
+	Err            error
+	Call           time.Time
+	Return         time.Time
+}
+
+func (p Operation) String() string {
+	return fmt.Sprintf("worker %d: %s(%q) -> %d %s [%s .. %s]", p.Worker, p.Kind, p.Input, p.HttpStatusCode, p.Body,
+		p.Call.Format("15:04:05.000000"), p.Return.Format("15:04:05.000000"))
+}
+
+type History struct {
+	mutex      sync.Mutex
+	operations []Operation
+}
+
+func (p *History) Record(worker int, kind string, input string, call func() (int, string, error)) Operation {
+	op := Operation{Worker: worker, Kind: kind, Input: input}
+	op.Call = time.Now()
+	op.HttpStatusCode, op.Body, op.Err = call()
+	op.Return = time.Now()
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.operations = append(p.operations, op)
+	return op
+}
+
+func (p *History) Operations() []Operation {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	result := make([]Operation, len(p.operations))
+	copy(result, p.operations)
+	sort.Slice(result, func(i, j int) bool { return result[i].Call.Before(result[j].Call) })
+	return result
+}
+
+// Sequential specification of the system under test: Step returns the next state and false
+// if the observed result of op is impossible in the given state
+type SequentialModel struct {
+	Init func() any
+	Step func(state any, op Operation) (any, bool)
+	Key  func(state any) string
+}
+
+type Anomaly struct {
+	Description string
+	Operations  []Operation
+}
+
+func (p Anomaly) String() string {
+	result := p.Description
+	for _, op := range p.Operations {
+		result += "\n\t" + op.String()
+	}
+	return result
+}
+
+// Checks that there is a total order of the operations that respects real time
+// (an operation that returned before another one was called must be ordered first)
+// and is accepted by the sequential model. It's a Wing & Gong search with memoization of visited states.
+func IsLinearizable(model SequentialModel, history []Operation) bool {
+	visited := make(map[string]bool)
+	linearized := make([]bool, len(history))
+
+	var search func(state any, left int) bool
+	search = func(state any, left int) bool {
+		if left == 0 {
+			return true
+		}
+		key := linearizedKey(linearized) + "|" + model.Key(state)
+		if visited[key] {
+			return false
+		}
+		visited[key] = true
+
+		for i, op := range history {
+			if linearized[i] || !isMinimal(history, linearized, i) {
+				continue
+			}
+			next, ok := model.Step(state, op)
+			if !ok {
+				continue
+			}
+			linearized[i] = true
+			if search(next, left-1) {
+				return true
+			}
+			linearized[i] = false
+		}
+		return false
+	}
+	return search(model.Init(), len(history))
+}
+
+// An operation may be linearized next only if no other pending operation returned before it was called
+func isMinimal(history []Operation, linearized []bool, index int) bool {
+	for j, other := range history {
+		if j == index || linearized[j] {
+			continue
+		}
+		if other.Return.Before(history[index].Call) {
+			return false
+		}
+	}
+	return true
+}
+
+func linearizedKey(linearized []bool) string {
+	var sb strings.Builder
+	for _, v := range linearized {
+		if v {
+			sb.WriteByte('1')
+		} else {
+			sb.WriteByte('0')
+		}
+	}
+	return sb.String()
+}
+
+type ConcurrencyScenario struct {
+	Seed         int64
+	Workers      int
+	OpsPerWorker int
+	Kinds        []string
+	Execute      func(worker int, kind string, input string) (int, string, error)
+}
+
+func (p *ConcurrencyScenario) Run() *History {
+	random := rand.New(rand.NewSource(p.Seed))
+	plans := make([][]string, p.Workers)
+	for i := 0; i < p.Workers; i++ {
+		for j := 0; j < p.OpsPerWorker; j++ {
+			plans[i] = append(plans[i], p.Kinds[random.Intn(len(p.Kinds))])
+		}
+	}
+
+	history := &History{}
+	scheduler := NewSeededScheduler(p.Seed, p.Workers)
+	scheduler.Run(func(worker int) (int, string, error) {
+		for j, kind := range plans[worker] {
+			input := fmt.Sprintf("w%d-op%d", worker, j)
+			history.Record(worker, kind, input, func() (int, string, error) {
+				return p.Execute(worker, kind, input)
+			})
+		}
+		return 0, "", nil
+	})
+	return history
+}
+
+func AssertLinearizable(t *testing.T, model SequentialModel, history []Operation, detectors ...func([]Operation) []Anomaly) {
+	var anomalies []Anomaly
+	for _, detect := range detectors {
+		anomalies = append(anomalies, detect(history)...)
+	}
+	for _, anomaly := range anomalies {
+		t.Errorf("anomaly: %v", anomaly)
+	}
+
+	if !IsLinearizable(model, history) {
+		var sb strings.Builder
+		for _, op := range history {
+			sb.WriteString("\n\t" + op.String())
+		}
+		t.Errorf("history is not linearizable:%s", sb.String())
+	}
+}
@@ -0,0 +1,138 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-api/internal/db/entities"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/load"
+)
+
+const (
+	LOAD_DEFAULT_MIX         string        = "GetNotes=70,CreateNote=20,UpdateNote=10"
+	LOAD_DEFAULT_CONCURRENCY int           = 10
+	LOAD_DEFAULT_DURATION    time.Duration = 10 * time.Second
+	LOAD_DEFAULT_NOTES_COUNT int           = 100
+	LOAD_PAGE_LIMIT          int           = 100
+)
+
+type LoadConfig struct {
+	Scenario   string
+	Mix        string
+	Options    load.Options
+	NotesCount int
+	ReportPath string
+}
+
+func GetEnvString(name string, defaultValue string) string {
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func GetEnvInt(t *testing.T, name string, defaultValue int) int {
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return defaultValue
+	}
+	result, err := strconv.Atoi(value)
+	if err != nil {
+		t.Fatalf("unable to parse %s '%s': %v", name, value, err)
+	}
+	return result
+}
+
+func GetEnvDuration(t *testing.T, name string, defaultValue time.Duration) time.Duration {
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return defaultValue
+	}
+	result, err := time.ParseDuration(value)
+	if err != nil {
+		t.Fatalf("unable to parse %s '%s': %v", name, value, err)
+	}
+	return result
+}
+
+func GetLoadConfig(t *testing.T) LoadConfig {
+	mix := GetEnvString("QA_LOAD_MIX", LOAD_DEFAULT_MIX)
+	return LoadConfig{
+		Scenario: GetEnvString("QA_LOAD_SCENARIO", mix),
+		Mix:      mix,
+		Options: load.Options{
+			Concurrency: GetEnvInt(t, "QA_LOAD_CONCURRENCY", LOAD_DEFAULT_CONCURRENCY),
+			Rps:         float64(GetEnvInt(t, "QA_LOAD_RPS", 0)),
+			Duration:    GetEnvDuration(t, "QA_LOAD_DURATION", LOAD_DEFAULT_DURATION),
+			Requests:    GetEnvInt(t, "QA_LOAD_REQUESTS", 0),
+			Seed:        int64(GetEnvInt(t, "QA_LOAD_SEED", 1)),
+		},
+		NotesCount: GetEnvInt(t, "QA_LOAD_NOTES_COUNT", LOAD_DEFAULT_NOTES_COUNT),
+		ReportPath: GetEnvString("QA_LOAD_REPORT", ""),
+	}
+}
+
+// Operations available for load mixes. All of them go through the TestApi client,
+// so the same mix can be run in-process against TestRouter or against QA_TARGET_URL.
+func CreateLoadRegistry(client TestApi, notesCount int, seed int64) map[string]load.Operation {
+	var counter int64
+	var mutex sync.Mutex
+	random := rand.New(rand.NewSource(seed))
+	randomNoteId := func() int {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return random.Intn(notesCount) + 1
+	}
+	nextId := func() int {
+		return int(atomic.AddInt64(&counter, 1))
+	}
+	withoutBody := func(httpStatusCode int, body string, err error) (int, error) {
+		return httpStatusCode, err
+	}
+
+	return map[string]load.Operation{
+		"GetNotes": {Name: "GetNotes", Endpoint: fmt.Sprintf("GET /notes?limit=%d", LOAD_PAGE_LIMIT), Do: func() (int, error) {
+			return withoutBody(client.GetNotes(LOAD_PAGE_LIMIT, 0))
+		}},
+		"GetNote": {Name: "GetNote", Endpoint: "GET /notes/:id", Do: func() (int, error) {
+			httpStatusCode, _ := client.GetNote(strconv.Itoa(randomNoteId()))
+			return httpStatusCode, nil
+		}},
+		"CreateNote": {Name: "CreateNote", Endpoint: "POST /notes", Do: func() (int, error) {
+			id := nextId()
+			return withoutBody(client.CreateNote(
+				utils.entityGenerators.GenerateNoteText(TEST_NOTE_TEXT_TEMPLATE+"load ", id),
+				utils.entityGenerators.GenerateNoteTopic(TEST_NOTE_TOPIC_TEMPLATE+"load ", id),
+				TEST_NOTE_TAG_ID_1, TEST_NOTE_USER_ID_1, entities.NOTE_STATE_NEW))
+		}},
+		"UpdateNote": {Name: "UpdateNote", Endpoint: "PUT /notes/:id", Do: func() (int, error) {
+			id := nextId()
+			return withoutBody(client.UpdateNote(randomNoteId(),
+				utils.entityGenerators.GenerateNoteText(TEST_NOTE_TEXT_TEMPLATE+"updated ", id),
+				utils.entityGenerators.GenerateNoteTopic(TEST_NOTE_TOPIC_TEMPLATE+"updated ", id),
+				TEST_NOTE_TAG_ID_1, TEST_NOTE_USER_ID_1, entities.NOTE_STATE_NEW))
+		}},
+		"GetTags": {Name: "GetTags", Endpoint: fmt.Sprintf("GET /tags?limit=%d", LOAD_PAGE_LIMIT), Do: func() (int, error) {
+			return withoutBody(client.GetTags(LOAD_PAGE_LIMIT, 0))
+		}},
+		"GetTasks": {Name: "GetTasks", Endpoint: fmt.Sprintf("GET /tasks?limit=%d", LOAD_PAGE_LIMIT), Do: func() (int, error) {
+			return withoutBody(client.GetTasks(LOAD_PAGE_LIMIT, 0))
+		}},
+		"GetUsers": {Name: "GetUsers", Endpoint: fmt.Sprintf("GET /users?limit=%d", LOAD_PAGE_LIMIT), Do: func() (int, error) {
+			return withoutBody(client.GetUsers(LOAD_PAGE_LIMIT, 0))
+		}},
+		"Ping": {Name: "Ping", Endpoint: "GET /ping", Do: func() (int, error) {
+			return withoutBody(client.Ping())
+		}},
+	}
+}
@@ -0,0 +1,70 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/liquibase"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+// Guards the step by step update against a liquibase that never reports the end of the changelog
+const MIGRATIONS_MAX_STEPS int = 1000
+
+// QA_LIQUIBASE_COMMAND runs a local liquibase against a local Postgres instead of the docker-compose service,
+// QA_LIQUIBASE_SERVICE overrides the name of that service
+func CreateLiquibaseRunner() liquibase.Runner {
+	return liquibase.NewRunner(GetRootPath(), os.Getenv("QA_LIQUIBASE_COMMAND"), os.Getenv("QA_LIQUIBASE_SERVICE"))
+}
+
+func TakeSchemaSnapshot(t *testing.T) schema.Snapshot {
+	result, err := schema.Take(context.Background(), HarnessDB(), schema.DEFAULT_SCHEMA)
+	assert.Nil(t, err)
+	return result
+}
+
+func GetAppliedChangesets(t *testing.T) []liquibase.Changeset {
+	result, err := liquibase.Applied(context.Background(), HarnessDB())
+	assert.Nil(t, err)
+	return result
+}
+
+type MigrationStep struct {
+	Changeset liquibase.Changeset
+	// Schema right after the changeset is applied
+	Schema schema.Snapshot
+}
+
+// Applies the changelog one changeset at a time from an empty DB and snapshots the schema after each of them
+func ApplyMigrationsStepByStep(t *testing.T, runner liquibase.Runner) (schema.Snapshot, []MigrationStep) {
+	if !assert.Nil(t, runner.DropAll()) {
+		return schema.Snapshot{}, nil
+	}
+	initial := TakeSchemaSnapshot(t)
+
+	var steps []MigrationStep
+	for i := 0; i < MIGRATIONS_MAX_STEPS; i++ {
+		if !assert.Nil(t, runner.UpdateCount(1)) {
+			return initial, steps
+		}
+		applied := GetAppliedChangesets(t)
+		if len(applied) == len(steps) {
+			return initial, steps
+		}
+		if !assert.Equal(t, len(steps)+1, len(applied), "update-count 1 must apply exactly one changeset") {
+			return initial, steps
+		}
+		steps = append(steps, MigrationStep{Changeset: applied[len(applied)-1], Schema: TakeSchemaSnapshot(t)})
+	}
+	t.Errorf("changelog has more than %d changesets", MIGRATIONS_MAX_STEPS)
+	return initial, steps
+}
+
+func AssertSchemaEqual(t *testing.T, expected schema.Snapshot, actual schema.Snapshot, msgAndArgs ...any) {
+	assert.Empty(t, schema.Compare(expected, actual), msgAndArgs...)
+}
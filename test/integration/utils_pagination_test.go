@@ -0,0 +1,158 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ArtemVoronov/indefinite-studies-api/internal/db"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	PAGINATION_DEFAULT_LIMIT int = 50
+	// Guards page walks against endpoints that never return a short page
+	PAGINATION_MAX_PAGES int = 10000
+)
+
+type Page struct {
+	Count  int
+	Offset int
+	Limit  int
+	Data   []struct {
+		Id int
+	}
+}
+
+func (p Page) Ids() []int {
+	result := make([]int, 0, len(p.Data))
+	for _, item := range p.Data {
+		result = append(result, item.Id)
+	}
+	return result
+}
+
+func ParsePage(body string) (Page, error) {
+	var result Page
+	if err := json.Unmarshal([]byte(body), &result); err != nil {
+		return result, fmt.Errorf("unable to parse page '%s': %v", body, err)
+	}
+	return result, nil
+}
+
+type ListEndpoint struct {
+	Name  string
+	Table string
+	List  func(limit any, offset any) (int, string, error)
+}
+
+func CreateListEndpoints() []ListEndpoint {
+	return []ListEndpoint{
+		{Name: "GetNotes", Table: "notes", List: testHttpClient.GetNotes},
+		{Name: "GetTags", Table: "tags", List: testHttpClient.GetTags},
+		{Name: "GetTasks", Table: "tasks", List: testHttpClient.GetTasks},
+		{Name: "GetUsers", Table: "users", List: testHttpClient.GetUsers},
+	}
+}
+
+// Checks the envelope of a successful page: it echoes the request and never returns more than asked
+func AssertPageInvariants(t *testing.T, page Page, limit int, offset int) {
+	assert.Equal(t, len(page.Data), page.Count, "Count must be equal to the length of Data")
+	assert.Equal(t, offset, page.Offset)
+	assert.Equal(t, limit, page.Limit)
+	assert.LessOrEqual(t, len(page.Data), limit)
+}
+
+// Requests pages with the limit until a short page, returns ids in the order they were received
+func WalkPages(t *testing.T, endpoint ListEndpoint, limit int) []int {
+	var result []int
+	for offset, pages := 0, 0; pages < PAGINATION_MAX_PAGES; offset, pages = offset+limit, pages+1 {
+		httpStatusCode, body, err := endpoint.List(limit, offset)
+		assert.Nil(t, err)
+		if !assert.Equal(t, http.StatusOK, httpStatusCode, "%s(limit=%d, offset=%d): %s", endpoint.Name, limit, offset, body) {
+			return result
+		}
+		page, err := ParsePage(body)
+		if !assert.Nil(t, err) {
+			return result
+		}
+		AssertPageInvariants(t, page, limit, offset)
+		result = append(result, page.Ids()...)
+		if len(page.Data) < limit {
+			return result
+		}
+	}
+	t.Errorf("%s: more than %d pages with limit %d", endpoint.Name, PAGINATION_MAX_PAGES, limit)
+	return result
+}
+
+func FindDuplicates(ids []int) []int {
+	seen := make(map[int]bool)
+	var result []int
+	for _, id := range ids {
+		if seen[id] {
+			result = append(result, id)
+		}
+		seen[id] = true
+	}
+	return result
+}
+
+// Ids of expected that are absent in actual
+func FindGaps(expected []int, actual []int) []int {
+	present := make(map[int]bool)
+	for _, id := range actual {
+		present[id] = true
+	}
+	var result []int
+	for _, id := range expected {
+		if !present[id] {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+func IsStrictlyAscending(ids []int) bool {
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			return false
+		}
+	}
+	return true
+}
+
+func AssertNoDuplicatesOrGaps(t *testing.T, name string, expected []int, actual []int) {
+	assert.Empty(t, FindDuplicates(actual), "%s: duplicates", name)
+	assert.Empty(t, FindGaps(expected, actual), "%s: gaps", name)
+	assert.Empty(t, FindGaps(actual, expected), "%s: unexpected ids", name)
+}
+
+func GetIdsInDB(t *testing.T, table string) []int {
+	var result []int
+	db.TxVoid(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error {
+		rows, err := tx.QueryContext(ctx, "SELECT id FROM "+table+" ORDER BY id")
+		if err != nil {
+			assert.Nil(t, err)
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var id int
+			if err := rows.Scan(&id); err != nil {
+				assert.Nil(t, err)
+				return err
+			}
+			result = append(result, id)
+		}
+		assert.Nil(t, rows.Err())
+		return rows.Err()
+	})()
+	return result
+}
@@ -0,0 +1,187 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ArtemVoronov/indefinite-studies-api/internal/db/entities"
+)
+
+type SoftDeleteResource struct {
+	Path         string
+	Table        string
+	DeletedState string
+}
+
+func CreateSoftDeleteResources() []SoftDeleteResource {
+	return []SoftDeleteResource{
+		{Path: "/notes", Table: "notes", DeletedState: entities.NOTE_STATE_DELETED},
+		{Path: "/tags", Table: "tags", DeletedState: entities.TAG_STATE_DELETED},
+		{Path: "/tasks", Table: "tasks", DeletedState: entities.TASK_STATE_DELETED},
+		{Path: "/users", Table: "users", DeletedState: entities.USER_STATE_DELETED},
+	}
+}
+
+type SoftDeleteViolation struct {
+	Method         string
+	Url            string
+	HttpStatusCode int
+	Table          string
+	Id             int
+	Description    string
+}
+
+func (p SoftDeleteViolation) String() string {
+	return fmt.Sprintf("%s %s -> %d: %s '%s' id %d", p.Method, p.Url, p.HttpStatusCode, p.Description, p.Table, p.Id)
+}
+
+// Observes every call of the test client: rows that were in the deleted state before a GET or PUT
+// must not appear in its response and must not be updated successfully
+type SoftDeleteChecker struct {
+	mutex     sync.Mutex
+	resources []SoftDeleteResource
+	// Number of Disable calls not yet undone, they overlap e.g. in a load run with injected DB faults
+	disabled   int
+	violations []SoftDeleteViolation
+}
+
+var softDeleteChecker = NewSoftDeleteChecker(CreateSoftDeleteResources())
+
+func NewSoftDeleteChecker(resources []SoftDeleteResource) *SoftDeleteChecker {
+	return &SoftDeleteChecker{resources: resources}
+}
+
+func (p *SoftDeleteChecker) Observe(req *http.Request, serve func() (int, string, error)) (int, string, error) {
+	resource, id, ok := p.match(req)
+	if !ok || !HasDB() || (req.Method != http.MethodGet && req.Method != http.MethodPut) {
+		return serve()
+	}
+	deleted, err := p.deletedIds(resource)
+	if err != nil {
+		// Not a leak of the API, the request is served unchecked
+		return serve()
+	}
+
+	httpStatusCode, body, err := serve()
+	if err != nil || httpStatusCode < 200 || httpStatusCode >= 300 {
+		return httpStatusCode, body, err
+	}
+
+	violation := SoftDeleteViolation{Method: req.Method, Url: req.URL.RequestURI(), HttpStatusCode: httpStatusCode, Table: resource.Table}
+	switch {
+	case req.Method == http.MethodPut && deleted[id]:
+		violation.Id, violation.Description = id, "successful update of deleted"
+		p.add(violation)
+	case req.Method == http.MethodGet && id > 0 && deleted[id]:
+		violation.Id, violation.Description = id, "GET returns deleted"
+		p.add(violation)
+	case req.Method == http.MethodGet && id == 0:
+		page, err := ParsePage(body)
+		if err != nil {
+			return httpStatusCode, body, nil
+		}
+		for _, listed := range page.Ids() {
+			if deleted[listed] {
+				violation.Id, violation.Description = listed, "list contains deleted"
+				p.add(violation)
+			}
+		}
+	}
+	return httpStatusCode, body, nil
+}
+
+// Resource of the request and the id from its path, 0 for the collection itself
+func (p *SoftDeleteChecker) match(req *http.Request) (SoftDeleteResource, int, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.disabled > 0 {
+		return SoftDeleteResource{}, 0, false
+	}
+	path := strings.TrimSuffix(req.URL.Path, "/")
+	for _, resource := range p.resources {
+		if path == resource.Path {
+			return resource, 0, true
+		}
+		if !strings.HasPrefix(path, resource.Path+"/") {
+			continue
+		}
+		id, err := strconv.Atoi(strings.TrimPrefix(path, resource.Path+"/"))
+		if err != nil || id <= 0 {
+			return SoftDeleteResource{}, 0, false
+		}
+		return resource, id, true
+	}
+	return SoftDeleteResource{}, 0, false
+}
+
+func (p *SoftDeleteChecker) deletedIds(resource SoftDeleteResource) (map[int]bool, error) {
+	rows, err := HarnessDB().QueryContext(context.Background(), "SELECT id FROM "+resource.Table+" WHERE state = $1", resource.DeletedState)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[int]bool)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		result[id] = true
+	}
+	return result, rows.Err()
+}
+
+func (p *SoftDeleteChecker) add(violation SoftDeleteViolation) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.violations = append(p.violations, violation)
+}
+
+func (p *SoftDeleteChecker) Reset() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.violations = nil
+}
+
+// Turns the checker off, e.g. while measuring latencies, and returns a function that turns it back on
+// once every other caller of Disable has turned it back on as well
+func (p *SoftDeleteChecker) Disable() func() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.disabled++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			p.mutex.Lock()
+			defer p.mutex.Unlock()
+			p.disabled--
+		})
+	}
+}
+
+func (p *SoftDeleteChecker) Violations() []SoftDeleteViolation {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return append([]SoftDeleteViolation{}, p.violations...)
+}
+
+func AssertNoSoftDeleteViolations(t *testing.T) {
+	violations := softDeleteChecker.Violations()
+	if len(violations) == 0 {
+		return
+	}
+	var sb strings.Builder
+	for _, v := range violations {
+		sb.WriteString("\n  " + v.String())
+	}
+	t.Errorf("soft deleted rows leaked through the API:%s", sb.String())
+}
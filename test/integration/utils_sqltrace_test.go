@@ -0,0 +1,73 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-api/internal/db"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/sqltrace"
+)
+
+const SQL_TRACE_DRIVER_NAME string = "postgres-sqltrace"
+
+const SQL_TRACE_TX_TIMEOUT = 30 * time.Second
+
+// Statements of the API under test. The API opens its transactions with its own contexts,
+// so they are told apart from other statements only while nothing else runs: not for concurrent tests.
+func CaptureQueries(f func()) sqltrace.Statements {
+	return sqltrace.CollectShared(f)
+}
+
+// Like db.Tx, but the transaction runs with a context of its own capture, so concurrent tests do not mix in
+func TxWithQueries(f func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error)) (any, error, sqltrace.Statements) {
+	ctx, capture := sqltrace.Start(context.Background())
+	ctx, cancel := context.WithTimeout(ctx, SQL_TRACE_TX_TIMEOUT)
+	defer cancel()
+
+	tx, err := db.GetInstance().GetDB().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err, capture.Stop()
+	}
+	result, err := f(tx, ctx, cancel)
+	if err != nil {
+		tx.Rollback()
+		return result, err, capture.Stop()
+	}
+	err = tx.Commit()
+	return result, err, capture.Stop()
+}
+
+func TxVoidWithQueries(f func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) error) (error, sqltrace.Statements) {
+	_, err, statements := TxWithQueries(func(tx *sql.Tx, ctx context.Context, cancel context.CancelFunc) (any, error) {
+		return nil, f(tx, ctx, cancel)
+	})
+	return err, statements
+}
+
+// Fails if nothing was captured as well: a request that reads the DB always runs at least one statement,
+// so an empty capture means that the API doesn't use the tracing driver
+func AssertMaxQueries(t *testing.T, statements sqltrace.Statements, max int) bool {
+	count := len(statements.Queries())
+	if count == 0 {
+		t.Errorf("no statements were captured, check that the API uses the '%s' driver", SQL_TRACE_DRIVER_NAME)
+		return false
+	}
+	if count > max {
+		t.Errorf("expected at most %d statements, actual: %d\n%s", max, count, statements.Report())
+		return false
+	}
+	return true
+}
+
+func AssertNoRepeatedQueries(t *testing.T, statements sqltrace.Statements) bool {
+	if len(statements.Repeated()) > 0 {
+		t.Errorf("repeated statements found\n%s", statements.Report())
+		return false
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"os"
+	"time"
+
+	"github.com/ArtemVoronov/indefinite-studies-api/internal/api"
+	"github.com/ArtemVoronov/indefinite-studies-api/internal/db/entities"
+	"github.com/ArtemVoronov/indefinite-studies-qa-service/internal/stub"
+	utilsapi "github.com/ArtemVoronov/indefinite-studies-utils/pkg/api"
+)
+
+const (
+	TARGET_STUB string = "stub"
+	// Lifetime of both tokens in .env.test, the auth tests wait for the tokens to expire
+	STUB_TOKEN_TTL time.Duration = 10 * time.Second
+)
+
+// QA_TARGET=stub runs the suite against the in-memory fake (see internal/stub) instead of the API,
+// so the fake is checked by the same assertions. Tests and subtests that query the DB are skipped.
+var stubServer *stub.Server
+
+func IsStubTarget() bool {
+	return os.Getenv("QA_TARGET") == TARGET_STUB
+}
+
+func SetupStub() {
+	if IsStubTarget() {
+		stubServer = stub.New(CreateStubConfig())
+	}
+}
+
+// The constants of the API, so the stub answers with exactly the texts the tests expect
+func CreateStubConfig() stub.Config {
+	config := stub.DefaultConfig()
+	config.Messages.PageNotFound = api.PAGE_NOT_FOUND
+	config.Messages.IdWrongFormat = api.ERROR_ID_WRONG_FORMAT
+	config.Messages.ParsingBodyJson = api.ERROR_MESSAGE_PARSING_BODY_JSON
+	config.Messages.Done = api.DONE
+	config.Messages.DuplicateFound = api.DUPLICATE_FOUND
+	config.Messages.DeleteViaPostForbidden = api.DELETE_VIA_POST_REQUEST_IS_FODBIDDEN
+	config.Messages.DeleteViaPutForbidden = api.DELETE_VIA_PUT_REQUEST_IS_FODBIDDEN
+	config.Messages.WrongPasswordOrEmail = api.ERROR_WRONG_PASSWORD_OR_EMAIL
+	config.Messages.TokenIsExpired = utilsapi.ERROR_TOKEN_IS_EXPIRED
+	config.AccessTokenTTL = STUB_TOKEN_TTL
+	config.RefreshTokenTTL = STUB_TOKEN_TTL
+	config.Enums = stub.Enums{
+		TaskStates:       entities.GetPossibleTaskStates(),
+		TagStates:        entities.GetPossibleTagStates(),
+		UserStates:       entities.GetPossibleUserStates(),
+		NoteStates:       entities.GetPossibleNoteStates(),
+		UserRoles:        entities.GetPossibleUserRoles(),
+		TaskDeletedState: entities.TASK_STATE_DELETED,
+		TagDeletedState:  entities.TAG_STATE_DELETED,
+		UserDeletedState: entities.USER_STATE_DELETED,
+		NoteDeletedState: entities.NOTE_STATE_DELETED,
+	}
+	return config
+}